@@ -28,6 +28,7 @@ import (
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
 	"k8s.io/component-base/config"
 	"k8s.io/component-base/config/options"
 	klog "k8s.io/klog/v2"
@@ -51,6 +52,7 @@ import (
 	configv1 "github.com/openshift/api/config/v1"
 	mapiv1 "github.com/openshift/api/machine/v1"
 	mapiv1beta1 "github.com/openshift/api/machine/v1beta1"
+	operatorv1alpha1 "github.com/openshift/cluster-capi-operator/pkg/apis/operator/v1alpha1"
 	"github.com/openshift/cluster-capi-operator/pkg/controllers"
 	"github.com/openshift/cluster-capi-operator/pkg/controllers/capiinstaller"
 	"github.com/openshift/cluster-capi-operator/pkg/controllers/corecluster"
@@ -82,6 +84,7 @@ func initScheme(scheme *runtime.Scheme) {
 	utilruntime.Must(vspherev1.AddToScheme(scheme))
 	utilruntime.Must(mapiv1.AddToScheme(scheme))
 	utilruntime.Must(mapiv1beta1.AddToScheme(scheme))
+	utilruntime.Must(operatorv1alpha1.AddToScheme(scheme))
 }
 
 //nolint:funlen
@@ -114,6 +117,11 @@ func main() {
 		defaultImagesLocation,
 		"The location of images file to use by operator for managed CAPI binaries.",
 	)
+	additionalProvidersFile := flag.String(
+		"additional-providers-yaml",
+		"",
+		"The location of the file listing the CAPI providers to install alongside the core and infrastructure providers.",
+	)
 	webhookPort := flag.Int(
 		"webhook-port",
 		9443,
@@ -142,21 +150,34 @@ func main() {
 	capiflags.AddManagerOptions(pflag.CommandLine, &capiManagerOptions)
 	pflag.Parse()
 
-	if err := setFeatureGatesEnvVars(); err != nil {
-		klog.Error(err, "unable to set feature gates environment variables")
-		os.Exit(1)
-	}
-
 	if logToStderr != nil {
 		klog.LogToStderr(*logToStderr)
 	}
 
-	_, diagnosticsOpts, err := capiflags.GetManagerOptions(capiManagerOptions)
+	cfg := ctrl.GetConfigOrDie()
+
+	if err := applyClusterTLSProfile(cfg, scheme, &capiManagerOptions); err != nil {
+		klog.Error(err, "unable to get cluster TLS profile")
+		os.Exit(1)
+	}
+
+	tlsOpts, diagnosticsOpts, err := capiflags.GetManagerOptions(capiManagerOptions)
 	if err != nil {
 		klog.Error(err, "unable to get manager options")
 		os.Exit(1)
 	}
 
+	// The servers follow the changes of the cluster TLS profile once the manager is started.
+	tlsProfileReloader := &util.TLSProfileReloader{
+		KeepMinVersion:   pflag.CommandLine.Changed("tls-min-version"),
+		KeepCipherSuites: pflag.CommandLine.Changed("tls-cipher-suites"),
+	}
+
+	tlsOpts = append(tlsOpts, tlsProfileReloader.TLSOpt)
+	if diagnosticsOpts.SecureServing {
+		diagnosticsOpts.TLSOpts = tlsOpts
+	}
+
 	syncPeriod := 10 * time.Minute
 
 	cacheOpts := cache.Options{
@@ -168,8 +189,6 @@ func main() {
 		SyncPeriod: &syncPeriod,
 	}
 
-	cfg := ctrl.GetConfigOrDie()
-
 	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
 		Scheme:                  scheme,
 		Metrics:                 *diagnosticsOpts,
@@ -184,6 +203,7 @@ func main() {
 		WebhookServer: crwebhook.NewServer(crwebhook.Options{
 			Port:    *webhookPort,
 			CertDir: *webhookCertDir,
+			TLSOpts: tlsOpts,
 		}),
 	})
 	if err != nil {
@@ -191,6 +211,12 @@ func main() {
 		os.Exit(1)
 	}
 
+	tlsProfileReloader.Client = mgr.GetClient()
+	if err := tlsProfileReloader.SetupWithManager(mgr); err != nil {
+		klog.Error(err, "unable to create controller", "controller", "TLSProfileReloader")
+		os.Exit(1)
+	}
+
 	applyClient, err := kubernetes.NewForConfig(cfg)
 	if err != nil {
 		klog.Error(err, "unable to set up apply client")
@@ -221,7 +247,7 @@ func main() {
 		os.Exit(1)
 	}
 
-	setupPlatformReconcilers(mgr, infra, platform, containerImages, applyClient, apiextensionsClient, *managedNamespace)
+	setupPlatformReconcilers(mgr, infra, platform, containerImages, *additionalProvidersFile, applyClient, apiextensionsClient, *managedNamespace)
 
 	// +kubebuilder:scaffold:builder
 
@@ -252,15 +278,15 @@ func getClusterOperatorStatusClient(mgr manager.Manager, controller string, mana
 	}
 }
 
-func setupPlatformReconcilers(mgr manager.Manager, infra *configv1.Infrastructure, platform configv1.PlatformType, containerImages map[string]string, applyClient *kubernetes.Clientset, apiextensionsClient *apiextensionsclient.Clientset, managedNamespace string) {
+func setupPlatformReconcilers(mgr manager.Manager, infra *configv1.Infrastructure, platform configv1.PlatformType, containerImages map[string]string, providersFile string, applyClient *kubernetes.Clientset, apiextensionsClient *apiextensionsclient.Clientset, managedNamespace string) {
 	// Only setup reconcile controllers and webhooks when the platform is supported.
 	// This avoids unnecessary CAPI providers discovery, installs and reconciles when the platform is not supported.
 	switch platform {
 	case configv1.AWSPlatformType:
-		setupReconcilers(mgr, infra, platform, &awsv1.AWSCluster{}, containerImages, applyClient, apiextensionsClient, managedNamespace)
+		setupReconcilers(mgr, infra, platform, &awsv1.AWSCluster{}, containerImages, providersFile, applyClient, apiextensionsClient, managedNamespace)
 		setupWebhooks(mgr)
 	case configv1.GCPPlatformType:
-		setupReconcilers(mgr, infra, platform, &gcpv1.GCPCluster{}, containerImages, applyClient, apiextensionsClient, managedNamespace)
+		setupReconcilers(mgr, infra, platform, &gcpv1.GCPCluster{}, containerImages, providersFile, applyClient, apiextensionsClient, managedNamespace)
 		setupWebhooks(mgr)
 	case configv1.AzurePlatformType:
 		azureCloudEnvironment := getAzureCloudEnvironment(infra.Status.PlatformStatus)
@@ -268,17 +294,17 @@ func setupPlatformReconcilers(mgr manager.Manager, infra *configv1.Infrastructur
 			klog.Infof("Detected Azure Cloud Environment %q on platform %q is not supported, skipping capi controllers setup", azureCloudEnvironment, platform)
 			setupUnsupportedController(mgr, managedNamespace)
 		} else {
-			setupReconcilers(mgr, infra, platform, &azurev1.AzureCluster{}, containerImages, applyClient, apiextensionsClient, managedNamespace)
+			setupReconcilers(mgr, infra, platform, &azurev1.AzureCluster{}, containerImages, providersFile, applyClient, apiextensionsClient, managedNamespace)
 			setupWebhooks(mgr)
 		}
 	case configv1.PowerVSPlatformType:
-		setupReconcilers(mgr, infra, platform, &ibmpowervsv1.IBMPowerVSCluster{}, containerImages, applyClient, apiextensionsClient, managedNamespace)
+		setupReconcilers(mgr, infra, platform, &ibmpowervsv1.IBMPowerVSCluster{}, containerImages, providersFile, applyClient, apiextensionsClient, managedNamespace)
 		setupWebhooks(mgr)
 	case configv1.VSpherePlatformType:
-		setupReconcilers(mgr, infra, platform, &vspherev1.VSphereCluster{}, containerImages, applyClient, apiextensionsClient, managedNamespace)
+		setupReconcilers(mgr, infra, platform, &vspherev1.VSphereCluster{}, containerImages, providersFile, applyClient, apiextensionsClient, managedNamespace)
 		setupWebhooks(mgr)
 	case configv1.OpenStackPlatformType:
-		setupReconcilers(mgr, infra, platform, &openstackv1.OpenStackCluster{}, containerImages, applyClient, apiextensionsClient, managedNamespace)
+		setupReconcilers(mgr, infra, platform, &openstackv1.OpenStackCluster{}, containerImages, providersFile, applyClient, apiextensionsClient, managedNamespace)
 		setupWebhooks(mgr)
 	default:
 		klog.Infof("Detected platform %q is not supported, skipping capi controllers setup", platform)
@@ -286,7 +312,7 @@ func setupPlatformReconcilers(mgr manager.Manager, infra *configv1.Infrastructur
 	}
 }
 
-func setupReconcilers(mgr manager.Manager, infra *configv1.Infrastructure, platform configv1.PlatformType, infraClusterObject client.Object, containerImages map[string]string, applyClient *kubernetes.Clientset, apiextensionsClient *apiextensionsclient.Clientset, managedNamespace string) {
+func setupReconcilers(mgr manager.Manager, infra *configv1.Infrastructure, platform configv1.PlatformType, infraClusterObject client.Object, containerImages map[string]string, providersFile string, applyClient *kubernetes.Clientset, apiextensionsClient *apiextensionsclient.Clientset, managedNamespace string) {
 	if err := (&corecluster.CoreClusterReconciler{
 		ClusterOperatorStatusClient: getClusterOperatorStatusClient(mgr, "cluster-capi-operator-cluster-resource-controller", managedNamespace),
 		Cluster:                     &clusterv1.Cluster{},
@@ -320,8 +346,10 @@ func setupReconcilers(mgr manager.Manager, infra *configv1.Infrastructure, platf
 		Images:                      containerImages,
 		RestCfg:                     mgr.GetConfig(),
 		Platform:                    platform,
+		Infra:                       infra,
 		ApplyClient:                 applyClient,
 		APIExtensionsClient:         apiextensionsClient,
+		ProvidersFile:               providersFile,
 	}).SetupWithManager(mgr); err != nil {
 		klog.Error(err, "unable to create capi installer controller", "controller", "CAPIInstaller")
 		os.Exit(1)
@@ -347,17 +375,25 @@ func setupWebhooks(mgr ctrl.Manager) {
 	}
 }
 
-// setFeatureGatesEnvVars sets the explicit values for the listed feature gates in the environment.
-// These will then be loaded by envsubst and templated into the applied CAPI manifests.
-func setFeatureGatesEnvVars() error {
-	featureGates := map[string]string{
-		"EXP_BOOTSTRAP_FORMAT_IGNITION": "true",
+// applyClusterTLSProfile sets the TLS options of the operator webhook and metrics servers
+// from the cluster TLS profile on startup, unless they were set explicitly on the command line.
+func applyClusterTLSProfile(cfg *rest.Config, scheme *runtime.Scheme, managerOptions *capiflags.ManagerOptions) error {
+	cl, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return fmt.Errorf("unable to set up client: %w", err)
 	}
 
-	for k, v := range featureGates {
-		if err := os.Setenv(k, v); err != nil {
-			return fmt.Errorf("error setting environment variable: %s: %w", k, err)
-		}
+	profile, err := util.GetTLSProfile(context.Background(), cl)
+	if err != nil {
+		return fmt.Errorf("unable to get TLS profile: %w", err)
+	}
+
+	if !pflag.CommandLine.Changed("tls-min-version") && profile.MinVersion != "" {
+		managerOptions.TLSMinVersion = profile.MinVersion
+	}
+
+	if !pflag.CommandLine.Changed("tls-cipher-suites") {
+		managerOptions.TLSCipherSuites = profile.CipherSuites
 	}
 
 	return nil
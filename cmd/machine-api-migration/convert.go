@@ -0,0 +1,156 @@
+// Copyright 2024 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/openshift/cluster-capi-operator/pkg/controllers"
+	"github.com/openshift/cluster-capi-operator/pkg/conversion/offline"
+)
+
+const (
+	// convertCommand is the name of the subcommand converting manifests without a cluster.
+	convertCommand = "convert"
+
+	// convertExitConversionFailed is returned when a resource failed to convert,
+	// or reported warnings with --fail-on-warnings.
+	convertExitConversionFailed = 1
+
+	// convertExitUsage is returned when the arguments or manifests are invalid.
+	convertExitUsage = 2
+)
+
+// errInfrastructureRequired is returned when the convert subcommand is run without an Infrastructure.
+var errInfrastructureRequired = errors.New("--infrastructure is required")
+
+// runConvert runs the convert subcommand. It reads MAPI and CAPI manifests from the given files,
+// or stdin when no file or - is given, and writes the converted resources to stdout.
+// Warnings and errors of the conversion are written to stderr.
+func runConvert(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet(convertCommand, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		usage := fmt.Sprintf("Usage: %s %s --infrastructure=<file> [flags] [file...]\n\n", os.Args[0], convertCommand) +
+			"Converts MAPI Machines and MachineSets to CAPI, and CAPI Machines, MachineSets and MachineDeployments to MAPI.\n" +
+			"The infrastructure resources referenced by CAPI resources must be part of the input.\n\n"
+
+		printf(flags.Output(), "%s", usage)
+		flags.PrintDefaults()
+	}
+
+	infrastructure := flags.String("infrastructure", "", "Path to a YAML or JSON file holding the cluster Infrastructure.")
+	capiNamespace := flags.String("capi-namespace", controllers.DefaultManagedNamespace, "The namespace of the converted CAPI resources.")
+	mapiNamespace := flags.String("mapi-namespace", controllers.DefaultMAPIManagedNamespace, "The namespace of the converted MAPI resources.")
+	failOnWarnings := flags.Bool("fail-on-warnings", false, "Fail when a conversion reports warnings.")
+
+	if err := flags.Parse(args); err != nil {
+		return convertExitUsage
+	}
+
+	results, err := convert(*infrastructure, *capiNamespace, *mapiNamespace, flags.Args(), stdin)
+	if err != nil {
+		printf(stderr, "error: %v\n", err)
+		return convertExitUsage
+	}
+
+	if err := offline.WriteYAML(stdout, results); err != nil {
+		printf(stderr, "error: %v\n", err)
+		return convertExitUsage
+	}
+
+	failed := false
+
+	for _, result := range results {
+		for _, warning := range result.Report.Warnings() {
+			printf(stderr, "%s: warning: %s\n", result.Resource, warning)
+
+			failed = failed || *failOnWarnings
+		}
+
+		if result.Err != nil {
+			printf(stderr, "%s: error: %v\n", result.Resource, result.Err)
+
+			failed = true
+		}
+	}
+
+	if failed {
+		return convertExitConversionFailed
+	}
+
+	return 0
+}
+
+// convert reads the Infrastructure and the manifests, and converts the manifests.
+func convert(infrastructure, capiNamespace, mapiNamespace string, files []string, stdin io.Reader) ([]offline.Result, error) {
+	if infrastructure == "" {
+		return nil, errInfrastructureRequired
+	}
+
+	infraManifest, err := os.ReadFile(infrastructure) //nolint:gosec // Reading the given files is the purpose of the command.
+	if err != nil {
+		return nil, fmt.Errorf("failed to read infrastructure file: %w", err)
+	}
+
+	infra, err := offline.ReadInfrastructure(bytes.NewReader(infraManifest))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read infrastructure: %w", err)
+	}
+
+	converter, err := offline.NewConverter(infra, capiNamespace, mapiNamespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create converter: %w", err)
+	}
+
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	// Read every file as a single stream, so that CAPI resources may reference infrastructure resources from other files.
+	readers := []io.Reader{}
+
+	for _, file := range files {
+		if file == "-" {
+			readers = append(readers, stdin, strings.NewReader("\n---\n"))
+			continue
+		}
+
+		manifests, err := os.ReadFile(file) //nolint:gosec // Reading the given files is the purpose of the command.
+		if err != nil {
+			return nil, fmt.Errorf("failed to read manifests file: %w", err)
+		}
+
+		readers = append(readers, bytes.NewReader(manifests), strings.NewReader("\n---\n"))
+	}
+
+	results, err := converter.Convert(io.MultiReader(readers...))
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert manifests: %w", err)
+	}
+
+	return results, nil
+}
+
+// printf writes a formatted message to the output of the command, ignoring write errors
+// as there is no other output to report them to.
+func printf(w io.Writer, format string, args ...any) {
+	_, _ = fmt.Fprintf(w, format, args...)
+}
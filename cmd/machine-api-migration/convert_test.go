@@ -0,0 +1,110 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	configv1 "github.com/openshift/api/config/v1"
+	capibuilder "github.com/openshift/cluster-api-actuator-pkg/testutils/resourcebuilder/cluster-api/core/v1beta1"
+	capabuilder "github.com/openshift/cluster-api-actuator-pkg/testutils/resourcebuilder/cluster-api/infrastructure/v1beta2"
+	configbuilder "github.com/openshift/cluster-api-actuator-pkg/testutils/resourcebuilder/config/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	capav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	capiv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+var _ = Describe("convert", func() {
+	const capiNamespace = "openshift-cluster-api"
+
+	var (
+		infrastructureFile string
+		stdout, stderr     *bytes.Buffer
+	)
+
+	toYAML := func(objs ...client.Object) string {
+		docs := []string{}
+
+		for _, obj := range objs {
+			out, err := yaml.Marshal(obj)
+			Expect(err).ToNot(HaveOccurred())
+
+			docs = append(docs, string(out))
+		}
+
+		return strings.Join(docs, "---\n")
+	}
+
+	BeforeEach(func() {
+		infra := configbuilder.Infrastructure().AsAWS("cluster", "us-east-1").WithInfrastructureName("cluster-foo").Build()
+		infra.SetGroupVersionKind(configv1.GroupVersion.WithKind("Infrastructure"))
+
+		infrastructureFile = filepath.Join(GinkgoT().TempDir(), "infrastructure.yaml")
+		Expect(os.WriteFile(infrastructureFile, []byte(toYAML(infra)), 0o600)).To(Succeed())
+
+		stdout, stderr = &bytes.Buffer{}, &bytes.Buffer{}
+	})
+
+	It("should fail with a usage error without an Infrastructure", func() {
+		Expect(runConvert(nil, strings.NewReader(""), stdout, stderr)).To(Equal(convertExitUsage))
+		Expect(stderr.String()).To(ContainSubstring("--infrastructure is required"))
+	})
+
+	It("should report an unsupported field of a CAPI MachineSet as an error", func() {
+		template := capabuilder.AWSMachineTemplate().
+			WithName("foo").
+			WithNamespace(capiNamespace).
+			WithInstanceType("m5.large").
+			WithImageLookupOrg("123456789012").
+			Build()
+		template.SetGroupVersionKind(capav1.GroupVersion.WithKind("AWSMachineTemplate"))
+
+		machineSet := capibuilder.MachineSet().
+			WithName("foo").
+			WithNamespace(capiNamespace).
+			WithClusterName("cluster-foo").
+			WithTemplate(capiv1.MachineTemplateSpec{
+				Spec: capiv1.MachineSpec{
+					ClusterName: "cluster-foo",
+					InfrastructureRef: corev1.ObjectReference{
+						APIVersion: capav1.GroupVersion.String(),
+						Kind:       "AWSMachineTemplate",
+						Name:       template.GetName(),
+					},
+				},
+			}).
+			Build()
+		machineSet.SetGroupVersionKind(capiv1.GroupVersion.WithKind("MachineSet"))
+
+		awsCluster := capabuilder.AWSCluster().WithName("cluster-foo").WithNamespace(capiNamespace).Build()
+		awsCluster.SetGroupVersionKind(capav1.GroupVersion.WithKind("AWSCluster"))
+
+		stdin := strings.NewReader(toYAML(machineSet, template, awsCluster))
+
+		Expect(runConvert([]string{"--infrastructure", infrastructureFile}, stdin, stdout, stderr)).To(Equal(convertExitConversionFailed))
+		Expect(stderr.String()).To(ContainSubstring("MachineSet openshift-cluster-api/foo: error:"))
+		Expect(stderr.String()).To(ContainSubstring("imageLookupOrg is not supported"))
+	})
+})
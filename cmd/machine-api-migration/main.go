@@ -25,11 +25,16 @@ import (
 	mapiv1beta1 "github.com/openshift/api/machine/v1beta1"
 	configv1client "github.com/openshift/client-go/config/clientset/versioned"
 	configinformers "github.com/openshift/client-go/config/informers/externalversions"
+	migrationv1alpha1 "github.com/openshift/cluster-capi-operator/pkg/apis/migration/v1alpha1"
 	"github.com/openshift/cluster-capi-operator/pkg/controllers"
+	"github.com/openshift/cluster-capi-operator/pkg/controllers/machineapimigration"
 	"github.com/openshift/cluster-capi-operator/pkg/controllers/machinesetsync"
 	"github.com/openshift/cluster-capi-operator/pkg/controllers/machinesync"
+	"github.com/openshift/cluster-capi-operator/pkg/controllers/migrationreadiness"
+	"github.com/openshift/cluster-capi-operator/pkg/controllers/syncmetrics"
+	"github.com/openshift/cluster-capi-operator/pkg/conversion"
 	"github.com/openshift/cluster-capi-operator/pkg/util"
-	capav1beta2 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	"github.com/openshift/cluster-capi-operator/pkg/webhook"
 	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
 
 	"github.com/openshift/api/features"
@@ -47,6 +52,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+	crwebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
 var (
@@ -61,12 +68,20 @@ func initScheme(scheme *runtime.Scheme) {
 	// TODO(joelspeed): Add additional schemes here once we work out exactly which will be needed.
 	utilruntime.Must(mapiv1beta1.AddToScheme(scheme))
 	utilruntime.Must(configv1.AddToScheme(scheme))
-	utilruntime.Must(capav1beta2.AddToScheme(scheme))
 	utilruntime.Must(capiv1beta1.AddToScheme(scheme))
+	utilruntime.Must(migrationv1alpha1.AddToScheme(scheme))
+
+	for _, platform := range conversion.Platforms() {
+		utilruntime.Must(platform.AddToScheme(scheme))
+	}
 }
 
 //nolint:funlen
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == convertCommand {
+		os.Exit(runConvert(os.Args[2:], os.Stdin, os.Stdout, os.Stderr))
+	}
+
 	scheme := runtime.NewScheme()
 	initScheme(scheme)
 
@@ -94,6 +109,22 @@ func main() {
 		controllers.DefaultMAPIManagedNamespace,
 		"The namespace to watch for MAPI resources.",
 	)
+	forwardReplicas := flag.Bool(
+		"forward-replicas",
+		false,
+		"Forward changes of the replicas of non-authoritative MachineSets to the authoritative MachineSets.",
+	)
+
+	webhookPort := flag.Int(
+		"webhook-port",
+		9444,
+		"The port for the webhook server to listen on.",
+	)
+	webhookCertDir := flag.String(
+		"webhook-cert-dir",
+		"/tmp/k8s-webhook-server/serving-certs/",
+		"Webhook cert dir, only used when webhook-port is specified.",
+	)
 
 	logToStderr := flag.Bool(
 		"logtostderr",
@@ -147,6 +178,10 @@ func main() {
 		RetryPeriod:             &leaderElectionConfig.RetryPeriod.Duration,
 		RenewDeadline:           &leaderElectionConfig.RenewDeadline.Duration,
 		Cache:                   cacheOpts,
+		WebhookServer: crwebhook.NewServer(crwebhook.Options{
+			Port:    *webhookPort,
+			CertDir: *webhookCertDir,
+		}),
 	})
 	if err != nil {
 		klog.Error(err, "unable to create manager")
@@ -169,11 +204,7 @@ func main() {
 		os.Exit(1)
 	}
 
-	if !currentFeatureGates.Enabled(features.FeatureGateMachineAPIMigration) {
-		klog.Info("MachineAPIMigration feature gate is not enabled, nothing to do. Waiting for termination signal.")
-		<-stop.Done()
-		os.Exit(0)
-	}
+	migrationEnabled := currentFeatureGates.Enabled(features.FeatureGateMachineAPIMigration)
 
 	infraClient, err := client.New(cfg, client.Options{Scheme: scheme})
 	if err != nil {
@@ -193,19 +224,6 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Currently we only plan to support AWS, so all others are a noop until they're implemented.
-	switch provider {
-	case configv1.AWSPlatformType:
-		klog.Info("MachineAPIMigration: starting AWS controllers")
-
-	default:
-		klog.Infof("MachineAPIMigration not implemented for platform %s, nothing to do. Waiting for termination signal.", provider)
-		<-stop.Done()
-		os.Exit(0)
-	}
-
-	// +kubebuilder:scaffold:builder
-
 	if err := mgr.AddHealthzCheck("health", healthz.Ping); err != nil {
 		klog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -216,6 +234,38 @@ func main() {
 		os.Exit(1)
 	}
 
+	// The webhook is always served, so that changes of the authoritative API are denied
+	// when the migration is not enabled or not supported on the platform.
+	authoritativeAPIWebhook := webhook.AuthoritativeAPIWebhook{
+		Infra:            infra,
+		Platform:         provider,
+		MigrationEnabled: migrationEnabled,
+	}
+
+	if err := authoritativeAPIWebhook.SetupWebhookWithManager(mgr); err != nil {
+		klog.Error(err, "unable to create webhook", "webhook", "AuthoritativeAPI")
+		os.Exit(1)
+	}
+
+	if !migrationEnabled {
+		klog.Info("MachineAPIMigration feature gate is not enabled, only serving webhooks.")
+		startManager(stop, mgr)
+
+		return
+	}
+
+	// Only platforms with a registered conversion are supported, all others are a noop until they're implemented.
+	if !conversion.IsPlatformSupported(provider) {
+		klog.Infof("MachineAPIMigration not implemented for platform %s, only serving webhooks.", provider)
+		startManager(stop, mgr)
+
+		return
+	}
+
+	klog.Infof("MachineAPIMigration: starting %s controllers", provider)
+
+	// +kubebuilder:scaffold:builder
+
 	machineSyncReconciler := machinesync.MachineSyncReconciler{
 		Infra:    infra,
 		Platform: provider,
@@ -230,8 +280,9 @@ func main() {
 	}
 
 	machineSetSyncReconciler := machinesetsync.MachineSetSyncReconciler{
-		Platform: provider,
-		Infra:    infra,
+		Platform:        provider,
+		Infra:           infra,
+		ForwardReplicas: *forwardReplicas,
 
 		MAPINamespace: *mapiManagedNamespace,
 		CAPINamespace: *capiManagedNamespace,
@@ -242,9 +293,39 @@ func main() {
 		os.Exit(1)
 	}
 
+	// The sync metrics are served by the metrics server configured by the diagnostics options.
+	ctrlmetrics.Registry.MustRegister(syncmetrics.NewObjectsCollector(mgr.GetClient(), *mapiManagedNamespace))
+
+	migrationReadinessReconciler := migrationreadiness.MigrationReadinessReconciler{
+		Platform: provider,
+		Infra:    infra,
+
+		MAPINamespace: *mapiManagedNamespace,
+		CAPINamespace: *capiManagedNamespace,
+	}
+
+	if err := migrationReadinessReconciler.SetupWithManager(mgr); err != nil {
+		klog.Error(err, "failed to set up migration readiness reconciler with manager")
+		os.Exit(1)
+	}
+
+	machineAPIMigrationReconciler := machineapimigration.MachineAPIMigrationReconciler{
+		MAPINamespace: *mapiManagedNamespace,
+	}
+
+	if err := machineAPIMigrationReconciler.SetupWithManager(mgr); err != nil {
+		klog.Error(err, "failed to set up machine API migration reconciler with manager")
+		os.Exit(1)
+	}
+
+	startManager(stop, mgr)
+}
+
+// startManager starts the manager and blocks until the context is done.
+func startManager(ctx context.Context, mgr ctrl.Manager) {
 	klog.Info("Starting manager")
 
-	if err := mgr.Start(stop); err != nil {
+	if err := mgr.Start(ctx); err != nil {
 		klog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
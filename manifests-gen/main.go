@@ -23,6 +23,8 @@ var (
 	providerName    = flag.String("provider-name", "", "name of the provider")
 	providerType    = flag.String("provider-type", "", "type of the provider")
 	providerVersion = flag.String("provider-version", "", "version of the provider")
+	imageKey        = flag.String("image-key", "", "key of the provider manager image in the images ConfigMap, defaults to a key derived from the provider name")
+	managerCommand  = flag.String("manager-command", "", "entrypoint of the provider manager container, defaults to the entrypoint of the known provider images, or to the entrypoint of the provider manifests")
 	projDir         string
 
 	scheme          = runtime.NewScheme()
@@ -54,6 +56,16 @@ func main() {
 		Version: *providerVersion,
 	}
 
+	p.ImageKey = *imageKey
+	if p.ImageKey == "" {
+		p.ImageKey = defaultImageKey(p.Name)
+	}
+
+	p.ManagerCommand = *managerCommand
+	if p.ManagerCommand == "" {
+		p.ManagerCommand = defaultManagerCommand(p.Name)
+	}
+
 	if err := importProvider(p); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
@@ -29,14 +29,20 @@ const (
 	// customizedComponentsFilename is a name for file containing customized infrastructure components.
 	// This file helps with code review as it is always uncompressed unlike the components configMap.
 	customizedComponentsFilename = "infrastructure-components-openshift.yaml"
+	// imageKeyAnnotationKey is the annotation of the provider ConfigMap holding the key of the manager image in the images ConfigMap.
+	imageKeyAnnotationKey = "cluster-api.openshift.io/manager-image-key"
+	// managerCommandAnnotationKey is the annotation of the provider ConfigMap holding the entrypoint of the manager container.
+	managerCommandAnnotationKey = "cluster-api.openshift.io/manager-command"
 )
 
 type provider struct {
-	Name       string                    `json:"name"`
-	Type       clusterctlv1.ProviderType `json:"type"`
-	Version    string                    `json:"version"`
-	components repository.Components
-	metadata   []byte
+	Name           string                    `json:"name"`
+	Type           clusterctlv1.ProviderType `json:"type"`
+	Version        string                    `json:"version"`
+	ImageKey       string                    `json:"imageKey"`
+	ManagerCommand string                    `json:"managerCommand"`
+	components     repository.Components
+	metadata       []byte
 }
 
 // loadComponents loads components from the given provider.
@@ -128,8 +134,11 @@ func (p *provider) writeProviderCustomizedComponents(resourceMap map[resourceKey
 
 // writeProviderComponentsConfigmap allows to write provider components to the provider (transport) ConfigMap.
 func (p *provider) writeProviderComponentsConfigmap(fileName string, objs []unstructured.Unstructured) error {
-	annotations := openshiftAnnotations
-	annotations[featureSetAnnotationKey] = featureSetAnnotationValue
+	annotations := mergeMaps(openshiftAnnotations, map[string]string{
+		featureSetAnnotationKey:     featureSetAnnotationValue,
+		imageKeyAnnotationKey:       p.ImageKey,
+		managerCommandAnnotationKey: p.ManagerCommand,
+	})
 
 	cm := corev1.ConfigMap{
 		TypeMeta: metav1.TypeMeta{
@@ -211,6 +220,33 @@ func compressToZstd(data []byte) (bytes.Buffer, error) {
 	return compressed, nil
 }
 
+// defaultImageKey returns the key of the manager image of a provider in the images ConfigMap,
+// following the naming of the OpenShift provider images.
+func defaultImageKey(name string) string {
+	switch name {
+	case coreCAPIProvider:
+		return "cluster-capi-controllers"
+	case powerVSProvider:
+		return ibmCloudProvider + "-cluster-api-controllers"
+	default:
+		return name + "-cluster-api-controllers"
+	}
+}
+
+// defaultManagerCommand returns the entrypoint of the manager container of a provider, matching the fallback of the
+// operator for provider ConfigMaps without the command annotation. It is empty for the providers whose image binary
+// is not known, so that the entrypoint of the provider manifests is kept; set --manager-command for them when needed.
+func defaultManagerCommand(name string) string {
+	switch name {
+	case coreCAPIProvider:
+		return "./bin/cluster-api-controller-manager"
+	case "aws", "gcp", ibmCloudProvider:
+		return "./bin/cluster-api-provider-" + name + "-controller-manager"
+	default:
+		return ""
+	}
+}
+
 func importProvider(p provider) error {
 	fmt.Printf("Processing provider %s\n", p.Name)
 
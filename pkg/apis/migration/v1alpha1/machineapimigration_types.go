@@ -0,0 +1,179 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha1
+
+import (
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MigrationPhase is the phase of a MachineAPIMigration.
+// +kubebuilder:validation:Enum=Pending;Progressing;Paused;Completed
+type MigrationPhase string
+
+const (
+	// MigrationPhasePending denotes a migration which did not flip any MachineSet yet.
+	MigrationPhasePending MigrationPhase = "Pending"
+
+	// MigrationPhaseProgressing denotes a migration flipping the MachineSets in batches.
+	MigrationPhaseProgressing MigrationPhase = "Progressing"
+
+	// MigrationPhasePaused denotes a migration which stopped flipping MachineSets,
+	// either because it is paused or because a MachineSet failed to migrate.
+	MigrationPhasePaused MigrationPhase = "Paused"
+
+	// MigrationPhaseCompleted denotes a migration whose MachineSets all reached the target authority.
+	MigrationPhaseCompleted MigrationPhase = "Completed"
+)
+
+// ObjectPhase is the migration phase of a single MachineSet.
+// +kubebuilder:validation:Enum=Pending;Migrating;Migrated;Failed
+type ObjectPhase string
+
+const (
+	// ObjectPhasePending denotes a MachineSet whose authoritative API was not flipped yet.
+	ObjectPhasePending ObjectPhase = "Pending"
+
+	// ObjectPhaseMigrating denotes a MachineSet whose authoritative API was flipped, and which is not synchronized yet.
+	ObjectPhaseMigrating ObjectPhase = "Migrating"
+
+	// ObjectPhaseMigrated denotes a MachineSet which is synchronized with the target authority.
+	ObjectPhaseMigrated ObjectPhase = "Migrated"
+
+	// ObjectPhaseFailed denotes a MachineSet which could not be flipped, or failed to synchronize.
+	ObjectPhaseFailed ObjectPhase = "Failed"
+)
+
+// MachineAPIMigrationSpec defines the desired state of a MachineAPIMigration.
+type MachineAPIMigrationSpec struct {
+	// selector selects the MAPI MachineSets to migrate, within the namespace of the MachineAPIMigration.
+	// +required
+	Selector metav1.LabelSelector `json:"selector"`
+
+	// targetAuthority is the authoritative API the selected MachineSets are migrated to.
+	// Setting it back to MachineAPI rolls the migration back, in batches.
+	// +kubebuilder:validation:Enum=MachineAPI;ClusterAPI
+	// +required
+	TargetAuthority machinev1beta1.MachineAuthority `json:"targetAuthority"`
+
+	// batchSize is the number of MachineSets migrated in each batch.
+	// The next batch starts once every MachineSet of the batch is migrated, and the batch settled.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	// +optional
+	BatchSize int32 `json:"batchSize,omitempty"`
+
+	// maxConcurrency is the maximum number of MachineSets of a batch migrating at the same time.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	// +optional
+	MaxConcurrency int32 `json:"maxConcurrency,omitempty"`
+
+	// settleDuration is how long to wait once a batch is migrated before starting the next batch.
+	// +kubebuilder:default="1m"
+	// +optional
+	SettleDuration *metav1.Duration `json:"settleDuration,omitempty"`
+
+	// paused stops the migration from flipping further MachineSets.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+}
+
+// MachineAPIMigrationObjectStatus is the migration progress of a single MachineSet.
+type MachineAPIMigrationObjectStatus struct {
+	// name is the name of the MachineSet.
+	// +required
+	Name string `json:"name"`
+
+	// batch is the batch the MachineSet is migrated in, starting at 1. It is 0 while the MachineSet is not part of a batch.
+	// +optional
+	Batch int32 `json:"batch,omitempty"`
+
+	// phase is the migration phase of the MachineSet.
+	// +required
+	Phase ObjectPhase `json:"phase"`
+
+	// message explains the phase of the MachineSet, e.g. why it failed.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// lastTransitionTime is the last time the phase of the MachineSet changed.
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// MachineAPIMigrationStatus defines the observed state of a MachineAPIMigration.
+type MachineAPIMigrationStatus struct {
+	// observedGeneration is the generation of the spec last reconciled.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// phase is the phase of the migration.
+	// +optional
+	Phase MigrationPhase `json:"phase,omitempty"`
+
+	// message explains the phase of the migration, e.g. why it is paused.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// targetAuthority is the target authority the batches were planned for.
+	// When spec.targetAuthority changes, e.g. on rollback, the batches are planned again.
+	// +optional
+	TargetAuthority machinev1beta1.MachineAuthority `json:"targetAuthority,omitempty"`
+
+	// currentBatch is the batch being migrated, starting at 1.
+	// +optional
+	CurrentBatch int32 `json:"currentBatch,omitempty"`
+
+	// batchCompletedTime is the time every MachineSet of the current batch was migrated.
+	// The next batch starts once the batch settled for spec.settleDuration.
+	// +optional
+	BatchCompletedTime *metav1.Time `json:"batchCompletedTime,omitempty"`
+
+	// objects is the migration progress of every selected MachineSet.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Objects []MachineAPIMigrationObjectStatus `json:"objects,omitempty"`
+}
+
+// MachineAPIMigration flips the authoritative API of the selected MAPI MachineSets in batches,
+// waiting for each batch to be synchronized before starting the next one.
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Target",type=string,JSONPath=".spec.targetAuthority"
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="Batch",type=integer,JSONPath=".status.currentBatch"
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=".metadata.creationTimestamp"
+type MachineAPIMigration struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MachineAPIMigrationSpec   `json:"spec,omitempty"`
+	Status MachineAPIMigrationStatus `json:"status,omitempty"`
+}
+
+// MachineAPIMigrationList contains a list of MachineAPIMigrations.
+// +kubebuilder:object:root=true
+type MachineAPIMigrationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []MachineAPIMigration `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&MachineAPIMigration{}, &MachineAPIMigrationList{})
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// OperatorConfigName is the name of the OperatorConfig singleton.
+	OperatorConfigName = "cluster"
+
+	// OperatorConfigValidCondition reports whether the configuration of every installed provider manager is valid.
+	// The configuration of a provider whose settings are invalid is not applied.
+	OperatorConfigValidCondition = "Valid"
+)
+
+// ControllerConcurrency is the number of objects a controller of a provider manager reconciles concurrently.
+type ControllerConcurrency struct {
+	// controller is the name of the controller, as in its --<controller>-concurrency manager argument,
+	// e.g. machine for --machine-concurrency.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]+$`
+	// +required
+	Controller string `json:"controller"`
+
+	// workers is the number of objects the controller reconciles concurrently.
+	// +kubebuilder:validation:Minimum=1
+	// +required
+	Workers int32 `json:"workers"`
+}
+
+// ProviderManagerConfig configures the manager of a CAPI provider.
+type ProviderManagerConfig struct {
+	// logVerbosity is the verbosity of the manager logs, set with the --v manager argument.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=10
+	// +optional
+	LogVerbosity *int32 `json:"logVerbosity,omitempty"`
+
+	// syncPeriod is the interval at which every watched object is reconciled, set with the --sync-period manager argument.
+	// +optional
+	SyncPeriod *metav1.Duration `json:"syncPeriod,omitempty"`
+
+	// concurrency is the number of objects the controllers of the manager reconcile concurrently.
+	// +listType=map
+	// +listMapKey=controller
+	// +optional
+	Concurrency []ControllerConcurrency `json:"concurrency,omitempty"`
+
+	// extraArgs are additional manager arguments, keyed by their name without leading dashes.
+	// Only the arguments allowed for the provider are accepted.
+	// +optional
+	ExtraArgs map[string]string `json:"extraArgs,omitempty"`
+
+	// resourceRequests are the resource requests of the manager container.
+	// +optional
+	ResourceRequests corev1.ResourceList `json:"resourceRequests,omitempty"`
+}
+
+// ProviderManagerOverride configures the manager of a single CAPI provider.
+type ProviderManagerOverride struct {
+	// name is the name of the provider, as in the cluster.x-k8s.io/provider label of its components,
+	// e.g. cluster-api or infrastructure-aws.
+	// +required
+	Name string `json:"name"`
+
+	// image replaces the image of the manager container, e.g. with a debugging build of the provider.
+	// The ClusterOperator is not Upgradeable while the image of a provider manager is overridden.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	ProviderManagerConfig `json:",inline"`
+}
+
+// OperatorConfigSpec defines the desired configuration of the CAPI provider managers.
+type OperatorConfigSpec struct {
+	// defaults configures the manager of every provider.
+	// Settings which are not allowed for a provider are not applied to its manager.
+	// +optional
+	Defaults ProviderManagerConfig `json:"defaults,omitempty"`
+
+	// providers configures the manager of single providers, taking precedence over the defaults.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Providers []ProviderManagerOverride `json:"providers,omitempty"`
+}
+
+// ProviderManagerStatus is the configuration applied to the manager of a CAPI provider.
+type ProviderManagerStatus struct {
+	// name is the name of the provider.
+	// +required
+	Name string `json:"name"`
+
+	// image is the image of the manager container when overridden by the configuration.
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// args are the manager arguments set from the configuration.
+	// +listType=atomic
+	// +optional
+	Args []string `json:"args,omitempty"`
+
+	// resourceRequests are the resource requests of the manager container set from the configuration.
+	// +optional
+	ResourceRequests corev1.ResourceList `json:"resourceRequests,omitempty"`
+}
+
+// OperatorConfigStatus defines the observed state of the OperatorConfig.
+type OperatorConfigStatus struct {
+	// observedGeneration is the generation of the spec last applied.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// conditions report whether the configuration is valid.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// providers is the configuration effectively applied to the manager of every installed provider.
+	// +listType=map
+	// +listMapKey=name
+	// +optional
+	Providers []ProviderManagerStatus `json:"providers,omitempty"`
+}
+
+// OperatorConfig configures the managers of the CAPI providers installed by the cluster CAPI operator.
+// It is a singleton named cluster.
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
+// +kubebuilder:validation:XValidation:rule="self.metadata.name == 'cluster'",message="operatorconfig is a singleton, its name must be cluster"
+type OperatorConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OperatorConfigSpec   `json:"spec,omitempty"`
+	Status OperatorConfigStatus `json:"status,omitempty"`
+}
+
+// OperatorConfigList contains a list of OperatorConfigs.
+// +kubebuilder:object:root=true
+type OperatorConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OperatorConfig `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OperatorConfig{}, &OperatorConfigList{})
+}
@@ -0,0 +1,292 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package capiinstaller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+)
+
+const (
+	// componentPhaseRequeueInterval is how often a phase waiting to be ready is checked again.
+	componentPhaseRequeueInterval = 10 * time.Second
+
+	// servingCertSecretAnnotation requests the service CA operator to create a serving certificate Secret for a Service.
+	servingCertSecretAnnotation = "service.beta.openshift.io/serving-cert-secret-name" //nolint:gosec
+
+	// injectCABundleAnnotation requests the service CA operator to inject its CA bundle into an object.
+	injectCABundleAnnotation = "service.beta.openshift.io/inject-cabundle"
+)
+
+// componentPhase is a phase of the installation of the provider components.
+// The phases are applied in order, each one once the previous phase is ready.
+type componentPhase int
+
+const (
+	// componentPhaseCRDs applies the CRDs, and is ready once they are established.
+	componentPhaseCRDs componentPhase = iota
+	// componentPhaseRBAC applies the ServiceAccounts, RBAC and any other static component.
+	componentPhaseRBAC
+	// componentPhaseServices applies the Services, and is ready once their serving certificates are created,
+	// and the CA bundle is injected into the CRDs requesting it.
+	componentPhaseServices
+	// componentPhaseDeployments applies the Deployments, and is ready once they are available.
+	componentPhaseDeployments
+	// componentPhaseWebhooks applies the webhook configurations and the validating admission policies.
+	componentPhaseWebhooks
+
+	componentPhaseCount
+)
+
+// String returns the name of the phase.
+func (p componentPhase) String() string {
+	switch p {
+	case componentPhaseCRDs:
+		return "CustomResourceDefinitions"
+	case componentPhaseRBAC:
+		return "RBAC"
+	case componentPhaseServices:
+		return "Services"
+	case componentPhaseDeployments:
+		return "Deployments"
+	case componentPhaseWebhooks:
+		return "Webhooks"
+	case componentPhaseCount:
+	}
+
+	return fmt.Sprintf("componentPhase(%d)", int(p))
+}
+
+// componentPhaseForKind returns the phase applying the components of the given kind.
+func componentPhaseForKind(kind string) componentPhase {
+	switch kind {
+	case "CustomResourceDefinition":
+		return componentPhaseCRDs
+	case "Service":
+		return componentPhaseServices
+	case "Deployment":
+		return componentPhaseDeployments
+	case "ValidatingWebhookConfiguration", "MutatingWebhookConfiguration", "ValidatingAdmissionPolicy", "ValidatingAdmissionPolicyBinding":
+		return componentPhaseWebhooks
+	default:
+		return componentPhaseRBAC
+	}
+}
+
+// phaseComponents are the components applied by a phase.
+type phaseComponents struct {
+	// filenames are the names of the components, in the order they are applied.
+	filenames []string
+
+	// assets are the manifests of the components, by name.
+	assets map[string]string
+}
+
+// appliedComponents are the components applied so far, as observed after the apply.
+type appliedComponents struct {
+	crds        []*apiextensionsv1.CustomResourceDefinition
+	services    []*corev1.Service
+	deployments []*appsv1.Deployment
+}
+
+// addResults records the objects applied by resourceapply.ApplyDirectly.
+func (a *appliedComponents) addResults(results []resourceapply.ApplyResult) {
+	for _, res := range results {
+		switch obj := res.Result.(type) {
+		case *apiextensionsv1.CustomResourceDefinition:
+			a.crds = append(a.crds, obj)
+		case *corev1.Service:
+			a.services = append(a.services, obj)
+		}
+	}
+}
+
+// phaseNotReady describes why the given phase is not ready yet, or returns an empty string when it is ready.
+func (r *CapiInstallerController) phaseNotReady(ctx context.Context, phase componentPhase, applied *appliedComponents) (string, error) {
+	var (
+		notReady []string
+		err      error
+	)
+
+	switch phase {
+	case componentPhaseCRDs:
+		notReady = crdsNotEstablished(applied.crds)
+	case componentPhaseServices:
+		notReady, err = r.servingCertSecretsNotCreated(ctx, applied.services)
+		if err != nil {
+			return "", err
+		}
+
+		notReady = append(notReady, crdsWithoutCABundle(applied.crds)...)
+	case componentPhaseDeployments:
+		notReady = deploymentsNotAvailable(applied.deployments)
+	case componentPhaseRBAC, componentPhaseWebhooks, componentPhaseCount:
+	}
+
+	return strings.Join(notReady, ", "), nil
+}
+
+// crdsNotEstablished describes the CRDs which are not established yet.
+func crdsNotEstablished(crds []*apiextensionsv1.CustomResourceDefinition) []string {
+	var notReady []string
+
+	for _, crd := range crds {
+		established := false
+
+		for _, c := range crd.Status.Conditions {
+			if c.Type == apiextensionsv1.Established && c.Status == apiextensionsv1.ConditionTrue {
+				established = true
+			}
+		}
+
+		if !established {
+			notReady = append(notReady, fmt.Sprintf("CustomResourceDefinition %s is not established", crd.Name))
+		}
+	}
+
+	return notReady
+}
+
+// applyCRDs applies the CRDs, keeping the CA bundle already injected into their conversion webhook.
+// Applying a CRD replaces its whole spec, which would otherwise remove the injected CA bundle
+// and break the conversion of its resources until the service CA operator injects it again.
+func (r *CapiInstallerController) applyCRDs(ctx context.Context, components phaseComponents, applied *appliedComponents) error {
+	if err := r.keepInjectedCABundles(ctx, components); err != nil {
+		return err
+	}
+
+	return r.applyStaticComponents(ctx, components, applied)
+}
+
+// keepInjectedCABundles sets the CA bundle injected into the live CRDs on the CRD components requesting it.
+func (r *CapiInstallerController) keepInjectedCABundles(ctx context.Context, components phaseComponents) error {
+	for _, name := range components.filenames {
+		obj, err := yamlToRuntimeObject(r.Scheme, components.assets[name])
+		if err != nil {
+			return fmt.Errorf("error parsing CAPI provider component %q: %w", name, err)
+		}
+
+		crd, ok := obj.(*apiextensionsv1.CustomResourceDefinition)
+		if !ok || !requestsCABundle(crd) || len(crd.Spec.Conversion.Webhook.ClientConfig.CABundle) > 0 {
+			continue
+		}
+
+		caBundle, err := r.injectedCABundle(ctx, crd.Name)
+		if err != nil {
+			return err
+		}
+
+		if len(caBundle) == 0 {
+			continue
+		}
+
+		crd.Spec.Conversion.Webhook.ClientConfig.CABundle = caBundle
+		crd.SetGroupVersionKind(apiextensionsv1.SchemeGroupVersion.WithKind("CustomResourceDefinition"))
+
+		manifest, err := yaml.Marshal(crd)
+		if err != nil {
+			return fmt.Errorf("error marshalling CAPI provider component %q: %w", name, err)
+		}
+
+		components.assets[name] = string(manifest)
+	}
+
+	return nil
+}
+
+// injectedCABundle returns the CA bundle injected into the conversion webhook of the live CRD, if any.
+func (r *CapiInstallerController) injectedCABundle(ctx context.Context, name string) ([]byte, error) {
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := r.Get(ctx, client.ObjectKey{Name: name}, crd); apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error getting CRD %q: %w", name, err)
+	}
+
+	if !requestsCABundle(crd) {
+		return nil, nil
+	}
+
+	return crd.Spec.Conversion.Webhook.ClientConfig.CABundle, nil
+}
+
+// requestsCABundle returns true when the CA bundle is requested to be injected into the conversion webhook of the CRD.
+func requestsCABundle(crd *apiextensionsv1.CustomResourceDefinition) bool {
+	return crd.Annotations[injectCABundleAnnotation] == "true" && crd.Spec.Conversion != nil &&
+		crd.Spec.Conversion.Webhook != nil && crd.Spec.Conversion.Webhook.ClientConfig != nil
+}
+
+// crdsWithoutCABundle describes the CRDs whose conversion webhook is waiting for the CA bundle to be injected.
+func crdsWithoutCABundle(crds []*apiextensionsv1.CustomResourceDefinition) []string {
+	var notReady []string
+
+	for _, crd := range crds {
+		if requestsCABundle(crd) && len(crd.Spec.Conversion.Webhook.ClientConfig.CABundle) == 0 {
+			notReady = append(notReady, fmt.Sprintf("CustomResourceDefinition %s has no CA bundle injected", crd.Name))
+		}
+	}
+
+	return notReady
+}
+
+// servingCertSecretsNotCreated describes the Services whose serving certificate Secret is not created yet.
+func (r *CapiInstallerController) servingCertSecretsNotCreated(ctx context.Context, services []*corev1.Service) ([]string, error) {
+	var notReady []string
+
+	for _, svc := range services {
+		secretName, ok := svc.Annotations[servingCertSecretAnnotation]
+		if !ok {
+			continue
+		}
+
+		// The Secrets are not cached, so they are read directly rather than through the cached client.
+		_, err := r.ApplyClient.CoreV1().Secrets(svc.Namespace).Get(ctx, secretName, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			notReady = append(notReady, fmt.Sprintf("serving certificate Secret %s of Service %s is not created", secretName, svc.Name))
+
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("unable to get serving certificate Secret %s/%s: %w", svc.Namespace, secretName, err)
+		}
+	}
+
+	return notReady, nil
+}
+
+// deploymentsNotAvailable describes the Deployments which are not available.
+func deploymentsNotAvailable(deployments []*appsv1.Deployment) []string {
+	var notReady []string
+
+	for _, d := range deployments {
+		if available := getDeploymentCondition(d, appsv1.DeploymentAvailable); available == nil || available.Status != corev1.ConditionTrue {
+			notReady = append(notReady, fmt.Sprintf("Deployment %s is not available", d.Name))
+		}
+	}
+
+	return notReady
+}
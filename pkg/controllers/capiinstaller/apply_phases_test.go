@@ -0,0 +1,129 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package capiinstaller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/cluster-capi-operator/pkg/operatorstatus"
+)
+
+var testValidatingWebhookConfigurationManifest = `apiVersion: admissionregistration.k8s.io/v1
+kind: ValidatingWebhookConfiguration
+metadata:
+  name: capa-validating-webhook-configuration
+`
+
+func newTestCRD(name string, established bool) *apiextensionsv1.CustomResourceDefinition {
+	crd := &apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	if established {
+		crd.Status.Conditions = []apiextensionsv1.CustomResourceDefinitionCondition{
+			{Type: apiextensionsv1.Established, Status: apiextensionsv1.ConditionTrue},
+		}
+	}
+
+	return crd
+}
+
+func newTestConversionCRD(name string, inject bool, caBundle []byte) *apiextensionsv1.CustomResourceDefinition {
+	crd := newTestCRD(name, true)
+	crd.Spec.Conversion = &apiextensionsv1.CustomResourceConversion{
+		Strategy: apiextensionsv1.WebhookConverter,
+		Webhook:  &apiextensionsv1.WebhookConversion{ClientConfig: &apiextensionsv1.WebhookClientConfig{CABundle: caBundle}},
+	}
+
+	if inject {
+		crd.Annotations = map[string]string{injectCABundleAnnotation: "true"}
+	}
+
+	return crd
+}
+
+var _ = Describe("Provider component phases", func() {
+	It("should divide the components into the phases applying them", func() {
+		phases, err := getProviderComponents(scheme.Scheme, []string{testValidatingWebhookConfigurationManifest, testManifest, testServiceAccountManifest})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(phases).To(HaveLen(int(componentPhaseCount)))
+
+		Expect(phases[componentPhaseCRDs].filenames).To(BeEmpty())
+		Expect(phases[componentPhaseRBAC].filenames).To(ConsistOf("/v1/ServiceAccount - openshift-cluster-api/capa-controller-manager"))
+		Expect(phases[componentPhaseDeployments].filenames).To(ConsistOf("apps/v1/Deployment - nginx-deployment"))
+		Expect(phases[componentPhaseWebhooks].filenames).To(ConsistOf("admissionregistration.k8s.io/v1/ValidatingWebhookConfiguration - capa-validating-webhook-configuration"))
+		Expect(phases[componentPhaseWebhooks].assets).To(HaveKeyWithValue(
+			"admissionregistration.k8s.io/v1/ValidatingWebhookConfiguration - capa-validating-webhook-configuration", testValidatingWebhookConfigurationManifest))
+	})
+
+	It("should wait for the CRDs to be established", func() {
+		Expect(crdsNotEstablished([]*apiextensionsv1.CustomResourceDefinition{
+			newTestCRD("awsclusters.infrastructure.cluster.x-k8s.io", true),
+			newTestCRD("awsmachines.infrastructure.cluster.x-k8s.io", false),
+		})).To(ConsistOf("CustomResourceDefinition awsmachines.infrastructure.cluster.x-k8s.io is not established"))
+	})
+
+	It("should wait for the CA bundle to be injected into the CRDs requesting it", func() {
+		Expect(crdsWithoutCABundle([]*apiextensionsv1.CustomResourceDefinition{
+			newTestCRD("awsclusters.infrastructure.cluster.x-k8s.io", true),
+			newTestConversionCRD("awsmachines.infrastructure.cluster.x-k8s.io", true, []byte("ca")),
+			newTestConversionCRD("awsmachinetemplates.infrastructure.cluster.x-k8s.io", true, nil),
+			newTestConversionCRD("awsmanagedclusters.infrastructure.cluster.x-k8s.io", false, nil),
+		})).To(ConsistOf("CustomResourceDefinition awsmachinetemplates.infrastructure.cluster.x-k8s.io has no CA bundle injected"))
+	})
+
+	It("should keep the CA bundle injected into the live CRDs", func() {
+		sch := runtime.NewScheme()
+		Expect(apiextensionsv1.AddToScheme(sch)).To(Succeed())
+
+		required := newTestConversionCRD("awsmachines.infrastructure.cluster.x-k8s.io", true, nil)
+		required.SetGroupVersionKind(apiextensionsv1.SchemeGroupVersion.WithKind("CustomResourceDefinition"))
+		manifest, err := yaml.Marshal(required)
+		Expect(err).NotTo(HaveOccurred())
+
+		r := &CapiInstallerController{
+			ClusterOperatorStatusClient: operatorstatus.ClusterOperatorStatusClient{
+				Client: fake.NewClientBuilder().WithScheme(sch).WithObjects(newTestConversionCRD(required.Name, true, []byte("ca"))).Build(),
+			},
+			Scheme: sch,
+		}
+
+		components := phaseComponents{filenames: []string{required.Name}, assets: map[string]string{required.Name: string(manifest)}}
+		Expect(r.keepInjectedCABundles(context.Background(), components)).To(Succeed())
+
+		obj, err := yamlToRuntimeObject(sch, components.assets[required.Name])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(obj).To(HaveField("Spec.Conversion.Webhook.ClientConfig.CABundle", []byte("ca")))
+	})
+
+	It("should wait for the Deployments to be available", func() {
+		available := &appsv1.DeploymentCondition{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue}
+		unavailable := &appsv1.DeploymentCondition{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionFalse}
+
+		Expect(deploymentsNotAvailable([]*appsv1.Deployment{
+			newTestDeployment(1, 1, 1, 1, 1, available),
+			newTestDeployment(1, 1, 1, 1, 0, unavailable),
+		})).To(ConsistOf("Deployment capa-controller-manager is not available"))
+	})
+})
@@ -21,16 +21,13 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/drone/envsubst/v2"
 	"github.com/go-logr/logr"
 
-	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
-	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	rbacv1 "k8s.io/api/rbac/v1"
-	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -38,14 +35,18 @@ import (
 
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	configv1 "github.com/openshift/api/config/v1"
+	operatorv1alpha1 "github.com/openshift/cluster-capi-operator/pkg/apis/operator/v1alpha1"
 	"github.com/openshift/cluster-capi-operator/pkg/controllers"
 	"github.com/openshift/cluster-capi-operator/pkg/operatorstatus"
+	"github.com/openshift/cluster-capi-operator/pkg/util"
 	"github.com/openshift/library-go/pkg/operator/events"
 	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
 	"github.com/openshift/library-go/pkg/operator/resource/resourcemerge"
@@ -57,6 +58,10 @@ const (
 	// Controller conditions for the Cluster Operator resource.
 	capiInstallerControllerAvailableCondition = "CapiInstallerControllerAvailable"
 	capiInstallerControllerDegradedCondition  = "CapiInstallerControllerDegraded"
+	// capiInstallerControllerProgressingCondition is True while a provider Deployment rolls out.
+	capiInstallerControllerProgressingCondition = "CapiInstallerControllerProgressing"
+	// capiInstallerControllerUpgradeableCondition is False while the image of a provider manager is overridden.
+	capiInstallerControllerUpgradeableCondition = "CapiInstallerControllerUpgradeable"
 
 	controllerName                    = "CapiInstallerController"
 	defaultCAPINamespace              = "openshift-cluster-api"
@@ -64,7 +69,6 @@ const (
 	providerConfigMapLabelTypeKey     = "provider.cluster.x-k8s.io/type"
 	providerConfigMapLabelNameKey     = "provider.cluster.x-k8s.io/name"
 	ownedProviderComponentName        = "cluster.x-k8s.io/provider"
-	imagePlaceholder                  = "to.be/replaced:v99"
 	openshiftInfrastructureObjectName = "cluster"
 	notNamespaced                     = ""
 	clusterOperatorName               = "cluster-api"
@@ -85,166 +89,400 @@ type CapiInstallerController struct {
 	Images              map[string]string
 	RestCfg             *rest.Config
 	Platform            configv1.PlatformType
+	Infra               *configv1.Infrastructure
 	ApplyClient         *kubernetes.Clientset
 	APIExtensionsClient *apiextensionsclient.Clientset
+	// ProvidersFile lists the additional providers to install when the providers ConfigMap does not exist.
+	ProvidersFile string
 }
 
 // Reconcile reconciles the cluster-api ClusterOperator object.
 func (r *CapiInstallerController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := ctrl.LoggerFrom(ctx).WithName(controllerName)
 
-	res, err := r.reconcile(ctx, log)
+	result, err := r.reconcile(ctx, log)
 	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("error during reconcile: %w", err)
 	}
 
-	if err := r.setAvailableCondition(ctx, log); err != nil {
+	// The provider Deployments are watched, so the conditions follow their rollouts and availability.
+	health := newDeploymentsHealth(result.deployments, time.Now(), deploymentDegradedGracePeriod)
+	health.waitForPhases(result.waiting, componentPhaseRequeueInterval)
+
+	if err := r.setAvailableCondition(ctx, log, health, result.imageOverrides); err != nil {
 		return ctrl.Result{}, fmt.Errorf("failed to set conditions for CAPI Installer Controller: %w", err)
 	}
 
-	return res, nil
+	// Requeue to degrade once an unavailable Deployment exceeds the grace period, or to apply the next phase of the components.
+	return ctrl.Result{RequeueAfter: health.requeueAfter}, nil
 }
 
 // reconcile performs the main business logic for installing Cluster API components in the cluster.
-// Notably it fetches CAPI providers "transport" ConfigMap(s) matching the required labels,
+// Notably it fetches the "transport" ConfigMap(s) of the desired CAPI providers,
 // it extracts from those ConfigMaps the embedded CAPI providers manifests for the components
 // and it applies them to the cluster.
-//
-//nolint:unparam
-func (r *CapiInstallerController) reconcile(ctx context.Context, log logr.Logger) (ctrl.Result, error) {
-	// Define the desired providers to be installed for this cluster.
-	// We always want to install the core provider, which in our case is the default cluster-api core provider.
-	// We also want to install the infrastructure provider that matches the currently detected platform the cluster is running on.
-	providerConfigMapLabels := map[string]string{
-		"core":           defaultCoreProviderComponentName,
-		"infrastructure": platformToProviderConfigMapLabelNameValue(r.Platform),
-	}
-
-	// Process each one of the desired providers.
-	for providerConfigMapLabelTypeVal, providerConfigMapLabelNameVal := range providerConfigMapLabels {
-		log.Info("reconciling CAPI provider", "name", providerConfigMapLabelNameVal)
-
-		// Get a List all the ConfigMaps matching the desired provider labels.
-		configMapList := &corev1.ConfigMapList{}
-		if err := r.List(ctx, configMapList, client.InNamespace(defaultCAPINamespace),
-			client.MatchingLabels{
-				providerConfigMapLabelNameKey: providerConfigMapLabelNameVal,
-				providerConfigMapLabelTypeKey: providerConfigMapLabelTypeVal,
-			},
-		); err != nil {
-			if err := r.setDegradedCondition(ctx, log); err != nil {
-				return ctrl.Result{}, fmt.Errorf("failed to set conditions for CAPI Installer controller: %w", err)
-			}
-
-			return ctrl.Result{}, fmt.Errorf("unable to list CAPI provider %q ConfigMaps: %w", providerConfigMapLabelNameVal, err)
+func (r *CapiInstallerController) reconcile(ctx context.Context, log logr.Logger) (installResult, error) {
+	render, err := r.getRenderConfig(ctx, log)
+	if err != nil {
+		return installResult{}, err
+	}
+
+	// No provider is installed nor pruned while the desired providers cannot be read.
+	var result installResult
+
+	providers, errs := r.getDesiredProviders(ctx)
+	if errs == nil {
+		result, errs = r.reconcileProviders(ctx, log, providers, render)
+	}
+
+	if errs != nil {
+		if err := r.setDegradedCondition(ctx, log, errs); err != nil {
+			return installResult{}, fmt.Errorf("failed to set conditions for CAPI Installer controller: %w", err)
+		}
+
+		return installResult{}, errs
+	}
+
+	return result, nil
+}
+
+// installResult is the outcome of installing the desired providers.
+type installResult struct {
+	// deployments are the applied provider Deployments.
+	deployments []*appsv1.Deployment
+
+	// waiting describes the providers whose components are waiting for a phase to be ready.
+	waiting []string
+
+	// imageOverrides describes the providers whose manager image is overridden.
+	imageOverrides []string
+}
+
+// reconcileProviders reconciles each one of the given desired providers, with the operator configuration of its manager.
+// A provider failing to install does not prevent the other providers from being installed.
+// The configuration applied to every provider manager is reported on the OperatorConfig.
+func (r *CapiInstallerController) reconcileProviders(ctx context.Context, log logr.Logger, providers []util.Provider, render renderConfig) (installResult, error) {
+	operatorConfig, err := r.getOperatorConfig(ctx)
+	if err != nil {
+		return installResult{}, err
+	}
+
+	managers := make([]operatorv1alpha1.ProviderManagerStatus, 0, len(providers))
+
+	var (
+		result  installResult
+		invalid error
+		errs    error
+	)
+
+	for _, provider := range providers {
+		name := providerComponentName(provider.Type, provider.Name)
+
+		// An invalid configuration is not applied, leaving the provider manager as shipped.
+		manager, err := newManagerConfig(operatorConfig, name)
+		if err != nil {
+			log.Error(err, "not applying the invalid operator configuration of the CAPI provider manager", "name", name)
+
+			invalid = errors.Join(invalid, err)
 		}
 
-		// Extract the provider manifests stored each of the matching ConfigMaps.
-		var providerComponents []string
+		managers = append(managers, manager.status(name))
 
-		for _, cm := range configMapList.Items {
-			log.Info("processing CAPI provider ConfigMap", "configmapName", cm.Name, "providerType", cm.Labels[providerConfigMapLabelTypeKey],
-				"providerName", cm.Labels[providerConfigMapLabelNameKey], "providerVersion", cm.Labels[providerConfigMapLabelVersionKey])
+		if manager.image != "" {
+			result.imageOverrides = append(result.imageOverrides, fmt.Sprintf("%s uses %s", name, manager.image))
+		}
 
-			partialComponents, err := r.extractProviderComponents(cm)
-			if err != nil {
-				if err := r.setDegradedCondition(ctx, log); err != nil {
-					return ctrl.Result{}, fmt.Errorf("failed to set conditions for CAPI Installer controller: %w", err)
-				}
+		providerRender := render
+		providerRender.manager = manager
 
-				return ctrl.Result{}, fmt.Errorf("error extracting CAPI provider components from ConfigMap %q/%q: %w", cm.Namespace, cm.Name, err)
-			}
+		providerDeployments, providerWaiting, err := r.reconcileProvider(ctx, log, provider, providerRender)
+		if err != nil {
+			errs = errors.Join(errs, err)
 
-			providerComponents = append(providerComponents, partialComponents...)
+			continue
 		}
 
-		// Apply all the collected provider components manifests.
-		if err := r.applyProviderComponents(ctx, providerComponents); err != nil {
-			if err := r.setDegradedCondition(ctx, log); err != nil {
-				return ctrl.Result{}, fmt.Errorf("failed to set conditions for CAPI Installer controller: %w", err)
-			}
+		result.deployments = append(result.deployments, providerDeployments...)
 
-			return ctrl.Result{}, fmt.Errorf("error applying CAPI provider %q components: %w", providerConfigMapLabelNameVal, err)
+		if providerWaiting != "" {
+			result.waiting = append(result.waiting, providerWaiting)
 		}
+	}
+
+	// The components of the providers which are no longer desired are deleted.
+	if err := r.pruneDroppedProviders(ctx, log, providers); err != nil {
+		errs = errors.Join(errs, fmt.Errorf("error pruning dropped CAPI providers: %w", err))
+	}
+
+	if err := r.updateOperatorConfigStatus(ctx, operatorConfig, managers, invalid); err != nil {
+		errs = errors.Join(errs, err)
+	}
+
+	return result, errs
+}
+
+// renderConfig is the cluster configuration the provider components are rendered with.
+type renderConfig struct {
+	// variables are substituted into the manifests.
+	variables map[string]string
+
+	// images are the container images substituted into the manifests, keyed by component.
+	images map[string]string
+
+	// proxy is injected into the provider Deployments.
+	proxy proxyConfig
 
-		log.Info("finished reconciling CAPI provider", "name", providerConfigMapLabelNameVal)
+	// tlsArgs are the manager arguments applying the cluster TLS profile.
+	tlsArgs []string
+
+	// topology is the topology the provider Deployments are placed on.
+	topology deploymentTopology
+
+	// manager is the operator configuration of the provider manager, set for each provider.
+	manager managerConfig
+}
+
+// getRenderConfig returns the cluster configuration the provider components are rendered with.
+// The FeatureGate, the Proxy and the trusted CA bundle are watched, so the provider components are rendered again when they change.
+func (r *CapiInstallerController) getRenderConfig(ctx context.Context, log logr.Logger) (renderConfig, error) {
+	variables, err := r.getFeatureGateVariables(ctx, log)
+	if err != nil {
+		return renderConfig{}, err
 	}
 
-	return ctrl.Result{}, nil
+	proxy, err := r.getProxyConfig(ctx)
+	if err != nil {
+		return renderConfig{}, err
+	}
+
+	tlsProfile, err := util.GetTLSProfile(ctx, r.Client)
+	if err != nil {
+		return renderConfig{}, fmt.Errorf("error getting TLS profile: %w", err)
+	}
+
+	images, err := r.getImages(ctx)
+	if err != nil {
+		return renderConfig{}, err
+	}
+
+	return renderConfig{
+		variables: variables,
+		images:    images,
+		proxy:     proxy,
+		tlsArgs:   tlsArgs(tlsProfile),
+		topology:  newDeploymentTopology(r.Infra),
+	}, nil
 }
 
-// applyProviderComponents applies the provider components to the cluster.
-// It does so by differentiating between static components and dynamic components (i.e. Deployments).
-func (r *CapiInstallerController) applyProviderComponents(ctx context.Context, components []string) error {
-	componentsFilenames, componentsAssets, deploymentsFilenames, deploymentsAssets, err := getProviderComponents(r.Scheme, components)
+// reconcileProvider fetches the transport ConfigMaps of a single CAPI provider,
+// extracts the provider components manifests and applies them to the cluster.
+// The components are rendered with the given cluster configuration.
+// It returns the applied provider Deployments, and describes the phase of the components waiting to be ready, if any.
+func (r *CapiInstallerController) reconcileProvider(ctx context.Context, log logr.Logger, provider util.Provider, render renderConfig) ([]*appsv1.Deployment, string, error) {
+	log.Info("reconciling CAPI provider", "type", provider.Type, "name", provider.Name, "version", provider.Version)
+
+	configMaps, err := r.getProviderConfigMaps(ctx, provider)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Deny the InfraMachine fields which can not be converted to Machine API, along with the infrastructure provider.
+	providerComponents, err := r.unsupportedFieldsComponents(provider)
+	if err != nil {
+		return nil, "", fmt.Errorf("error generating unsupported fields policies: %w", err)
+	}
+
+	// Extract the provider manifests stored each of the matching ConfigMaps.
+	for _, cm := range configMaps {
+		log.Info("processing CAPI provider ConfigMap", "configmapName", cm.Name, "providerType", cm.Labels[providerConfigMapLabelTypeKey],
+			"providerName", cm.Labels[providerConfigMapLabelNameKey], "providerVersion", cm.Labels[providerConfigMapLabelVersionKey])
+
+		partialComponents, err := extractProviderComponents(cm, render)
+		if err != nil {
+			return nil, "", fmt.Errorf("error extracting CAPI provider components from ConfigMap %q/%q: %w", cm.Namespace, cm.Name, err)
+		}
+
+		providerComponents = append(providerComponents, partialComponents...)
+	}
+
+	// Apply all the collected provider components manifests.
+	deployments, waiting, err := r.applyProviderComponents(ctx, providerComponents, render)
 	if err != nil {
-		return fmt.Errorf("error getting provider components: %w", err)
+		return nil, "", fmt.Errorf("error applying CAPI provider %q components: %w", provider.Name, err)
+	}
+
+	// The components are not pruned until every phase is applied.
+	if waiting != "" {
+		log.Info("CAPI provider components are not fully applied yet", "name", provider.Name, "waiting", waiting)
+
+		return deployments, fmt.Sprintf("CAPI provider %s is %s", provider.Name, waiting), nil
+	}
+
+	// Without any transport ConfigMap, every previously applied component would be pruned.
+	if len(configMaps) == 0 {
+		log.Info("no CAPI provider ConfigMaps found, not pruning components", "name", provider.Name)
+
+		return deployments, "", nil
+	}
+
+	// Delete the components applied by a previous provider version which are no longer shipped.
+	if err := r.pruneProviderComponents(ctx, log, provider.Type, provider.Name, providerComponents); err != nil {
+		return nil, "", fmt.Errorf("error pruning CAPI provider %q components: %w", provider.Name, err)
+	}
+
+	log.Info("finished reconciling CAPI provider", "name", provider.Name)
+
+	return deployments, "", nil
+}
+
+// getProviderConfigMaps returns the transport ConfigMaps of the highest version of a CAPI provider
+// which supports the contract of the core provider.
+func (r *CapiInstallerController) getProviderConfigMaps(ctx context.Context, provider util.Provider) ([]corev1.ConfigMap, error) {
+	// Get a List all the ConfigMaps matching the desired provider labels.
+	configMapList := &corev1.ConfigMapList{}
+	if err := r.List(ctx, configMapList, client.InNamespace(defaultCAPINamespace), providerConfigMapLabels(provider)); err != nil {
+		return nil, fmt.Errorf("unable to list CAPI provider %q ConfigMaps: %w", provider.Name, err)
+	}
+
+	if len(configMapList.Items) == 0 {
+		return nil, nil
+	}
+
+	configMaps, err := selectCompatibleConfigMaps(configMapList.Items, clusterv1.GroupVersion.Version)
+	if err != nil {
+		return nil, fmt.Errorf("refusing to install incompatible CAPI provider %q: %w", provider.Name, err)
+	}
+
+	return configMaps, nil
+}
+
+// applyProviderComponents applies the provider components to the cluster, phase by phase.
+// Each phase is only applied once the previous phase is ready, so that e.g. webhook configurations
+// are not applied before the Deployments serving them are available.
+// It returns the applied Deployments, as observed after the apply, and describes the phase waiting to be ready, if any.
+func (r *CapiInstallerController) applyProviderComponents(ctx context.Context, components []string, render renderConfig) ([]*appsv1.Deployment, string, error) {
+	phases, err := getProviderComponents(r.Scheme, components)
+	if err != nil {
+		return nil, "", fmt.Errorf("error getting provider components: %w", err)
+	}
+
+	applied := &appliedComponents{}
+
+	for phase := componentPhaseCRDs; phase < componentPhaseCount; phase++ {
+		switch phase { //nolint:exhaustive
+		case componentPhaseCRDs:
+			err = r.applyCRDs(ctx, phases[phase], applied)
+		case componentPhaseDeployments:
+			applied.deployments, err = r.applyDeployments(ctx, phases[phase], render)
+		default:
+			err = r.applyStaticComponents(ctx, phases[phase], applied)
+		}
+
+		if err != nil {
+			return applied.deployments, "", fmt.Errorf("error applying %s: %w", phase, err)
+		}
+
+		notReady, err := r.phaseNotReady(ctx, phase, applied)
+		if err != nil {
+			return applied.deployments, "", fmt.Errorf("error checking %s: %w", phase, err)
+		}
+
+		if notReady != "" {
+			return applied.deployments, fmt.Sprintf("waiting for %s to be ready: %s", phase, notReady), nil
+		}
+	}
+
+	return applied.deployments, "", nil
+}
+
+// applyStaticComponents performs a Direct apply of the static components, and records the applied objects.
+func (r *CapiInstallerController) applyStaticComponents(ctx context.Context, components phaseComponents, applied *appliedComponents) error {
+	if len(components.filenames) == 0 {
+		return nil
 	}
 
-	// Perform a Direct apply of the static components.
 	res := resourceapply.ApplyDirectly(
 		ctx,
 		resourceapply.NewKubeClientHolder(r.ApplyClient).WithAPIExtensionsClient(r.APIExtensionsClient),
 		events.NewInMemoryRecorder("cluster-capi-operator-capi-installer-apply-client"),
 		resourceapply.NewResourceCache(),
-		assetFn(componentsAssets),
-		componentsFilenames...,
+		assetFn(components.assets),
+		components.filenames...,
 	)
 
-	// For each of the Deployment components perform a Deployment-specific apply.
-	for _, d := range deploymentsFilenames {
-		deploymentManifest, ok := deploymentsAssets[d]
+	var errs error
+
+	for i, r := range res {
+		if r.Error != nil {
+			errs = errors.Join(errs, fmt.Errorf("error applying CAPI provider component %q at position %d: %w", r.File, i, r.Error))
+		}
+	}
+
+	applied.addResults(res)
+
+	return errs
+}
+
+// applyDeployments performs a Deployment-specific apply of each of the Deployment components,
+// once the cluster configuration is injected into them. It returns the applied Deployments, as observed after the apply.
+func (r *CapiInstallerController) applyDeployments(ctx context.Context, components phaseComponents, render renderConfig) ([]*appsv1.Deployment, error) {
+	deployments := make([]*appsv1.Deployment, 0, len(components.filenames))
+
+	for _, d := range components.filenames {
+		deploymentManifest, ok := components.assets[d]
 		if !ok {
-			panic("error finding deployment manifest")
+			return nil, fmt.Errorf("error finding CAPI provider deployment manifest %q: %w", d, errResourceNotFound)
 		}
 
 		obj, err := yamlToRuntimeObject(r.Scheme, deploymentManifest)
 		if err != nil {
-			return fmt.Errorf("error parsing CAPI provider deployment manifets %q: %w", d, err)
+			return nil, fmt.Errorf("error parsing CAPI provider deployment manifets %q: %w", d, err)
 		}
 
-		// TODO: Deployments State/Conditions should influence the overall ClusterOperator Status.
 		deployment, ok := obj.(*appsv1.Deployment)
 		if !ok {
-			return fmt.Errorf("error casting object to Deployment: %w", err)
+			return nil, fmt.Errorf("error casting object to Deployment: %w", err)
 		}
 
-		if _, _, err := resourceapply.ApplyDeployment(
+		injectProxyConfig(deployment, render.proxy)
+		injectTLSArgs(deployment, render.tlsArgs)
+		injectTopology(deployment, render.topology)
+		injectManagerConfig(deployment, render.manager)
+
+		appliedDeployment, _, err := resourceapply.ApplyDeployment(
 			ctx,
 			r.ApplyClient.AppsV1(),
 			events.NewInMemoryRecorder("cluster-capi-operator-capi-installer-apply-client"),
 			deployment,
 			resourcemerge.ExpectedDeploymentGeneration(deployment, nil),
-		); err != nil {
-			return fmt.Errorf("error applying CAPI provider deployment %q: %w", deployment.Name, err)
+		)
+		if err != nil {
+			return nil, fmt.Errorf("error applying CAPI provider deployment %q: %w", deployment.Name, err)
 		}
-	}
 
-	var errs error
-
-	for i, r := range res {
-		if r.Error != nil {
-			errs = errors.Join(errs, fmt.Errorf("error applying CAPI provider component %q at position %d: %w", r.File, i, r.Error))
+		if err := r.reconcilePodDisruptionBudget(ctx, appliedDeployment, render.topology); err != nil {
+			return nil, err
 		}
+
+		deployments = append(deployments, appliedDeployment)
 	}
 
-	return errs
+	return deployments, nil
 }
 
-// getProviderComponents parses the provided list of components into a map of filenames and assets.
-// Deployments are handled separately so are returned in a separate map.
-func getProviderComponents(scheme *runtime.Scheme, components []string) ([]string, map[string]string, []string, map[string]string, error) {
-	componentsFilenames := []string{}
-	componentsAssets := make(map[string]string)
-
-	deploymentsFilenames := []string{}
-	deploymentsAssets := make(map[string]string)
+// getProviderComponents parses the provided list of components into the components of each phase,
+// as a list of filenames and a map of assets.
+func getProviderComponents(scheme *runtime.Scheme, components []string) ([]phaseComponents, error) {
+	phases := make([]phaseComponents, componentPhaseCount)
+	for i := range phases {
+		phases[i] = phaseComponents{filenames: []string{}, assets: map[string]string{}}
+	}
 
 	for i, m := range components {
 		// Parse the YAML manifests into unstructure objects.
 		u, err := yamlToUnstructured(scheme, m)
 		if err != nil {
-			return nil, nil, nil, nil, fmt.Errorf("error parsing provider component at position %d to unstructured: %w", i, err)
+			return nil, fmt.Errorf("error parsing provider component at position %d to unstructured: %w", i, err)
 		}
 
 		name := fmt.Sprintf("%s/%s/%s - %s",
@@ -254,36 +492,28 @@ func getProviderComponents(scheme *runtime.Scheme, components []string) ([]strin
 			getResourceName(u.GetNamespace(), u.GetName()),
 		)
 
-		// Divide manifests into static vs deployment components.
-		if u.GroupVersionKind().Kind == "Deployment" {
-			deploymentsFilenames = append(deploymentsFilenames, name)
-			deploymentsAssets[name] = m
-		} else {
-			componentsFilenames = append(componentsFilenames, name)
-			componentsAssets[name] = m
-		}
+		// Divide manifests into the phases applying them.
+		phase := &phases[componentPhaseForKind(u.GroupVersionKind().Kind)]
+		phase.filenames = append(phase.filenames, name)
+		phase.assets[name] = m
 	}
 
-	return componentsFilenames, componentsAssets, deploymentsFilenames, deploymentsAssets, nil
+	return phases, nil
 }
 
-// setAvailableCondition sets the ClusterOperator status condition to Available.
-func (r *CapiInstallerController) setAvailableCondition(ctx context.Context, log logr.Logger) error {
+// setAvailableCondition sets the ClusterOperator status conditions once the components are applied.
+// The conditions reflect the health of the provider Deployments, and whether the image of a provider manager is overridden.
+func (r *CapiInstallerController) setAvailableCondition(ctx context.Context, log logr.Logger, health deploymentsHealth, imageOverrides []string) error {
 	co, err := r.GetOrCreateClusterOperator(ctx)
 	if err != nil {
 		return fmt.Errorf("unable to get cluster operator: %w", err)
 	}
 
-	conds := []configv1.ClusterOperatorStatusCondition{
-		operatorstatus.NewClusterOperatorStatusCondition(capiInstallerControllerAvailableCondition, configv1.ConditionTrue, operatorstatus.ReasonAsExpected,
-			"CAPI Installer Controller works as expected"),
-		operatorstatus.NewClusterOperatorStatusCondition(capiInstallerControllerDegradedCondition, configv1.ConditionFalse, operatorstatus.ReasonAsExpected,
-			"CAPI Installer Controller works as expected"),
-	}
+	conds := append(health.conditions(), upgradeableConditions(co, imageOverrides)...)
 
 	co.Status.Versions = []configv1.OperandVersion{{Name: controllers.OperatorVersionKey, Version: r.ReleaseVersion}}
 
-	log.V(2).Info("CAPI Installer Controller is Available")
+	log.V(2).Info("CAPI Installer Controller applied the components", "unavailable", health.unavailable, "progressing", health.progressing, "degraded", health.degraded)
 
 	if err := r.SyncStatus(ctx, co, conds); err != nil {
 		return fmt.Errorf("failed to sync status: %w", err)
@@ -292,8 +522,8 @@ func (r *CapiInstallerController) setAvailableCondition(ctx context.Context, log
 	return nil
 }
 
-// setAvailableCondition sets the ClusterOperator status condition to Degraded.
-func (r *CapiInstallerController) setDegradedCondition(ctx context.Context, log logr.Logger) error {
+// setDegradedCondition sets the ClusterOperator status condition to Degraded, with the error which caused it.
+func (r *CapiInstallerController) setDegradedCondition(ctx context.Context, log logr.Logger, cause error) error {
 	co, err := r.GetOrCreateClusterOperator(ctx)
 	if err != nil {
 		return fmt.Errorf("unable to get cluster operator: %w", err)
@@ -301,14 +531,14 @@ func (r *CapiInstallerController) setDegradedCondition(ctx context.Context, log
 
 	conds := []configv1.ClusterOperatorStatusCondition{
 		operatorstatus.NewClusterOperatorStatusCondition(capiInstallerControllerAvailableCondition, configv1.ConditionFalse, operatorstatus.ReasonSyncFailed,
-			"CAPI Installer Controller failed install"),
+			fmt.Sprintf("CAPI Installer Controller failed install: %v", cause)),
 		operatorstatus.NewClusterOperatorStatusCondition(capiInstallerControllerDegradedCondition, configv1.ConditionTrue, operatorstatus.ReasonSyncFailed,
-			"CAPI Installer Controller failed install"),
+			fmt.Sprintf("CAPI Installer Controller failed install: %v", cause)),
 	}
 
 	co.Status.Versions = []configv1.OperandVersion{{Name: controllers.OperatorVersionKey, Version: r.ReleaseVersion}}
 
-	log.Info("CAPI Installer Controller is Degraded")
+	log.Info("CAPI Installer Controller is Degraded", "reason", cause.Error())
 
 	if err := r.SyncStatus(ctx, co, conds); err != nil {
 		return fmt.Errorf("failed to sync status: %w", err)
@@ -317,6 +547,28 @@ func (r *CapiInstallerController) setDegradedCondition(ctx context.Context, log
 	return nil
 }
 
+// watchClusterConfigs watches the cluster configuration singletons the provider components are rendered with.
+func watchClusterConfigs(build *builder.Builder) *builder.Builder {
+	clusterConfigs := []struct {
+		obj  client.Object
+		name string
+	}{
+		{&configv1.FeatureGate{}, featureGateObjectName},
+		{&configv1.Proxy{}, proxyObjectName},
+		{&configv1.APIServer{}, apiServerObjectName},
+	}
+
+	for _, c := range clusterConfigs {
+		build = build.Watches(
+			c.obj,
+			handler.EnqueueRequestsFromMapFunc(toClusterOperator),
+			builder.WithPredicates(clusterConfigPredicate(c.name)),
+		)
+	}
+
+	return build
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *CapiInstallerController) SetupWithManager(mgr ctrl.Manager) error {
 	build := ctrl.NewControllerManagedBy(mgr).
@@ -325,33 +577,23 @@ func (r *CapiInstallerController) SetupWithManager(mgr ctrl.Manager) error {
 		Watches(
 			&corev1.ConfigMap{},
 			handler.EnqueueRequestsFromMapFunc(toClusterOperator),
-			builder.WithPredicates(configMapPredicate(r.ManagedNamespace, r.Platform)),
+			builder.WithPredicates(configMapPredicate(r.ManagedNamespace)),
+		).
+		Watches(
+			&operatorv1alpha1.OperatorConfig{},
+			handler.EnqueueRequestsFromMapFunc(toClusterOperator),
+			// The status updates of the installer are not watched.
+			builder.WithPredicates(clusterConfigPredicate(operatorv1alpha1.OperatorConfigName), predicate.GenerationChangedPredicate{}),
 		)
 
-	// All of the following watches share the ownedPlatformLabelPredicate.
-	watches := []struct {
-		obj       client.Object
-		namespace string
-	}{
-		{&appsv1.Deployment{}, r.ManagedNamespace},
-		{&admissionregistrationv1.ValidatingWebhookConfiguration{}, notNamespaced},
-		{&admissionregistrationv1.MutatingWebhookConfiguration{}, notNamespaced},
-		{&admissionregistrationv1beta1.ValidatingAdmissionPolicy{}, notNamespaced},
-		{&admissionregistrationv1beta1.ValidatingAdmissionPolicyBinding{}, notNamespaced},
-		{&corev1.Service{}, r.ManagedNamespace},
-		{&apiextensionsv1.CustomResourceDefinition{}, notNamespaced},
-		{&corev1.ServiceAccount{}, r.ManagedNamespace},
-		{&rbacv1.ClusterRoleBinding{}, notNamespaced},
-		{&rbacv1.ClusterRole{}, notNamespaced},
-		{&rbacv1.Role{}, r.ManagedNamespace},
-		{&rbacv1.RoleBinding{}, r.ManagedNamespace},
-	}
-
-	for _, w := range watches {
+	build = watchClusterConfigs(build)
+
+	// All of the provider components watches share the ownedProviderLabelPredicate.
+	for _, kind := range r.providerComponentKinds() {
 		build = build.Watches(
-			w.obj,
+			kind.obj,
 			handler.EnqueueRequestsFromMapFunc(toClusterOperator),
-			builder.WithPredicates(ownedPlatformLabelPredicate(w.namespace, r.Platform)),
+			builder.WithPredicates(ownedProviderLabelPredicate(kind.namespace)),
 		)
 	}
 
@@ -366,30 +608,28 @@ func (r *CapiInstallerController) SetupWithManager(mgr ctrl.Manager) error {
 // The format of the ConfigMap is well known and follows the upstream CAPI's
 // clusterctl Provider Contract - Components YAML file contract defined at:
 // https://github.com/kubernetes-sigs/cluster-api/blob/a36712e28bf5d54e398ea84cb3e20102c0499426/docs/book/src/clusterctl/provider-contract.md?plain=1#L157-L162
-func (r *CapiInstallerController) extractProviderComponents(cm corev1.ConfigMap) ([]string, error) {
-	yamlManifests, err := extractManifests(cm)
+func extractProviderComponents(cm corev1.ConfigMap, render renderConfig) ([]string, error) {
+	yamlManifests, err := extractManifests(cm, render.variables)
 	if err != nil {
 		return nil, fmt.Errorf("failed to extract manifests from configMap: %w", err)
 	}
 
-	replacedYamlManifests := []string{}
-	providerName := cm.Labels[providerConfigMapLabelNameKey]
+	// The Deployments of each ConfigMap are shipped with the manager image and entrypoint recorded on it.
+	manager := providerManagerOf(cm)
 
-	for _, m := range yamlManifests {
-		newM := strings.Replace(m, imagePlaceholder, r.Images[providerNameToImageKey(providerName)], 1)
-		newM = strings.Replace(newM, "registry.ci.openshift.org/openshift:kube-rbac-proxy", r.Images["kube-rbac-proxy"], 1)
-		// TODO: change this to manager in the forked providers openshift/Dockerfile.rhel.
-		newM = strings.Replace(newM, "/manager", providerNameToCommand(providerName), 1)
-
-		replacedYamlManifests = append(replacedYamlManifests, newM)
+	for i, m := range yamlManifests {
+		yamlManifests[i], err = renderProviderDeployment(m, manager, render.images)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render provider component at position %d: %w", i, err)
+		}
 	}
 
-	return replacedYamlManifests, nil
+	return yamlManifests, nil
 }
 
 // extractManifests extracts and processes component manifests from given ConfiMap.
 // If the data is in compressed binary form, it decompresses them.
-func extractManifests(cm corev1.ConfigMap) ([]string, error) {
+func extractManifests(cm corev1.ConfigMap, variables map[string]string) ([]string, error) {
 	data, hasData := cm.Data["components"]
 	binaryData, hasBinary := cm.BinaryData["components-zstd"]
 
@@ -412,9 +652,9 @@ func extractManifests(cm corev1.ConfigMap) ([]string, error) {
 	}
 
 	// Certain provider components have drone/envsubst environment variables interpolated within the manifest.
-	// Substitute them with the value defined in the environment variable (see setFeatureGatesEnvVars()).
+	// Substitute them with the value of the variable (see providerFeatureGates()).
 	// If that's not set, fallback to the default value defined in the template.
-	components, err := envsubst.EvalEnv(data)
+	components, err := envsubst.Eval(data, func(name string) string { return variables[name] })
 	if err != nil {
 		return nil, fmt.Errorf("failed to substitute environment variables in component manifests: %w", err)
 	}
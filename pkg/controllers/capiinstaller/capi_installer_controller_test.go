@@ -93,7 +93,7 @@ var _ = Describe("extractManifests", func() {
 
 	for _, tc := range testCases {
 		It(tc.name, func() {
-			manifests, err := extractManifests(tc.configMap)
+			manifests, err := extractManifests(tc.configMap, nil)
 
 			if tc.expectedError != nil {
 				Expect(err).To(MatchError(tc.expectedError))
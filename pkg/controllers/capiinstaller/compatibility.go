@@ -0,0 +1,122 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package capiinstaller
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/version"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/yaml"
+)
+
+// providerConfigMapMetadataKey is the key of the transport ConfigMaps holding the clusterctl metadata.yaml of the provider.
+const providerConfigMapMetadataKey = "metadata"
+
+var (
+	errNoProviderMetadata        = errors.New("provider configmap has no metadata")
+	errNoReleaseSeries           = errors.New("provider metadata has no release series for the version")
+	errIncompatibleContract      = errors.New("provider release series does not support the core CAPI contract")
+	errNoCompatibleProviderFound = errors.New("no provider version supports the core CAPI contract")
+)
+
+// providerRelease is a version of a provider, along with its transport ConfigMaps.
+type providerRelease struct {
+	version    *version.Version
+	configMaps []corev1.ConfigMap
+	err        error
+}
+
+// selectCompatibleConfigMaps returns the transport ConfigMaps of the highest version of a provider
+// whose release series supports the contract. It returns an error explaining why each version is
+// incompatible when no version supports it.
+func selectCompatibleConfigMaps(configMaps []corev1.ConfigMap, contract string) ([]corev1.ConfigMap, error) {
+	releases := map[string]*providerRelease{}
+
+	for _, cm := range configMaps {
+		versionLabel := cm.Labels[providerConfigMapLabelVersionKey]
+
+		release, ok := releases[versionLabel]
+		if !ok {
+			release = &providerRelease{}
+			releases[versionLabel] = release
+		}
+
+		v, err := checkProviderConfigMapContract(cm, contract)
+		if err != nil {
+			release.err = errors.Join(release.err, fmt.Errorf("ConfigMap %s/%s: %w", cm.Namespace, cm.Name, err))
+		}
+
+		release.version = v
+		release.configMaps = append(release.configMaps, cm)
+	}
+
+	compatible := make([]*providerRelease, 0, len(releases))
+
+	var errs error
+
+	for _, release := range releases {
+		if release.err != nil {
+			errs = errors.Join(errs, release.err)
+
+			continue
+		}
+
+		compatible = append(compatible, release)
+	}
+
+	if len(compatible) == 0 {
+		return nil, fmt.Errorf("%w %s: %w", errNoCompatibleProviderFound, contract, errs)
+	}
+
+	sort.Slice(compatible, func(i, j int) bool { return compatible[i].version.GreaterThan(compatible[j].version) })
+
+	return compatible[0].configMaps, nil
+}
+
+// checkProviderConfigMapContract checks that the release series of the version of a transport ConfigMap
+// supports the contract, according to the clusterctl metadata of the provider. It returns the version.
+func checkProviderConfigMapContract(cm corev1.ConfigMap, contract string) (*version.Version, error) {
+	v, err := version.ParseSemantic(cm.Labels[providerConfigMapLabelVersionKey])
+	if err != nil {
+		return nil, fmt.Errorf("invalid provider version label: %w", err)
+	}
+
+	data, ok := cm.Data[providerConfigMapMetadataKey]
+	if !ok {
+		return nil, errNoProviderMetadata
+	}
+
+	metadata := &clusterctlv1.Metadata{}
+	if err := yaml.Unmarshal([]byte(data), metadata); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal provider metadata: %w", err)
+	}
+
+	releaseSeries := metadata.GetReleaseSeriesForVersion(v)
+	if releaseSeries == nil {
+		return nil, fmt.Errorf("%w %s", errNoReleaseSeries, v)
+	}
+
+	if releaseSeries.Contract != contract {
+		return nil, fmt.Errorf("%w %s: release series %d.%d of version %s supports contract %s",
+			errIncompatibleContract, contract, releaseSeries.Major, releaseSeries.Minor, v, releaseSeries.Contract)
+	}
+
+	return v, nil
+}
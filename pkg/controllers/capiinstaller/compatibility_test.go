@@ -0,0 +1,96 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package capiinstaller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var testProviderMetadata = `apiVersion: clusterctl.cluster.x-k8s.io/v1alpha3
+kind: Metadata
+releaseSeries:
+  - major: 2
+    minor: 5
+    contract: v1beta1
+  - major: 2
+    minor: 6
+    contract: v1beta1
+  - major: 3
+    minor: 0
+    contract: v1beta2
+`
+
+func newTestProviderConfigMap(name, version, metadata string) corev1.ConfigMap {
+	cm := corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: defaultCAPINamespace,
+			Labels: map[string]string{
+				providerConfigMapLabelTypeKey:    "infrastructure",
+				providerConfigMapLabelNameKey:    "aws",
+				providerConfigMapLabelVersionKey: version,
+			},
+		},
+		Data: map[string]string{"components": testManifest},
+	}
+
+	if metadata != "" {
+		cm.Data[providerConfigMapMetadataKey] = metadata
+	}
+
+	return cm
+}
+
+var _ = Describe("Provider contract compatibility", func() {
+	It("should select the highest version supporting the contract", func() {
+		configMaps, err := selectCompatibleConfigMaps([]corev1.ConfigMap{
+			newTestProviderConfigMap("aws-v2.5.0", "v2.5.0", testProviderMetadata),
+			newTestProviderConfigMap("aws-v3.0.0", "v3.0.0", testProviderMetadata),
+			newTestProviderConfigMap("aws-v2.6.1", "v2.6.1", testProviderMetadata),
+		}, "v1beta1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(configMaps).To(HaveLen(1))
+		Expect(configMaps[0].Name).To(Equal("aws-v2.6.1"))
+	})
+
+	It("should select every ConfigMap of the selected version", func() {
+		configMaps, err := selectCompatibleConfigMaps([]corev1.ConfigMap{
+			newTestProviderConfigMap("aws-crds", "v2.6.1", testProviderMetadata),
+			newTestProviderConfigMap("aws", "v2.6.1", testProviderMetadata),
+		}, "v1beta1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(configMaps).To(HaveLen(2))
+	})
+
+	DescribeTable("should refuse incompatible versions",
+		func(cm corev1.ConfigMap, expectedErr error) {
+			_, err := selectCompatibleConfigMaps([]corev1.ConfigMap{cm}, "v1beta1")
+			Expect(err).To(MatchError(errNoCompatibleProviderFound))
+			Expect(err).To(MatchError(expectedErr))
+		},
+		Entry("when the release series supports another contract", newTestProviderConfigMap("aws", "v3.0.0", testProviderMetadata), errIncompatibleContract),
+		Entry("when the version has no release series", newTestProviderConfigMap("aws", "v2.7.0", testProviderMetadata), errNoReleaseSeries),
+		Entry("when the ConfigMap has no metadata", newTestProviderConfigMap("aws", "v2.6.1", ""), errNoProviderMetadata),
+	)
+
+	It("should refuse an invalid version label", func() {
+		_, err := selectCompatibleConfigMaps([]corev1.ConfigMap{newTestProviderConfigMap("aws", "latest", testProviderMetadata)}, "v1beta1")
+		Expect(err).To(MatchError(ContainSubstring("invalid provider version label")))
+	})
+})
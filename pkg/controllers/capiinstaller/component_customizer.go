@@ -16,32 +16,121 @@ limitations under the License.
 
 package capiinstaller
 
-func providerNameToImageKey(name string) string {
-	switch name {
-	case "aws":
-		return "aws-cluster-api-controllers"
-	case "azure":
-		return "azure-cluster-api-controllers"
-	case "gcp":
-		return "gcp-cluster-api-controllers"
-	case "vsphere":
-		return "vsphere-cluster-api-controllers"
-	case "ibmcloud":
-		return "ibmcloud-cluster-api-controllers"
-	case "cluster-api":
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// managerImageKeyAnnotation is the annotation of a transport ConfigMap holding the key of the manager image in the images ConfigMap.
+	managerImageKeyAnnotation = "cluster-api.openshift.io/manager-image-key"
+	// managerCommandAnnotation is the annotation of a transport ConfigMap holding the entrypoint of the manager container.
+	managerCommandAnnotation = "cluster-api.openshift.io/manager-command"
+
+	// kubeRBACProxyContainerName is the name of the provider container protecting the manager metrics.
+	kubeRBACProxyContainerName = "kube-rbac-proxy"
+	// kubeRBACProxyImageKey is the key of the kube-rbac-proxy image in the images ConfigMap.
+	kubeRBACProxyImageKey = "kube-rbac-proxy"
+)
+
+// providerManager describes how the manager of a provider is run, as recorded by manifests-gen on its transport ConfigMap.
+type providerManager struct {
+	// imageKey is the key of the manager image in the images ConfigMap.
+	imageKey string
+
+	// command is the entrypoint of the manager container. It is empty to keep the entrypoint of the manifests.
+	command string
+}
+
+// providerManagerOf returns the provider manager recorded on a transport ConfigMap.
+// ConfigMaps generated before the annotations were introduced fall back to the provider name.
+func providerManagerOf(cm corev1.ConfigMap) providerManager {
+	providerName := cm.Labels[providerConfigMapLabelNameKey]
+
+	imageKey, ok := cm.Annotations[managerImageKeyAnnotation]
+	if !ok {
+		imageKey = legacyProviderImageKey(providerName)
+	}
+
+	command, ok := cm.Annotations[managerCommandAnnotation]
+	if !ok {
+		command = legacyProviderCommand(providerName)
+	}
+
+	return providerManager{imageKey: imageKey, command: command}
+}
+
+// legacyProviderImageKey returns the image key of a transport ConfigMap without the image key annotation.
+func legacyProviderImageKey(name string) string {
+	if name == defaultCoreProviderComponentName {
 		return "cluster-capi-controllers"
-	default:
-		return "none"
 	}
+
+	return name + "-cluster-api-controllers"
 }
 
-func providerNameToCommand(name string) string {
+// legacyProviderCommand returns the manager entrypoint of a transport ConfigMap without the command annotation.
+func legacyProviderCommand(name string) string {
 	switch name {
 	case "aws", "gcp", "ibmcloud":
 		return "./bin/cluster-api-provider-" + name + "-controller-manager"
-	case "cluster-api":
+	case defaultCoreProviderComponentName:
 		return "./bin/cluster-api-controller-manager"
 	default:
-		return "/manager"
+		return ""
+	}
+}
+
+// renderProviderDeployment sets the provider manager on a Deployment manifest of a transport ConfigMap.
+// The other manifests are returned unchanged.
+func renderProviderDeployment(manifest string, manager providerManager, images map[string]string) (string, error) {
+	typeMeta := metav1.TypeMeta{}
+	if err := yaml.Unmarshal([]byte(manifest), &typeMeta); err != nil {
+		return "", fmt.Errorf("error parsing manifest: %w", err)
+	}
+
+	if typeMeta.GroupVersionKind() != appsv1.SchemeGroupVersion.WithKind("Deployment") {
+		return manifest, nil
+	}
+
+	deployment := &appsv1.Deployment{}
+	if err := yaml.Unmarshal([]byte(manifest), deployment); err != nil {
+		return "", fmt.Errorf("error parsing Deployment manifest: %w", err)
+	}
+
+	injectProviderManager(deployment, manager, images)
+
+	rendered, err := yaml.Marshal(deployment)
+	if err != nil {
+		return "", fmt.Errorf("error marshalling Deployment %q: %w", deployment.Name, err)
+	}
+
+	return string(rendered), nil
+}
+
+// injectProviderManager sets the image and the entrypoint of the manager container of the Deployment,
+// along with the image of its kube-rbac-proxy container. Images missing from the images ConfigMap are left as shipped.
+func injectProviderManager(deployment *appsv1.Deployment, manager providerManager, images map[string]string) {
+	injectManagerCommand(deployment, manager.command)
+
+	containers := deployment.Spec.Template.Spec.Containers
+
+	for i := range containers {
+		imageKey := ""
+
+		switch containers[i].Name {
+		case managerContainerName:
+			imageKey = manager.imageKey
+		case kubeRBACProxyContainerName:
+			imageKey = kubeRBACProxyImageKey
+		}
+
+		if image, ok := images[imageKey]; ok && imageKey != "" {
+			containers[i].Image = image
+		}
 	}
 }
@@ -0,0 +1,135 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package capiinstaller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Provider manager", func() {
+	It("should resolve the manager from the transport ConfigMap annotations", func() {
+		cm := corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{providerConfigMapLabelNameKey: "openstack"},
+			Annotations: map[string]string{
+				managerImageKeyAnnotation: "openstack-cluster-api-controllers",
+				managerCommandAnnotation:  "./bin/cluster-api-provider-openstack-controller-manager",
+			},
+		}}
+
+		Expect(providerManagerOf(cm)).To(Equal(providerManager{
+			imageKey: "openstack-cluster-api-controllers",
+			command:  "./bin/cluster-api-provider-openstack-controller-manager",
+		}))
+	})
+
+	DescribeTable("should fall back to the provider name without annotations",
+		func(providerName string, expected providerManager) {
+			cm := corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{providerConfigMapLabelNameKey: providerName}}}
+
+			Expect(providerManagerOf(cm)).To(Equal(expected))
+		},
+		Entry("for the core provider", "cluster-api", providerManager{imageKey: "cluster-capi-controllers", command: "./bin/cluster-api-controller-manager"}),
+		Entry("for aws", "aws", providerManager{imageKey: "aws-cluster-api-controllers", command: "./bin/cluster-api-provider-aws-controller-manager"}),
+		Entry("for vsphere", "vsphere", providerManager{imageKey: "vsphere-cluster-api-controllers"}),
+	)
+
+	It("should substitute the image of the annotated image key", func() {
+		cm := corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels:      map[string]string{providerConfigMapLabelNameKey: "openstack"},
+				Annotations: map[string]string{managerImageKeyAnnotation: "openstack-cluster-api-controllers"},
+			},
+			Data: map[string]string{"components": testPlaceholderManifest},
+		}
+
+		components, err := extractProviderComponents(cm, renderConfig{images: map[string]string{"openstack-cluster-api-controllers": "quay.io/openshift/openstack:latest"}})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(components).To(ConsistOf(ContainSubstring("image: quay.io/openshift/openstack:latest")))
+	})
+
+	It("should set the manager command of each ConfigMap on its own Deployments", func() {
+		newConfigMap := func(providerName, command string) corev1.ConfigMap {
+			return corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      map[string]string{providerConfigMapLabelNameKey: providerName},
+					Annotations: map[string]string{managerCommandAnnotation: command},
+				},
+				Data: map[string]string{"components": testPlaceholderManifest},
+			}
+		}
+
+		for _, cm := range []corev1.ConfigMap{newConfigMap("aws", "/aws-manager"), newConfigMap("ipam", "/ipam-manager")} {
+			components, err := extractProviderComponents(cm, renderConfig{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(components).To(ConsistOf(ContainSubstring("- " + cm.Annotations[managerCommandAnnotation])))
+		}
+	})
+
+	It("should set the images by container", func() {
+		d := &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "manager", Image: "to.be/replaced:v99"},
+				{Name: "kube-rbac-proxy", Image: "registry.ci.openshift.org/openshift:kube-rbac-proxy"},
+				{Name: "sidecar", Image: "to.be/replaced:v99"},
+			},
+		}}}}
+
+		injectProviderManager(d, providerManager{imageKey: "aws-cluster-api-controllers"}, map[string]string{
+			"aws-cluster-api-controllers": "quay.io/openshift/aws:latest",
+			"kube-rbac-proxy":             "quay.io/openshift/kube-rbac-proxy:latest",
+		})
+
+		Expect(d.Spec.Template.Spec.Containers).To(HaveExactElements(
+			HaveField("Image", "quay.io/openshift/aws:latest"),
+			HaveField("Image", "quay.io/openshift/kube-rbac-proxy:latest"),
+			HaveField("Image", "to.be/replaced:v99"),
+		))
+	})
+
+	It("should leave the components other than Deployments unchanged", func() {
+		Expect(renderProviderDeployment(testServiceAccountManifest, providerManager{command: "/manager"}, nil)).To(Equal(testServiceAccountManifest))
+	})
+
+	It("should only set the command of the manager container", func() {
+		d := &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "manager", Command: []string{"/manager"}},
+				{Name: "kube-rbac-proxy", Command: []string{"/manager-proxy"}},
+			},
+		}}}}
+
+		injectManagerCommand(d, "./bin/cluster-api-provider-aws-controller-manager")
+
+		Expect(d.Spec.Template.Spec.Containers[0].Command).To(Equal([]string{"./bin/cluster-api-provider-aws-controller-manager"}))
+		Expect(d.Spec.Template.Spec.Containers[1].Command).To(Equal([]string{"/manager-proxy"}))
+	})
+
+	It("should keep the command of the manifests without a manager command", func() {
+		d := &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "manager", Command: []string{"/manager"}}},
+		}}}}
+
+		injectManagerCommand(d, "")
+
+		Expect(d.Spec.Template.Spec.Containers[0].Command).To(Equal([]string{"/manager"}))
+	})
+})
@@ -0,0 +1,180 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package capiinstaller
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/cluster-capi-operator/pkg/operatorstatus"
+)
+
+const (
+	// deploymentDegradedGracePeriod is how long a provider Deployment may be unavailable before the controller is degraded.
+	deploymentDegradedGracePeriod = 5 * time.Minute
+
+	// reasonDeploymentUnavailable denotes a provider Deployment which is not available.
+	reasonDeploymentUnavailable = "DeploymentUnavailable"
+
+	// reasonDeploymentRollingOut denotes a provider Deployment whose rollout is in progress.
+	reasonDeploymentRollingOut = "DeploymentRollingOut"
+
+	// reasonApplyingComponents denotes provider components waiting for a phase to be ready before the next phase is applied.
+	reasonApplyingComponents = "ApplyingComponents"
+
+	// reasonDeploymentDegraded denotes a provider Deployment which has been unavailable for longer than the grace period.
+	reasonDeploymentDegraded = "DeploymentDegraded"
+)
+
+// deploymentsHealth is the health of the applied provider Deployments.
+type deploymentsHealth struct {
+	// unavailable, progressing and degraded describe the Deployments in each state.
+	unavailable []string
+	progressing []string
+	degraded    []string
+
+	// recovering describes the unavailable Deployments which are still within the grace period.
+	recovering []string
+
+	// waiting describes the providers whose components are waiting for a phase to be ready.
+	waiting []string
+
+	// requeueAfter is how long until an unavailable Deployment exceeds the grace period.
+	requeueAfter time.Duration
+}
+
+// newDeploymentsHealth evaluates the health of the given provider Deployments at the given time.
+func newDeploymentsHealth(deployments []*appsv1.Deployment, now time.Time, gracePeriod time.Duration) deploymentsHealth {
+	health := deploymentsHealth{}
+
+	for _, d := range deployments {
+		name := getResourceName(d.Namespace, d.Name)
+
+		if isRollingOut(d) {
+			health.progressing = append(health.progressing, fmt.Sprintf("deployment %s is rolling out: %d of %d replicas updated",
+				name, d.Status.UpdatedReplicas, desiredReplicas(d)))
+		}
+
+		available := getDeploymentCondition(d, appsv1.DeploymentAvailable)
+		if available != nil && available.Status == corev1.ConditionTrue {
+			continue
+		}
+
+		// A Deployment without an Available condition has been unavailable since it was created.
+		since, reason, message := d.CreationTimestamp.Time, "MinimumReplicasUnavailable", "the deployment has no available condition"
+		if available != nil {
+			since, reason, message = available.LastTransitionTime.Time, available.Reason, available.Message
+		}
+
+		health.unavailable = append(health.unavailable, fmt.Sprintf("deployment %s is unavailable: %s: %s", name, reason, message))
+
+		if remaining := gracePeriod - now.Sub(since); remaining > 0 {
+			if health.requeueAfter == 0 || remaining < health.requeueAfter {
+				health.requeueAfter = remaining
+			}
+
+			health.recovering = append(health.recovering, fmt.Sprintf("deployment %s is unavailable: %s: %s", name, reason, message))
+
+			continue
+		}
+
+		health.degraded = append(health.degraded, fmt.Sprintf("deployment %s has been unavailable for more than %s: %s: %s", name, gracePeriod, reason, message))
+	}
+
+	return health
+}
+
+// conditions returns the controller conditions reflecting the health of the Deployments.
+// A Deployment is only reported as unavailable once it exceeds the grace period, the same as for degraded,
+// so that a rollout or a restarting pod does not flap the availability of the controller.
+// Until then, it is reported as progressing.
+func (h deploymentsHealth) conditions() []configv1.ClusterOperatorStatusCondition {
+	available := operatorstatus.NewClusterOperatorStatusCondition(capiInstallerControllerAvailableCondition, configv1.ConditionTrue,
+		operatorstatus.ReasonAsExpected, "CAPI Installer Controller works as expected")
+	if len(h.degraded) > 0 {
+		available = operatorstatus.NewClusterOperatorStatusCondition(capiInstallerControllerAvailableCondition, configv1.ConditionFalse,
+			reasonDeploymentUnavailable, strings.Join(h.degraded, "\n"))
+	}
+
+	progressing := operatorstatus.NewClusterOperatorStatusCondition(capiInstallerControllerProgressingCondition, configv1.ConditionFalse,
+		operatorstatus.ReasonAsExpected, "CAPI Installer Controller works as expected")
+
+	switch {
+	case len(h.waiting) > 0:
+		progressing = operatorstatus.NewClusterOperatorStatusCondition(capiInstallerControllerProgressingCondition, configv1.ConditionTrue,
+			reasonApplyingComponents, strings.Join(slices.Concat(h.waiting, h.progressing, h.recovering), "\n"))
+	case len(h.progressing) > 0:
+		progressing = operatorstatus.NewClusterOperatorStatusCondition(capiInstallerControllerProgressingCondition, configv1.ConditionTrue,
+			reasonDeploymentRollingOut, strings.Join(slices.Concat(h.progressing, h.recovering), "\n"))
+	case len(h.recovering) > 0:
+		progressing = operatorstatus.NewClusterOperatorStatusCondition(capiInstallerControllerProgressingCondition, configv1.ConditionTrue,
+			reasonDeploymentUnavailable, strings.Join(h.recovering, "\n"))
+	}
+
+	degraded := operatorstatus.NewClusterOperatorStatusCondition(capiInstallerControllerDegradedCondition, configv1.ConditionFalse,
+		operatorstatus.ReasonAsExpected, "CAPI Installer Controller works as expected")
+	if len(h.degraded) > 0 {
+		degraded = operatorstatus.NewClusterOperatorStatusCondition(capiInstallerControllerDegradedCondition, configv1.ConditionTrue,
+			reasonDeploymentDegraded, strings.Join(h.degraded, "\n"))
+	}
+
+	return []configv1.ClusterOperatorStatusCondition{available, progressing, degraded}
+}
+
+// waitForPhases records the providers whose components are waiting for a phase to be ready,
+// and requeues at most after the given interval to check them again.
+func (h *deploymentsHealth) waitForPhases(waiting []string, interval time.Duration) {
+	h.waiting = waiting
+
+	if len(waiting) > 0 && (h.requeueAfter == 0 || interval < h.requeueAfter) {
+		h.requeueAfter = interval
+	}
+}
+
+// isRollingOut returns true until the Deployment controller observed the latest spec,
+// and every replica was updated and is available.
+func isRollingOut(d *appsv1.Deployment) bool {
+	return d.Status.ObservedGeneration < d.Generation ||
+		d.Status.UpdatedReplicas < desiredReplicas(d) ||
+		d.Status.Replicas > d.Status.UpdatedReplicas ||
+		d.Status.AvailableReplicas < d.Status.UpdatedReplicas
+}
+
+// desiredReplicas returns the number of replicas of the Deployment, which defaults to 1.
+func desiredReplicas(d *appsv1.Deployment) int32 {
+	if d.Spec.Replicas == nil {
+		return 1
+	}
+
+	return *d.Spec.Replicas
+}
+
+// getDeploymentCondition returns the condition of the given type, or nil when it is not set.
+func getDeploymentCondition(d *appsv1.Deployment, condType appsv1.DeploymentConditionType) *appsv1.DeploymentCondition {
+	for i := range d.Status.Conditions {
+		if d.Status.Conditions[i].Type == condType {
+			return &d.Status.Conditions[i]
+		}
+	}
+
+	return nil
+}
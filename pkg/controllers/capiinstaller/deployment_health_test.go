@@ -0,0 +1,138 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package capiinstaller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+func newTestDeployment(generation, observedGeneration int64, replicas, updated, available int32, availableCondition *appsv1.DeploymentCondition) *appsv1.Deployment {
+	d := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "capa-controller-manager", Namespace: defaultCAPINamespace, Generation: generation},
+		Spec:       appsv1.DeploymentSpec{Replicas: ptr.To(replicas)},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: observedGeneration,
+			Replicas:           replicas,
+			UpdatedReplicas:    updated,
+			AvailableReplicas:  available,
+		},
+	}
+
+	if availableCondition != nil {
+		d.Status.Conditions = []appsv1.DeploymentCondition{*availableCondition}
+	}
+
+	return d
+}
+
+var _ = Describe("Provider Deployment health", func() {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	availableCondition := &appsv1.DeploymentCondition{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue}
+	unavailableSince := func(d time.Duration) *appsv1.DeploymentCondition {
+		return &appsv1.DeploymentCondition{
+			Type:               appsv1.DeploymentAvailable,
+			Status:             corev1.ConditionFalse,
+			Reason:             "MinimumReplicasUnavailable",
+			Message:            "Deployment does not have minimum availability.",
+			LastTransitionTime: metav1.NewTime(now.Add(-d)),
+		}
+	}
+
+	conditionStatuses := func(health deploymentsHealth) map[string]configv1.ConditionStatus {
+		statuses := map[string]configv1.ConditionStatus{}
+		for _, c := range health.conditions() {
+			statuses[string(c.Type)] = c.Status
+		}
+
+		return statuses
+	}
+
+	It("should be available when the rollout is complete", func() {
+		health := newDeploymentsHealth([]*appsv1.Deployment{newTestDeployment(1, 1, 1, 1, 1, availableCondition)}, now, time.Minute)
+
+		Expect(conditionStatuses(health)).To(Equal(map[string]configv1.ConditionStatus{
+			capiInstallerControllerAvailableCondition:   configv1.ConditionTrue,
+			capiInstallerControllerProgressingCondition: configv1.ConditionFalse,
+			capiInstallerControllerDegradedCondition:    configv1.ConditionFalse,
+		}))
+		Expect(health.requeueAfter).To(BeZero())
+	})
+
+	DescribeTable("should be progressing while a rollout is running",
+		func(d *appsv1.Deployment) {
+			health := newDeploymentsHealth([]*appsv1.Deployment{d}, now, time.Minute)
+
+			Expect(conditionStatuses(health)).To(HaveKeyWithValue(capiInstallerControllerProgressingCondition, configv1.ConditionTrue))
+			Expect(conditionStatuses(health)).To(HaveKeyWithValue(capiInstallerControllerAvailableCondition, configv1.ConditionTrue))
+		},
+		Entry("when the generation is not observed yet", newTestDeployment(2, 1, 1, 1, 1, availableCondition)),
+		Entry("when replicas are not updated yet", newTestDeployment(2, 2, 3, 2, 3, availableCondition)),
+		Entry("when updated replicas are not available yet", newTestDeployment(2, 2, 1, 1, 0, availableCondition)),
+	)
+
+	It("should stay available, be progressing, and requeue until the grace period is exceeded", func() {
+		health := newDeploymentsHealth([]*appsv1.Deployment{newTestDeployment(2, 2, 1, 1, 0, unavailableSince(20*time.Second))}, now, time.Minute)
+
+		Expect(conditionStatuses(health)).To(Equal(map[string]configv1.ConditionStatus{
+			capiInstallerControllerAvailableCondition:   configv1.ConditionTrue,
+			capiInstallerControllerProgressingCondition: configv1.ConditionTrue,
+			capiInstallerControllerDegradedCondition:    configv1.ConditionFalse,
+		}))
+		Expect(health.requeueAfter).To(Equal(40 * time.Second))
+	})
+
+	It("should be progressing while a Deployment which is not rolling out is unavailable within the grace period", func() {
+		health := newDeploymentsHealth([]*appsv1.Deployment{newTestDeployment(1, 1, 1, 1, 1, unavailableSince(20*time.Second))}, now, time.Minute)
+
+		Expect(health.conditions()).To(ContainElement(SatisfyAll(
+			HaveField("Type", BeEquivalentTo(capiInstallerControllerProgressingCondition)),
+			HaveField("Status", Equal(configv1.ConditionTrue)),
+			HaveField("Reason", Equal(reasonDeploymentUnavailable)),
+		)))
+		Expect(conditionStatuses(health)).To(HaveKeyWithValue(capiInstallerControllerAvailableCondition, configv1.ConditionTrue))
+	})
+
+	It("should be unavailable and degraded with the deployment name and reason once the grace period is exceeded", func() {
+		health := newDeploymentsHealth([]*appsv1.Deployment{newTestDeployment(2, 2, 1, 1, 0, unavailableSince(2*time.Minute))}, now, time.Minute)
+
+		Expect(conditionStatuses(health)).To(HaveKeyWithValue(capiInstallerControllerAvailableCondition, configv1.ConditionFalse))
+		Expect(conditionStatuses(health)).To(HaveKeyWithValue(capiInstallerControllerDegradedCondition, configv1.ConditionTrue))
+		Expect(health.degraded).To(ConsistOf(ContainSubstring("deployment openshift-cluster-api/capa-controller-manager has been unavailable for more than 1m0s: MinimumReplicasUnavailable")))
+		Expect(health.requeueAfter).To(BeZero())
+	})
+
+	It("should be progressing, and requeue, while components wait for a phase to be ready", func() {
+		health := newDeploymentsHealth(nil, now, time.Minute)
+		health.waitForPhases([]string{"CAPI provider aws is waiting for CustomResourceDefinitions to be ready"}, 10*time.Second)
+
+		Expect(conditionStatuses(health)).To(Equal(map[string]configv1.ConditionStatus{
+			capiInstallerControllerAvailableCondition:   configv1.ConditionTrue,
+			capiInstallerControllerProgressingCondition: configv1.ConditionTrue,
+			capiInstallerControllerDegradedCondition:    configv1.ConditionFalse,
+		}))
+		Expect(health.requeueAfter).To(Equal(10 * time.Second))
+	})
+})
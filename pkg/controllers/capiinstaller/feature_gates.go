@@ -0,0 +1,102 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package capiinstaller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+const (
+	// featureGateObjectName is the name of the cluster-wide FeatureGate.
+	featureGateObjectName = "cluster"
+)
+
+// providerFeatureGate maps an OpenShift FeatureGate to the variable enabling the matching feature of the CAPI providers.
+// The variables are substituted by envsubst into the provider components, e.g. as ${EXP_BOOTSTRAP_FORMAT_IGNITION:=false}.
+type providerFeatureGate struct {
+	// variable is the name of the variable.
+	variable string
+
+	// featureGate enables the variable when enabled. The variable is always enabled when it is empty.
+	featureGate configv1.FeatureGateName
+}
+
+// providerFeatureGates returns the variables enabling the features of the CAPI providers.
+func providerFeatureGates() []providerFeatureGate {
+	return []providerFeatureGate{
+		// OpenShift nodes are always bootstrapped with Ignition.
+		{variable: "EXP_BOOTSTRAP_FORMAT_IGNITION"},
+		// The experimental features of the providers, such as MachinePool (EXP_MACHINE_POOL) and ClusterTopology
+		// (CLUSTER_TOPOLOGY), are not mapped: github.com/openshift/api/features defines no FeatureGate for them,
+		// so they keep the default of the provider components.
+	}
+}
+
+// featureGateVariables returns the value of the variable of each provider feature gate, given the enabled OpenShift FeatureGates.
+func featureGateVariables(gates []providerFeatureGate, enabled []configv1.FeatureGateName) map[string]string {
+	variables := map[string]string{}
+
+	for _, gate := range gates {
+		variables[gate.variable] = strconv.FormatBool(gate.featureGate == "" || slices.Contains(enabled, gate.featureGate))
+	}
+
+	return variables
+}
+
+// enabledFeatureGates returns the FeatureGates enabled for the given version.
+// It returns false when the FeatureGate has no status for the version yet.
+func enabledFeatureGates(featureGate *configv1.FeatureGate, version string) ([]configv1.FeatureGateName, bool) {
+	for _, details := range featureGate.Status.FeatureGates {
+		if details.Version != version {
+			continue
+		}
+
+		enabled := make([]configv1.FeatureGateName, 0, len(details.Enabled))
+		for _, attributes := range details.Enabled {
+			enabled = append(enabled, attributes.Name)
+		}
+
+		return enabled, true
+	}
+
+	return nil, false
+}
+
+// getFeatureGateVariables returns the variables substituted into the provider components,
+// derived from the FeatureGates enabled for the release version of the operator.
+// The feature gated variables are disabled while the FeatureGate has no status for the release version.
+func (r *CapiInstallerController) getFeatureGateVariables(ctx context.Context, log logr.Logger) (map[string]string, error) {
+	featureGate := &configv1.FeatureGate{}
+	if err := r.Get(ctx, client.ObjectKey{Name: featureGateObjectName}, featureGate); err != nil && !apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("unable to get FeatureGate %q: %w", featureGateObjectName, err)
+	}
+
+	enabled, ok := enabledFeatureGates(featureGate, r.ReleaseVersion)
+	if !ok {
+		log.Info("FeatureGate has no status for the release version, disabling feature gated provider features", "version", r.ReleaseVersion)
+	}
+
+	return featureGateVariables(providerFeatureGates(), enabled), nil
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package capiinstaller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/api/features"
+)
+
+var _ = Describe("Provider feature gates", func() {
+	gates := []providerFeatureGate{
+		{variable: "EXP_BOOTSTRAP_FORMAT_IGNITION"},
+		{variable: "EXP_INSTALL", featureGate: features.FeatureGateClusterAPIInstall},
+		{variable: "EXP_INSTALL_IBMCLOUD", featureGate: features.FeatureGateClusterAPIInstallIBMCloud},
+	}
+
+	featureGate := &configv1.FeatureGate{
+		Status: configv1.FeatureGateStatus{
+			FeatureGates: []configv1.FeatureGateDetails{
+				{Version: "4.17.0", Enabled: []configv1.FeatureGateAttributes{{Name: features.FeatureGateClusterAPIInstallIBMCloud}}},
+				{Version: "4.18.0", Enabled: []configv1.FeatureGateAttributes{{Name: features.FeatureGateClusterAPIInstall}}},
+			},
+		},
+	}
+
+	It("should enable the variables of the FeatureGates enabled for the release version", func() {
+		enabled, ok := enabledFeatureGates(featureGate, "4.18.0")
+		Expect(ok).To(BeTrue())
+
+		Expect(featureGateVariables(gates, enabled)).To(Equal(map[string]string{
+			"EXP_BOOTSTRAP_FORMAT_IGNITION": "true",
+			"EXP_INSTALL":                   "true",
+			"EXP_INSTALL_IBMCLOUD":          "false",
+		}))
+	})
+
+	It("should disable the feature gated variables when the FeatureGate has no status for the release version", func() {
+		enabled, ok := enabledFeatureGates(featureGate, "4.19.0")
+		Expect(ok).To(BeFalse())
+
+		Expect(featureGateVariables(gates, enabled)).To(Equal(map[string]string{
+			"EXP_BOOTSTRAP_FORMAT_IGNITION": "true",
+			"EXP_INSTALL":                   "false",
+			"EXP_INSTALL_IBMCLOUD":          "false",
+		}))
+	})
+
+	It("should substitute the variables into the components, and fallback to the defaults", func() {
+		manifests, err := extractManifests(corev1.ConfigMap{Data: map[string]string{
+			"components": "args:\n- --feature-gates=BootstrapFormatIgnition=${EXP_BOOTSTRAP_FORMAT_IGNITION:=false},ClusterResourceSet=${EXP_CLUSTER_RESOURCE_SET:=true}\n",
+		}}, map[string]string{"EXP_BOOTSTRAP_FORMAT_IGNITION": "true"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(manifests).To(ConsistOf("args:\n- --feature-gates=BootstrapFormatIgnition=true,ClusterResourceSet=true\n"))
+	})
+
+	It("should always bootstrap with Ignition", func() {
+		Expect(featureGateVariables(providerFeatureGates(), nil)).To(HaveKeyWithValue("EXP_BOOTSTRAP_FORMAT_IGNITION", "true"))
+	})
+})
@@ -0,0 +1,105 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package capiinstaller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/cluster-capi-operator/pkg/operatorstatus"
+	"github.com/openshift/cluster-capi-operator/pkg/util"
+	"github.com/openshift/library-go/pkg/config/clusteroperator/v1helpers"
+)
+
+const (
+	// imagesConfigMapName is the ConfigMap holding the container images of the release.
+	imagesConfigMapName = "cluster-capi-operator-images"
+
+	// imagesConfigMapKey is the key of the JSON map of container images, also mounted as the images file.
+	imagesConfigMapKey = "images.json"
+
+	// reasonImageOverridden is the reason of the Upgradeable condition while a provider manager image is overridden.
+	reasonImageOverridden = "ImageOverridden"
+)
+
+var errNoImagesData = errors.New("images ConfigMap has no images data")
+
+// getImages returns the container images the provider components are rendered with.
+// The images ConfigMap is watched, so the provider components are rendered again when the images change.
+// The images read on startup are used when it does not exist, e.g. when running the operator locally.
+func (r *CapiInstallerController) getImages(ctx context.Context) (map[string]string, error) {
+	cm := &corev1.ConfigMap{}
+
+	if err := r.Get(ctx, client.ObjectKey{Namespace: defaultCAPINamespace, Name: imagesConfigMapName}, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return r.Images, nil
+		}
+
+		return nil, fmt.Errorf("unable to get images ConfigMap: %w", err)
+	}
+
+	data, ok := cm.Data[imagesConfigMapKey]
+	if !ok {
+		return nil, errNoImagesData
+	}
+
+	images, err := util.ParseImages([]byte(data))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse images ConfigMap: %w", err)
+	}
+
+	return images, nil
+}
+
+// isImagesConfigMap checks whether an object is the images ConfigMap.
+func isImagesConfigMap(obj runtime.Object, namespace string) bool {
+	cO, ok := obj.(client.Object)
+
+	return ok && cO.GetNamespace() == namespace && cO.GetName() == imagesConfigMapName
+}
+
+// upgradeableConditions returns the Upgradeable condition of the installer, which is False while the image
+// of a provider manager is overridden, along with the resulting Upgradeable condition of the ClusterOperator.
+// The ClusterOperator Upgradeable condition is only set True again by the installer when it set it False.
+func upgradeableConditions(co *configv1.ClusterOperator, imageOverrides []string) []configv1.ClusterOperatorStatusCondition {
+	upgradeable := operatorstatus.NewClusterOperatorStatusCondition(capiInstallerControllerUpgradeableCondition, configv1.ConditionTrue,
+		operatorstatus.ReasonAsExpected, "CAPI Installer Controller works as expected")
+	if len(imageOverrides) > 0 {
+		upgradeable = operatorstatus.NewClusterOperatorStatusCondition(capiInstallerControllerUpgradeableCondition, configv1.ConditionFalse,
+			reasonImageOverridden, "The images of CAPI provider managers are overridden, remove the overrides to upgrade: "+strings.Join(imageOverrides, ", "))
+	}
+
+	conditions := slices.Clone(co.Status.Conditions)
+	v1helpers.SetStatusCondition(&conditions, upgradeable)
+
+	clusterOperatorUpgradeable := operatorstatus.UpgradeableCondition(conditions)
+	current := v1helpers.FindStatusCondition(co.Status.Conditions, configv1.OperatorUpgradeable)
+
+	if clusterOperatorUpgradeable.Status == configv1.ConditionTrue && (current == nil || current.Reason != reasonImageOverridden) {
+		return []configv1.ClusterOperatorStatusCondition{upgradeable}
+	}
+
+	return []configv1.ClusterOperatorStatusCondition{upgradeable, clusterOperatorUpgradeable}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package capiinstaller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+var testPlaceholderManifest = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: capa-controller-manager
+spec:
+  template:
+    spec:
+      containers:
+      - name: manager
+        image: to.be/replaced:v99
+`
+
+var _ = Describe("Images", func() {
+	It("should render the provider components with the given images", func() {
+		cm := corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{providerConfigMapLabelNameKey: "aws"}},
+			Data:       map[string]string{"components": testPlaceholderManifest},
+		}
+
+		components, err := extractProviderComponents(cm, renderConfig{images: map[string]string{"aws-cluster-api-controllers": "quay.io/openshift/aws:updated"}})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(components).To(ConsistOf(ContainSubstring("image: quay.io/openshift/aws:updated")))
+	})
+
+	It("should only match the images ConfigMap of the namespace", func() {
+		Expect(isImagesConfigMap(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: defaultCAPINamespace, Name: imagesConfigMapName}}, defaultCAPINamespace)).To(BeTrue())
+		Expect(isImagesConfigMap(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: imagesConfigMapName}}, defaultCAPINamespace)).To(BeFalse())
+	})
+})
+
+var _ = Describe("Upgradeable conditions", func() {
+	newClusterOperator := func(conditions ...configv1.ClusterOperatorStatusCondition) *configv1.ClusterOperator {
+		return &configv1.ClusterOperator{Status: configv1.ClusterOperatorStatus{Conditions: conditions}}
+	}
+
+	It("should only report the installer condition without image overrides", func() {
+		co := newClusterOperator(configv1.ClusterOperatorStatusCondition{Type: configv1.OperatorUpgradeable, Status: configv1.ConditionFalse, Reason: "SyncingFailed"})
+
+		Expect(upgradeableConditions(co, nil)).To(ConsistOf(
+			HaveField("Type", configv1.ClusterStatusConditionType(capiInstallerControllerUpgradeableCondition)),
+		))
+	})
+
+	It("should not be upgradeable while a provider manager image is overridden", func() {
+		conditions := upgradeableConditions(newClusterOperator(), []string{"infrastructure-aws uses quay.io/debug/aws:test"})
+
+		Expect(conditions).To(ConsistOf(
+			And(
+				HaveField("Type", configv1.ClusterStatusConditionType(capiInstallerControllerUpgradeableCondition)),
+				HaveField("Status", configv1.ConditionFalse),
+				HaveField("Message", ContainSubstring("infrastructure-aws uses quay.io/debug/aws:test")),
+			),
+			And(
+				HaveField("Type", configv1.OperatorUpgradeable),
+				HaveField("Status", configv1.ConditionFalse),
+				HaveField("Reason", reasonImageOverridden),
+			),
+		))
+	})
+
+	It("should be upgradeable again once the overrides are removed", func() {
+		co := newClusterOperator(
+			configv1.ClusterOperatorStatusCondition{Type: capiInstallerControllerUpgradeableCondition, Status: configv1.ConditionFalse, Reason: reasonImageOverridden},
+			configv1.ClusterOperatorStatusCondition{Type: configv1.OperatorUpgradeable, Status: configv1.ConditionFalse, Reason: reasonImageOverridden},
+		)
+
+		Expect(upgradeableConditions(co, nil)).To(ContainElement(And(
+			HaveField("Type", configv1.OperatorUpgradeable),
+			HaveField("Status", configv1.ConditionTrue),
+		)))
+	})
+})
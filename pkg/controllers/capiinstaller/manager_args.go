@@ -0,0 +1,101 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package capiinstaller
+
+import (
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+const (
+	// managerContainerName is the name of the provider container running the CAPI manager.
+	managerContainerName = "manager"
+)
+
+// managerFlags are the optional flags accepted by the manager container of a provider Deployment.
+type managerFlags struct {
+	// tls is whether the manager accepts the --tls-min-version and --tls-cipher-suites flags.
+	tls bool
+
+	// leaderElection is whether the manager accepts the --leader-elect flags tuning the leader election.
+	leaderElection bool
+}
+
+// deploymentManagerFlags returns the optional flags accepted by the managers of the provider Deployments,
+// keyed by the name of the Deployment. Deployments missing from the table, such as the Azure Service Operator
+// shipped with the Azure provider, are not given any of them.
+func deploymentManagerFlags() map[string]managerFlags {
+	capiManager := managerFlags{tls: true, leaderElection: true}
+
+	return map[string]managerFlags{
+		"capi-controller-manager":   capiManager,
+		"capa-controller-manager":   capiManager,
+		"capz-controller-manager":   capiManager,
+		"capg-controller-manager":   capiManager,
+		"capibm-controller-manager": capiManager,
+		"capo-controller-manager":   capiManager,
+		"capv-controller-manager":   capiManager,
+	}
+}
+
+// injectManagerArgs sets the arguments on the manager container of the Deployment,
+// replacing any value of the same flags set by the provider manifests.
+func injectManagerArgs(deployment *appsv1.Deployment, args []string) {
+	containers := deployment.Spec.Template.Spec.Containers
+
+	for i := range containers {
+		if containers[i].Name != managerContainerName {
+			continue
+		}
+
+		for _, arg := range args {
+			containers[i].Args = setArg(containers[i].Args, arg)
+		}
+	}
+}
+
+// setArg sets the --flag=value argument, replacing the argument of the same flag if any.
+// Flags passed as a separate value are left in place, as the appended argument takes precedence.
+func setArg(args []string, arg string) []string {
+	flag, _, _ := strings.Cut(arg, "=")
+
+	for i := range args {
+		if strings.HasPrefix(args[i], flag+"=") {
+			args[i] = arg
+
+			return args
+		}
+	}
+
+	return append(args, arg)
+}
+
+// injectManagerCommand sets the entrypoint of the manager container of the Deployment.
+// An empty command keeps the entrypoint set by the provider manifests.
+func injectManagerCommand(deployment *appsv1.Deployment, command string) {
+	if command == "" {
+		return
+	}
+
+	containers := deployment.Spec.Template.Spec.Containers
+
+	for i := range containers {
+		if containers[i].Name == managerContainerName {
+			containers[i].Command = []string{command}
+		}
+	}
+}
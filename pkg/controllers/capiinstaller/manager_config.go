@@ -0,0 +1,247 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package capiinstaller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	operatorv1alpha1 "github.com/openshift/cluster-capi-operator/pkg/apis/operator/v1alpha1"
+)
+
+const (
+	reasonConfigurationValid   = "AsExpected"
+	reasonConfigurationInvalid = "InvalidConfiguration"
+)
+
+var errManagerArgNotAllowed = errors.New("manager argument is not allowed for the provider")
+
+// managerConfig is the operator configuration applied to the manager of a provider.
+type managerConfig struct {
+	// args are the sorted manager arguments.
+	args []string
+
+	// resourceRequests are the resource requests of the manager container.
+	resourceRequests corev1.ResourceList
+
+	// image overrides the image of the manager container when set.
+	image string
+}
+
+// status returns the configuration applied to the manager of the named provider, as reported on the OperatorConfig.
+func (c managerConfig) status(providerName string) operatorv1alpha1.ProviderManagerStatus {
+	return operatorv1alpha1.ProviderManagerStatus{Name: providerName, Image: c.image, Args: c.args, ResourceRequests: c.resourceRequests}
+}
+
+// commonManagerArgs returns the manager arguments accepted by every provider.
+func commonManagerArgs() []string {
+	return []string{"v", "sync-period", "kube-api-qps", "kube-api-burst"}
+}
+
+// providerManagerArgs returns the manager arguments accepted by a provider on top of the common ones,
+// keyed by the name of the provider. Providers missing from the table only accept the common arguments.
+func providerManagerArgs() map[string][]string {
+	return map[string][]string{
+		"cluster-api": {
+			"cluster-concurrency", "machine-concurrency", "machineset-concurrency", "machinedeployment-concurrency",
+			"machinehealthcheck-concurrency", "clusterresourceset-concurrency", "clustercachetracker-concurrency",
+		},
+		"infrastructure-aws":       {"awscluster-concurrency", "awsmachine-concurrency"},
+		"infrastructure-azure":     {"azurecluster-concurrency", "azuremachine-concurrency"},
+		"infrastructure-gcp":       {"gcpcluster-concurrency", "gcpmachine-concurrency"},
+		"infrastructure-openstack": {"openstackcluster-concurrency", "openstackmachine-concurrency"},
+		"infrastructure-vsphere":   {"max-concurrent-reconciles"},
+	}
+}
+
+// managerArgsOf returns the manager arguments set by the configuration, keyed by flag.
+func managerArgsOf(config operatorv1alpha1.ProviderManagerConfig) map[string]string {
+	args := maps.Clone(config.ExtraArgs)
+	if args == nil {
+		args = map[string]string{}
+	}
+
+	if config.LogVerbosity != nil {
+		args["v"] = strconv.Itoa(int(*config.LogVerbosity))
+	}
+
+	if config.SyncPeriod != nil {
+		args["sync-period"] = config.SyncPeriod.Duration.String()
+	}
+
+	for _, concurrency := range config.Concurrency {
+		args[concurrency.Controller+"-concurrency"] = strconv.Itoa(int(concurrency.Workers))
+	}
+
+	return args
+}
+
+// newManagerConfig resolves the configuration of the manager of the provider from the operator configuration.
+// The defaults which the provider does not accept are skipped, while the provider settings which it does not accept
+// make the configuration invalid.
+func newManagerConfig(config *operatorv1alpha1.OperatorConfig, providerName string) (managerConfig, error) {
+	if config == nil {
+		return managerConfig{}, nil
+	}
+
+	allowed := slices.Concat(commonManagerArgs(), providerManagerArgs()[providerName])
+	args := map[string]string{}
+
+	for flag, value := range managerArgsOf(config.Spec.Defaults) {
+		if slices.Contains(allowed, flag) {
+			args[flag] = value
+		}
+	}
+
+	resourceRequests := maps.Clone(config.Spec.Defaults.ResourceRequests)
+
+	var (
+		image string
+		errs  error
+	)
+
+	for _, override := range config.Spec.Providers {
+		if override.Name != providerName {
+			continue
+		}
+
+		image = override.Image
+
+		for flag, value := range managerArgsOf(override.ProviderManagerConfig) {
+			if !slices.Contains(allowed, flag) {
+				errs = errors.Join(errs, fmt.Errorf("%w: %s", errManagerArgNotAllowed, flag))
+
+				continue
+			}
+
+			args[flag] = value
+		}
+
+		if override.ResourceRequests != nil {
+			if resourceRequests == nil {
+				resourceRequests = corev1.ResourceList{}
+			}
+
+			maps.Copy(resourceRequests, override.ResourceRequests)
+		}
+	}
+
+	if errs != nil {
+		return managerConfig{}, fmt.Errorf("invalid configuration of provider %q: %w", providerName, errs)
+	}
+
+	managerArgs := make([]string, 0, len(args))
+	for flag, value := range args {
+		managerArgs = append(managerArgs, "--"+flag+"="+value)
+	}
+
+	slices.Sort(managerArgs)
+
+	return managerConfig{args: managerArgs, resourceRequests: resourceRequests, image: image}, nil
+}
+
+// injectManagerConfig applies the operator configuration to the manager container of the Deployment.
+func injectManagerConfig(deployment *appsv1.Deployment, config managerConfig) {
+	injectManagerArgs(deployment, config.args)
+
+	containers := deployment.Spec.Template.Spec.Containers
+
+	for i := range containers {
+		if containers[i].Name != managerContainerName {
+			continue
+		}
+
+		if config.image != "" {
+			containers[i].Image = config.image
+		}
+
+		if len(config.resourceRequests) == 0 {
+			continue
+		}
+
+		if containers[i].Resources.Requests == nil {
+			containers[i].Resources.Requests = corev1.ResourceList{}
+		}
+
+		maps.Copy(containers[i].Resources.Requests, config.resourceRequests)
+	}
+}
+
+// getOperatorConfig returns the OperatorConfig singleton, or nil when it does not exist.
+func (r *CapiInstallerController) getOperatorConfig(ctx context.Context) (*operatorv1alpha1.OperatorConfig, error) {
+	config := &operatorv1alpha1.OperatorConfig{}
+
+	if err := r.Get(ctx, client.ObjectKey{Name: operatorv1alpha1.OperatorConfigName}, config); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil //nolint:nilnil
+		}
+
+		return nil, fmt.Errorf("unable to get operator config: %w", err)
+	}
+
+	return config, nil
+}
+
+// updateOperatorConfigStatus reports the configuration applied to the manager of every installed provider,
+// and whether the configuration is valid, on the OperatorConfig.
+func (r *CapiInstallerController) updateOperatorConfigStatus(ctx context.Context, config *operatorv1alpha1.OperatorConfig,
+	providers []operatorv1alpha1.ProviderManagerStatus, invalid error) error {
+	if config == nil {
+		return nil
+	}
+
+	updated := config.DeepCopy()
+	updated.Status.ObservedGeneration = config.Generation
+	updated.Status.Providers = providers
+
+	condition := metav1.Condition{
+		Type:               operatorv1alpha1.OperatorConfigValidCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             reasonConfigurationValid,
+		Message:            "The configuration of every provider manager is valid",
+		ObservedGeneration: config.Generation,
+	}
+
+	if invalid != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = reasonConfigurationInvalid
+		condition.Message = fmt.Sprintf("The invalid provider configurations are not applied: %v", invalid)
+	}
+
+	meta.SetStatusCondition(&updated.Status.Conditions, condition)
+
+	if equality.Semantic.DeepEqual(config.Status, updated.Status) {
+		return nil
+	}
+
+	if err := r.Status().Patch(ctx, updated, client.MergeFrom(config)); err != nil {
+		return fmt.Errorf("unable to update operator config status: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package capiinstaller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	operatorv1alpha1 "github.com/openshift/cluster-capi-operator/pkg/apis/operator/v1alpha1"
+)
+
+var _ = Describe("Provider manager configuration", func() {
+	newOperatorConfig := func(providers ...operatorv1alpha1.ProviderManagerOverride) *operatorv1alpha1.OperatorConfig {
+		return &operatorv1alpha1.OperatorConfig{Spec: operatorv1alpha1.OperatorConfigSpec{
+			Defaults: operatorv1alpha1.ProviderManagerConfig{
+				LogVerbosity: ptr.To[int32](2),
+				SyncPeriod:   &metav1.Duration{Duration: 10 * time.Minute},
+				Concurrency:  []operatorv1alpha1.ControllerConcurrency{{Controller: "machine", Workers: 5}},
+				ResourceRequests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("20m"),
+					corev1.ResourceMemory: resource.MustParse("100Mi"),
+				},
+			},
+			Providers: providers,
+		}}
+	}
+
+	It("should not configure the managers without an operator configuration", func() {
+		Expect(newManagerConfig(nil, "cluster-api")).To(Equal(managerConfig{}))
+	})
+
+	It("should only apply the defaults allowed for the provider", func() {
+		config, err := newManagerConfig(newOperatorConfig(), "infrastructure-aws")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(config.args).To(Equal([]string{"--sync-period=10m0s", "--v=2"}))
+	})
+
+	It("should apply the provider settings over the defaults", func() {
+		config, err := newManagerConfig(newOperatorConfig(operatorv1alpha1.ProviderManagerOverride{
+			Name: "cluster-api",
+			ProviderManagerConfig: operatorv1alpha1.ProviderManagerConfig{
+				LogVerbosity:     ptr.To[int32](4),
+				ExtraArgs:        map[string]string{"kube-api-qps": "40"},
+				ResourceRequests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("200Mi")},
+			},
+		}), "cluster-api")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(config.args).To(Equal([]string{"--kube-api-qps=40", "--machine-concurrency=5", "--sync-period=10m0s", "--v=4"}))
+		Expect(config.resourceRequests).To(Equal(corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("20m"),
+			corev1.ResourceMemory: resource.MustParse("200Mi"),
+		}))
+	})
+
+	It("should reject the provider settings which are not allowed for the provider", func() {
+		_, err := newManagerConfig(newOperatorConfig(operatorv1alpha1.ProviderManagerOverride{
+			Name: "infrastructure-aws",
+			ProviderManagerConfig: operatorv1alpha1.ProviderManagerConfig{
+				Concurrency: []operatorv1alpha1.ControllerConcurrency{{Controller: "awsmachine", Workers: 10}},
+				ExtraArgs:   map[string]string{"leader-elect": "false"},
+			},
+		}), "infrastructure-aws")
+
+		Expect(err).To(MatchError(errManagerArgNotAllowed))
+		Expect(err).To(MatchError(ContainSubstring("leader-elect")))
+	})
+
+	It("should override the image of the provider manager", func() {
+		config, err := newManagerConfig(newOperatorConfig(operatorv1alpha1.ProviderManagerOverride{
+			Name:  "infrastructure-aws",
+			Image: "quay.io/debug/aws:test",
+		}), "infrastructure-aws")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(config.status("infrastructure-aws")).To(HaveField("Image", "quay.io/debug/aws:test"))
+
+		other, err := newManagerConfig(newOperatorConfig(operatorv1alpha1.ProviderManagerOverride{
+			Name:  "infrastructure-aws",
+			Image: "quay.io/debug/aws:test",
+		}), "cluster-api")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(other.image).To(BeEmpty())
+	})
+
+	It("should apply the configuration to the manager container", func() {
+		d := &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "manager", Args: []string{"--v=1"}, Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("10m"),
+					corev1.ResourceMemory: resource.MustParse("50Mi"),
+				}}},
+				{Name: "kube-rbac-proxy"},
+			},
+		}}}}
+
+		injectManagerConfig(d, managerConfig{
+			image:            "quay.io/debug/cluster-api:test",
+			args:             []string{"--v=4"},
+			resourceRequests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("200Mi")},
+		})
+
+		Expect(d.Spec.Template.Spec.Containers[0].Image).To(Equal("quay.io/debug/cluster-api:test"))
+		Expect(d.Spec.Template.Spec.Containers[0].Args).To(Equal([]string{"--v=4"}))
+		Expect(d.Spec.Template.Spec.Containers[0].Resources.Requests).To(Equal(corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("10m"),
+			corev1.ResourceMemory: resource.MustParse("200Mi"),
+		}))
+		Expect(d.Spec.Template.Spec.Containers[1].Resources.Requests).To(BeEmpty())
+	})
+})
@@ -0,0 +1,127 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package capiinstaller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/cluster-capi-operator/pkg/util"
+)
+
+const (
+	coreProviderType           = "core"
+	infrastructureProviderType = "infrastructure"
+
+	// providersConfigMapName is the optional ConfigMap listing the additional providers to install.
+	providersConfigMapName = "cluster-capi-operator-providers"
+
+	// providersConfigMapKey is the key of the YAML list of additional providers, also mounted as the providers file.
+	providersConfigMapKey = "providers.yaml"
+)
+
+// getDesiredProviders returns the CAPI providers to install for this cluster, with the additional providers
+// currently listed in the providers ConfigMap.
+// The providers ConfigMap is watched, so the providers are installed or pruned when it changes.
+// The providers file is read instead when it does not exist, e.g. when running the operator locally.
+func (r *CapiInstallerController) getDesiredProviders(ctx context.Context) ([]util.Provider, error) {
+	cm := &corev1.ConfigMap{}
+
+	if err := r.Get(ctx, client.ObjectKey{Namespace: defaultCAPINamespace, Name: providersConfigMapName}, cm); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("unable to get providers ConfigMap: %w", err)
+		}
+
+		additionalProviders, err := r.readProvidersFile()
+		if err != nil {
+			return nil, err
+		}
+
+		return desiredProviders(r.Platform, additionalProviders), nil
+	}
+
+	data, ok := cm.Data[providersConfigMapKey]
+	if !ok {
+		return desiredProviders(r.Platform, nil), nil
+	}
+
+	additionalProviders, err := util.ParseAdditionalProviders([]byte(data))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse providers ConfigMap: %w", err)
+	}
+
+	return desiredProviders(r.Platform, additionalProviders), nil
+}
+
+// readProvidersFile returns the additional providers listed in the providers file.
+// No additional provider is installed when the file is not set, or does not exist.
+func (r *CapiInstallerController) readProvidersFile() ([]util.Provider, error) {
+	if r.ProvidersFile == "" {
+		return nil, nil
+	}
+
+	providers, err := util.ReadAdditionalProvidersFile(r.ProvidersFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error reading additional providers: %w", err)
+	}
+
+	return providers, nil
+}
+
+// isProvidersConfigMap checks whether an object is the providers ConfigMap.
+func isProvidersConfigMap(obj runtime.Object, namespace string) bool {
+	cO, ok := obj.(client.Object)
+
+	return ok && cO.GetNamespace() == namespace && cO.GetName() == providersConfigMapName
+}
+
+// desiredProviders returns the CAPI providers to install for this cluster.
+// We always want to install the core provider, which in our case is the default cluster-api core provider.
+// We also want to install the infrastructure provider that matches the currently detected platform the cluster is running on,
+// followed by the additional providers in the order they are configured.
+func desiredProviders(platform configv1.PlatformType, additionalProviders []util.Provider) []util.Provider {
+	providers := []util.Provider{
+		{Type: coreProviderType, Name: defaultCoreProviderComponentName},
+		{Type: infrastructureProviderType, Name: platformToProviderConfigMapLabelNameValue(platform)},
+	}
+
+	return append(providers, additionalProviders...)
+}
+
+// providerConfigMapLabels returns the labels selecting the transport ConfigMaps of a provider.
+// The version label is only matched when the provider selects a version.
+func providerConfigMapLabels(provider util.Provider) client.MatchingLabels {
+	labels := client.MatchingLabels{
+		providerConfigMapLabelNameKey: provider.Name,
+		providerConfigMapLabelTypeKey: provider.Type,
+	}
+
+	if provider.Version != "" {
+		labels[providerConfigMapLabelVersionKey] = provider.Version
+	}
+
+	return labels
+}
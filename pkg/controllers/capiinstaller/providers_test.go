@@ -0,0 +1,126 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package capiinstaller
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/cluster-capi-operator/pkg/operatorstatus"
+	"github.com/openshift/cluster-capi-operator/pkg/util"
+)
+
+var _ = Describe("Desired providers", func() {
+	ipamProvider := util.Provider{Type: "ipam", Name: "in-cluster", Version: "v0.1.0"}
+
+	It("should install the core, infrastructure and additional providers in order", func() {
+		Expect(desiredProviders(configv1.PowerVSPlatformType, []util.Provider{ipamProvider})).To(Equal([]util.Provider{
+			{Type: "core", Name: "cluster-api"},
+			{Type: "infrastructure", Name: "ibmcloud"},
+			ipamProvider,
+		}))
+	})
+
+	It("should only select the ConfigMaps of the configured version", func() {
+		Expect(providerConfigMapLabels(ipamProvider)).To(Equal(client.MatchingLabels{
+			providerConfigMapLabelTypeKey:    "ipam",
+			providerConfigMapLabelNameKey:    "in-cluster",
+			providerConfigMapLabelVersionKey: "v0.1.0",
+		}))
+		Expect(providerConfigMapLabels(util.Provider{Type: "core", Name: "cluster-api"})).NotTo(HaveKey(providerConfigMapLabelVersionKey))
+	})
+
+	DescribeTable("should watch the objects of any provider",
+		func(namespace string, labels map[string]string, expected bool) {
+			cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: namespace, Labels: labels}}
+
+			Expect(isTransportConfigMap(cm, defaultCAPINamespace) || isOwnedProviderComponent(cm, defaultCAPINamespace)).To(Equal(expected))
+		},
+		Entry("transport ConfigMap of the core provider", defaultCAPINamespace,
+			map[string]string{providerConfigMapLabelTypeKey: "core", providerConfigMapLabelNameKey: "cluster-api", providerConfigMapLabelVersionKey: "v1.8.4"}, true),
+		Entry("transport ConfigMap of a provider which is not installed yet", defaultCAPINamespace,
+			map[string]string{providerConfigMapLabelTypeKey: "ipam", providerConfigMapLabelNameKey: "in-cluster"}, true),
+		Entry("transport ConfigMap of another namespace", "default",
+			map[string]string{providerConfigMapLabelTypeKey: "infrastructure", providerConfigMapLabelNameKey: "aws"}, false),
+		Entry("ConfigMap without the provider type", defaultCAPINamespace, map[string]string{providerConfigMapLabelNameKey: "aws"}, false),
+		Entry("component of a provider", defaultCAPINamespace, map[string]string{ownedProviderComponentName: "ipam-in-cluster"}, true),
+		Entry("unlabelled ConfigMap", defaultCAPINamespace, nil, false),
+	)
+})
+
+var _ = Describe("Reading the desired providers", func() {
+	newController := func(providersFile string, objs ...client.Object) *CapiInstallerController {
+		return &CapiInstallerController{
+			ClusterOperatorStatusClient: operatorstatus.ClusterOperatorStatusClient{
+				Client:           fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(objs...).Build(),
+				ManagedNamespace: defaultCAPINamespace,
+			},
+			Platform:      configv1.AWSPlatformType,
+			ProvidersFile: providersFile,
+		}
+	}
+
+	providersConfigMap := func(data map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: providersConfigMapName, Namespace: defaultCAPINamespace}, Data: data}
+	}
+
+	It("should install the additional providers listed in the providers ConfigMap", func() {
+		r := newController("", providersConfigMap(map[string]string{providersConfigMapKey: "- type: ipam\n  name: in-cluster\n"}))
+
+		Expect(r.getDesiredProviders(context.Background())).To(ContainElement(util.Provider{Type: "ipam", Name: "in-cluster"}))
+	})
+
+	It("should not install any additional provider without providers data", func() {
+		r := newController("", providersConfigMap(nil))
+
+		Expect(r.getDesiredProviders(context.Background())).To(HaveLen(2))
+	})
+
+	It("should fail on an invalid providers ConfigMap", func() {
+		r := newController("", providersConfigMap(map[string]string{providersConfigMapKey: "- type: infrastructure\n  name: gcp\n"}))
+
+		_, err := r.getDesiredProviders(context.Background())
+		Expect(err).To(MatchError(ContainSubstring("unable to parse providers ConfigMap")))
+	})
+
+	It("should read the providers file when the providers ConfigMap does not exist", func() {
+		providersFile := filepath.Join(GinkgoT().TempDir(), "providers.yaml")
+		Expect(os.WriteFile(providersFile, []byte("- type: addon\n  name: helm\n"), 0o600)).To(Succeed())
+
+		Expect(newController(providersFile).getDesiredProviders(context.Background())).To(ContainElement(util.Provider{Type: "addon", Name: "helm"}))
+	})
+
+	It("should not install any additional provider when the providers file does not exist", func() {
+		r := newController(filepath.Join(GinkgoT().TempDir(), "providers.yaml"))
+
+		Expect(r.getDesiredProviders(context.Background())).To(HaveLen(2))
+	})
+
+	It("should only match the providers ConfigMap of the namespace", func() {
+		Expect(isProvidersConfigMap(providersConfigMap(nil), defaultCAPINamespace)).To(BeTrue())
+		Expect(isProvidersConfigMap(providersConfigMap(nil), "default")).To(BeFalse())
+	})
+})
@@ -0,0 +1,155 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package capiinstaller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+const (
+	// proxyObjectName is the name of the cluster-wide Proxy.
+	proxyObjectName = "cluster"
+
+	// trustedCABundleConfigMapName is the ConfigMap the trusted CA bundle of the cluster is injected into.
+	trustedCABundleConfigMapName = "trusted-ca-bundle"
+
+	// trustedCABundleKey is the key of the injected trusted CA bundle.
+	trustedCABundleKey = "ca-bundle.crt"
+
+	// trustedCABundleVolumeName is the name of the volume mounting the trusted CA bundle in the provider Deployments.
+	trustedCABundleVolumeName = "trusted-ca-bundle"
+
+	// trustedCABundleMountPath is where the trusted CA bundle replaces the CA bundle of the provider containers.
+	trustedCABundleMountPath = "/etc/pki/ca-trust/extracted/pem"
+
+	// trustedCABundleFileName is the name of the CA bundle file read by the provider containers.
+	trustedCABundleFileName = "tls-ca-bundle.pem"
+
+	// trustedCABundleHashAnnotation records the hash of the trusted CA bundle on the pod template,
+	// so that the provider Deployments are rolled out when the bundle changes.
+	trustedCABundleHashAnnotation = "cluster-api.openshift.io/trusted-ca-bundle-hash"
+)
+
+// proxyConfig is the cluster-wide proxy configuration injected into the provider Deployments.
+type proxyConfig struct {
+	// env are the proxy environment variables of the provider containers.
+	env []corev1.EnvVar
+
+	// trustedCABundleHash is the hash of the trusted CA bundle, or empty when no bundle is injected yet.
+	trustedCABundleHash string
+}
+
+// newProxyConfig returns the proxy configuration of the given Proxy and trusted CA bundle.
+func newProxyConfig(proxy *configv1.Proxy, trustedCABundle string) proxyConfig {
+	config := proxyConfig{}
+
+	for _, env := range []corev1.EnvVar{
+		{Name: "HTTP_PROXY", Value: proxy.Status.HTTPProxy},
+		{Name: "HTTPS_PROXY", Value: proxy.Status.HTTPSProxy},
+		{Name: "NO_PROXY", Value: proxy.Status.NoProxy},
+	} {
+		if env.Value != "" {
+			config.env = append(config.env, env)
+		}
+	}
+
+	if trustedCABundle != "" {
+		hash := sha256.Sum256([]byte(trustedCABundle))
+		config.trustedCABundleHash = hex.EncodeToString(hash[:])
+	}
+
+	return config
+}
+
+// getProxyConfig returns the proxy configuration of the cluster-wide Proxy, and of the trusted CA bundle
+// injected into the trusted CA bundle ConfigMap of the namespace.
+func (r *CapiInstallerController) getProxyConfig(ctx context.Context) (proxyConfig, error) {
+	proxy := &configv1.Proxy{}
+	if err := r.Get(ctx, client.ObjectKey{Name: proxyObjectName}, proxy); err != nil && !apierrors.IsNotFound(err) {
+		return proxyConfig{}, fmt.Errorf("unable to get Proxy %q: %w", proxyObjectName, err)
+	}
+
+	trustedCABundle := &corev1.ConfigMap{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: defaultCAPINamespace, Name: trustedCABundleConfigMapName}, trustedCABundle); err != nil && !apierrors.IsNotFound(err) {
+		return proxyConfig{}, fmt.Errorf("unable to get ConfigMap %s/%s: %w", defaultCAPINamespace, trustedCABundleConfigMapName, err)
+	}
+
+	return newProxyConfig(proxy, trustedCABundle.Data[trustedCABundleKey]), nil
+}
+
+// injectProxyConfig injects the proxy environment variables into every container of the Deployment,
+// and mounts the trusted CA bundle once it is injected.
+func injectProxyConfig(deployment *appsv1.Deployment, config proxyConfig) {
+	podSpec := &deployment.Spec.Template.Spec
+
+	for i := range podSpec.Containers {
+		for _, env := range config.env {
+			podSpec.Containers[i].Env = setEnvVar(podSpec.Containers[i].Env, env)
+		}
+	}
+
+	// Mounting an empty bundle would replace the CA bundle of the containers, so it is only mounted once injected.
+	if config.trustedCABundleHash == "" {
+		return
+	}
+
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: trustedCABundleVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: trustedCABundleConfigMapName},
+				Items:                []corev1.KeyToPath{{Key: trustedCABundleKey, Path: trustedCABundleFileName}},
+			},
+		},
+	})
+
+	for i := range podSpec.Containers {
+		podSpec.Containers[i].VolumeMounts = append(podSpec.Containers[i].VolumeMounts, corev1.VolumeMount{
+			Name:      trustedCABundleVolumeName,
+			MountPath: trustedCABundleMountPath,
+			ReadOnly:  true,
+		})
+	}
+
+	if deployment.Spec.Template.Annotations == nil {
+		deployment.Spec.Template.Annotations = map[string]string{}
+	}
+
+	deployment.Spec.Template.Annotations[trustedCABundleHashAnnotation] = config.trustedCABundleHash
+}
+
+// setEnvVar sets the environment variable, replacing the variable of the same name if any.
+func setEnvVar(envs []corev1.EnvVar, env corev1.EnvVar) []corev1.EnvVar {
+	for i := range envs {
+		if envs[i].Name == env.Name {
+			envs[i] = env
+
+			return envs
+		}
+	}
+
+	return append(envs, env)
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package capiinstaller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+var _ = Describe("Proxy injection", func() {
+	proxy := &configv1.Proxy{Status: configv1.ProxyStatus{
+		HTTPSProxy: "https://proxy.example.com:3128",
+		NoProxy:    ".cluster.local,.svc,10.0.0.0/16",
+	}}
+
+	newDeployment := func() *appsv1.Deployment {
+		return &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "manager", Env: []corev1.EnvVar{{Name: "NO_PROXY", Value: "localhost"}, {Name: "LOG_LEVEL", Value: "2"}}},
+				{Name: "kube-rbac-proxy"},
+			},
+		}}}}
+	}
+
+	It("should inject the proxy environment variables which are set", func() {
+		d := newDeployment()
+		injectProxyConfig(d, newProxyConfig(proxy, ""))
+
+		Expect(d.Spec.Template.Spec.Containers[0].Env).To(Equal([]corev1.EnvVar{
+			{Name: "NO_PROXY", Value: ".cluster.local,.svc,10.0.0.0/16"},
+			{Name: "LOG_LEVEL", Value: "2"},
+			{Name: "HTTPS_PROXY", Value: "https://proxy.example.com:3128"},
+		}))
+		Expect(d.Spec.Template.Spec.Containers[1].Env).To(ConsistOf(
+			corev1.EnvVar{Name: "HTTPS_PROXY", Value: "https://proxy.example.com:3128"},
+			corev1.EnvVar{Name: "NO_PROXY", Value: ".cluster.local,.svc,10.0.0.0/16"},
+		))
+	})
+
+	It("should not mount the trusted CA bundle until it is injected", func() {
+		d := newDeployment()
+		injectProxyConfig(d, newProxyConfig(&configv1.Proxy{}, ""))
+
+		Expect(d.Spec.Template.Spec.Volumes).To(BeEmpty())
+		Expect(d.Spec.Template.Annotations).NotTo(HaveKey(trustedCABundleHashAnnotation))
+		Expect(d.Spec.Template.Spec.Containers[0].Env).To(HaveLen(2))
+	})
+
+	It("should mount the trusted CA bundle, and roll out when it changes", func() {
+		d := newDeployment()
+		injectProxyConfig(d, newProxyConfig(proxy, "bundle"))
+
+		Expect(d.Spec.Template.Spec.Volumes).To(ConsistOf(HaveField("ConfigMap.Name", trustedCABundleConfigMapName)))
+		Expect(d.Spec.Template.Spec.Containers).To(HaveEach(HaveField("VolumeMounts", ConsistOf(corev1.VolumeMount{
+			Name: trustedCABundleVolumeName, MountPath: trustedCABundleMountPath, ReadOnly: true,
+		}))))
+
+		updated := newDeployment()
+		injectProxyConfig(updated, newProxyConfig(proxy, "updated bundle"))
+
+		Expect(d.Spec.Template.Annotations).To(HaveKey(trustedCABundleHashAnnotation))
+		Expect(updated.Spec.Template.Annotations[trustedCABundleHashAnnotation]).NotTo(Equal(d.Spec.Template.Annotations[trustedCABundleHashAnnotation]))
+	})
+})
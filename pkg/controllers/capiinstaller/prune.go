@@ -0,0 +1,419 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package capiinstaller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	corev1applyconfigs "k8s.io/client-go/applyconfigurations/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/openshift/cluster-capi-operator/pkg/util"
+)
+
+// errUnexpectedListType is returned when a list of provider components holds an unexpected type.
+var errUnexpectedListType = errors.New("unexpected list type")
+
+const (
+	// inventoryConfigMapPrefix is the name prefix of the ConfigMaps listing the components applied for each provider.
+	inventoryConfigMapPrefix = "capi-installer-inventory-"
+
+	// inventoryDataKey is the key of the list of applied components within an inventory ConfigMap.
+	inventoryDataKey = "components"
+
+	// inventoryFieldOwner is the field manager used to apply the inventory ConfigMaps.
+	inventoryFieldOwner = "capi-installer-controller"
+)
+
+// inventoryEntry identifies a provider component applied to the cluster.
+type inventoryEntry struct {
+	Group     string `json:"group"`
+	Version   string `json:"version"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+// key identifies the component regardless of its API version, so that
+// a component served at a new version by a new provider release is not pruned.
+func (e inventoryEntry) key() string {
+	return fmt.Sprintf("%s/%s/%s/%s", e.Group, e.Kind, e.Namespace, e.Name)
+}
+
+// groupVersionKind returns the GroupVersionKind of the component.
+func (e inventoryEntry) groupVersionKind() schema.GroupVersionKind {
+	return schema.GroupVersionKind{Group: e.Group, Version: e.Version, Kind: e.Kind}
+}
+
+// newInventory returns the inventory entries of the given provider components manifests, sorted by key.
+func newInventory(scheme *runtime.Scheme, components []string) ([]inventoryEntry, error) {
+	inventory := make([]inventoryEntry, 0, len(components))
+
+	for i, m := range components {
+		u, err := yamlToUnstructured(scheme, m)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing provider component at position %d to unstructured: %w", i, err)
+		}
+
+		gvk := u.GroupVersionKind()
+		inventory = append(inventory, inventoryEntry{
+			Group:     gvk.Group,
+			Version:   gvk.Version,
+			Kind:      gvk.Kind,
+			Namespace: u.GetNamespace(),
+			Name:      u.GetName(),
+		})
+	}
+
+	sort.Slice(inventory, func(i, j int) bool { return inventory[i].key() < inventory[j].key() })
+
+	return inventory, nil
+}
+
+// staleInventory returns the entries of the previous inventory which are not part of the desired inventory.
+// Entries found more than once in the previous inventory are only returned once.
+func staleInventory(previous, desired []inventoryEntry) []inventoryEntry {
+	seenKeys := make(map[string]bool, len(desired))
+	for _, e := range desired {
+		seenKeys[e.key()] = true
+	}
+
+	stale := []inventoryEntry{}
+
+	for _, e := range previous {
+		if seenKeys[e.key()] {
+			continue
+		}
+
+		seenKeys[e.key()] = true
+
+		stale = append(stale, e)
+	}
+
+	return stale
+}
+
+// providerComponentName returns the value of the cluster.x-k8s.io/provider label carried by the components of a provider.
+func providerComponentName(providerType, providerName string) string {
+	if providerName == defaultCoreProviderComponentName {
+		return defaultCoreProviderComponentName
+	}
+
+	return fmt.Sprintf("%s-%s", providerType, providerName)
+}
+
+// pruneProviderComponents deletes the components of a provider which are no longer part of its components,
+// and records the components now applied in the inventory ConfigMap of the provider.
+// The stale components are found by their provider label, among the kinds of components watched by the controller,
+// so that the components left by a previous release are pruned even without an inventory.
+// The inventory adds the components of the other kinds applied by a previous reconcile.
+// Only components still carrying the provider label are deleted, and CRDs are kept while they still have instances.
+func (r *CapiInstallerController) pruneProviderComponents(ctx context.Context, log logr.Logger, providerType, providerName string, components []string) error {
+	desired, err := newInventory(r.Scheme, components)
+	if err != nil {
+		return fmt.Errorf("error building inventory: %w", err)
+	}
+
+	previous, err := r.getInventory(ctx, providerName)
+	if err != nil {
+		return err
+	}
+
+	ownerLabel := providerComponentName(providerType, providerName)
+
+	live, err := r.listProviderComponents(ctx, client.MatchingLabels{ownedProviderComponentName: ownerLabel})
+	if err != nil {
+		return err
+	}
+
+	isOwned := func(label string) bool { return label == ownerLabel }
+
+	for _, e := range staleInventory(append(previous, live...), desired) {
+		kept, err := r.pruneComponent(ctx, log, e, isOwned)
+		if err != nil {
+			return err
+		}
+
+		// Components which could not be pruned yet are kept in the inventory, so that they are pruned later.
+		if kept {
+			desired = append(desired, e)
+		}
+	}
+
+	return r.applyInventory(ctx, providerName, desired)
+}
+
+// pruneDroppedProviders deletes the components of the providers which are no longer desired.
+// Only the components recorded in the inventory ConfigMaps of the dropped providers are considered,
+// so that the components of providers installed by others, such as HyperShift, are never deleted.
+// The inventory ConfigMaps are deleted once their components are.
+func (r *CapiInstallerController) pruneDroppedProviders(ctx context.Context, log logr.Logger, providers []util.Provider) error {
+	desiredLabels := make([]string, 0, len(providers))
+	desiredNames := map[string]bool{}
+
+	for _, provider := range providers {
+		desiredLabels = append(desiredLabels, providerComponentName(provider.Type, provider.Name))
+		desiredNames[provider.Name] = true
+	}
+
+	isDropped := func(label string) bool { return label != "" && !slices.Contains(desiredLabels, label) }
+
+	inventories := &corev1.ConfigMapList{}
+	if err := r.List(ctx, inventories, client.InNamespace(r.ManagedNamespace)); err != nil {
+		return fmt.Errorf("error listing CAPI provider inventories: %w", err)
+	}
+
+	for _, cm := range inventories.Items {
+		providerName, isInventory := strings.CutPrefix(cm.Name, inventoryConfigMapPrefix)
+		if !isInventory || desiredNames[providerName] {
+			continue
+		}
+
+		if err := r.pruneDroppedInventory(ctx, log, providerName, isDropped); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pruneDroppedInventory deletes the components recorded in the inventory of a dropped provider,
+// and then the inventory itself, unless some of the components could not be pruned yet.
+func (r *CapiInstallerController) pruneDroppedInventory(ctx context.Context, log logr.Logger, providerName string, isDropped func(string) bool) error {
+	previous, err := r.getInventory(ctx, providerName)
+	if err != nil {
+		return err
+	}
+
+	kept := []inventoryEntry{}
+
+	for _, e := range previous {
+		isKept, err := r.pruneComponent(ctx, log, e, isDropped)
+		if err != nil {
+			return err
+		}
+
+		if isKept {
+			kept = append(kept, e)
+		}
+	}
+
+	if len(kept) > 0 {
+		return r.applyInventory(ctx, providerName, kept)
+	}
+
+	log.Info("deleting inventory of dropped CAPI provider", "name", providerName)
+
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: inventoryConfigMapPrefix + providerName, Namespace: r.ManagedNamespace}}
+	if err := r.Delete(ctx, cm); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("error deleting CAPI provider %q inventory: %w", providerName, err)
+	}
+
+	return nil
+}
+
+// providerComponentKind is a kind of provider components watched by the controller.
+type providerComponentKind struct {
+	obj       client.Object
+	list      client.ObjectList
+	namespace string
+}
+
+// providerComponentKinds returns the kinds of provider components watched by the controller, and the namespace they are watched in.
+func (r *CapiInstallerController) providerComponentKinds() []providerComponentKind {
+	return []providerComponentKind{
+		{&appsv1.Deployment{}, &appsv1.DeploymentList{}, r.ManagedNamespace},
+		{&admissionregistrationv1.ValidatingWebhookConfiguration{}, &admissionregistrationv1.ValidatingWebhookConfigurationList{}, notNamespaced},
+		{&admissionregistrationv1.MutatingWebhookConfiguration{}, &admissionregistrationv1.MutatingWebhookConfigurationList{}, notNamespaced},
+		{&admissionregistrationv1beta1.ValidatingAdmissionPolicy{}, &admissionregistrationv1beta1.ValidatingAdmissionPolicyList{}, notNamespaced},
+		{&admissionregistrationv1beta1.ValidatingAdmissionPolicyBinding{}, &admissionregistrationv1beta1.ValidatingAdmissionPolicyBindingList{}, notNamespaced},
+		{&corev1.Service{}, &corev1.ServiceList{}, r.ManagedNamespace},
+		{&apiextensionsv1.CustomResourceDefinition{}, &apiextensionsv1.CustomResourceDefinitionList{}, notNamespaced},
+		{&corev1.ServiceAccount{}, &corev1.ServiceAccountList{}, r.ManagedNamespace},
+		{&rbacv1.ClusterRoleBinding{}, &rbacv1.ClusterRoleBindingList{}, notNamespaced},
+		{&rbacv1.ClusterRole{}, &rbacv1.ClusterRoleList{}, notNamespaced},
+		{&rbacv1.Role{}, &rbacv1.RoleList{}, r.ManagedNamespace},
+		{&rbacv1.RoleBinding{}, &rbacv1.RoleBindingList{}, r.ManagedNamespace},
+	}
+}
+
+// listProviderComponents returns the inventory entries of the provider components of the watched kinds matching the given options.
+func (r *CapiInstallerController) listProviderComponents(ctx context.Context, opts ...client.ListOption) ([]inventoryEntry, error) {
+	entries := []inventoryEntry{}
+
+	for _, kind := range r.providerComponentKinds() {
+		gvk, err := apiutil.GVKForObject(kind.obj, r.Scheme)
+		if err != nil {
+			return nil, fmt.Errorf("error getting kind of CAPI provider components: %w", err)
+		}
+
+		list, ok := kind.list.DeepCopyObject().(client.ObjectList)
+		if !ok {
+			return nil, fmt.Errorf("%w: %T", errUnexpectedListType, kind.list)
+		}
+
+		if err := r.List(ctx, list, append([]client.ListOption{client.InNamespace(kind.namespace)}, opts...)...); err != nil {
+			return nil, fmt.Errorf("error listing CAPI provider %s components: %w", gvk.Kind, err)
+		}
+
+		if err := meta.EachListItem(list, func(obj runtime.Object) error {
+			o, ok := obj.(client.Object)
+			if !ok {
+				return fmt.Errorf("%w: %T", errUnexpectedListType, obj)
+			}
+
+			entries = append(entries, inventoryEntry{
+				Group:     gvk.Group,
+				Version:   gvk.Version,
+				Kind:      gvk.Kind,
+				Namespace: o.GetNamespace(),
+				Name:      o.GetName(),
+			})
+
+			return nil
+		}); err != nil {
+			return nil, fmt.Errorf("error reading CAPI provider %s components: %w", gvk.Kind, err)
+		}
+	}
+
+	return entries, nil
+}
+
+// pruneComponent deletes a stale provider component, when its provider label is owned. It returns true when the component
+// was kept, because it is a CRD which still has instances.
+func (r *CapiInstallerController) pruneComponent(ctx context.Context, log logr.Logger, e inventoryEntry, isOwned func(label string) bool) (bool, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(e.groupVersionKind())
+
+	if err := r.Get(ctx, client.ObjectKey{Namespace: e.Namespace, Name: e.Name}, obj); apierrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("error getting stale CAPI provider component %q: %w", e.key(), err)
+	}
+
+	// The component was adopted by another provider, or by an administrator.
+	if !isOwned(obj.GetLabels()[ownedProviderComponentName]) {
+		log.Info("not pruning stale CAPI provider component without provider label", "component", e.key())
+
+		return false, nil
+	}
+
+	if e.Group == apiextensionsv1.GroupName && e.Kind == "CustomResourceDefinition" {
+		hasInstances, err := r.crdHasInstances(ctx, e.Name)
+		if err != nil {
+			return false, err
+		}
+
+		if hasInstances {
+			log.Info("not pruning stale CAPI provider CRD with instances", "component", e.key())
+
+			return true, nil
+		}
+	}
+
+	log.Info("pruning stale CAPI provider component", "component", e.key())
+
+	if err := r.Delete(ctx, obj, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !apierrors.IsNotFound(err) {
+		return false, fmt.Errorf("error deleting stale CAPI provider component %q: %w", e.key(), err)
+	}
+
+	return false, nil
+}
+
+// crdHasInstances returns true when any instance of the resource defined by the given CRD exists.
+func (r *CapiInstallerController) crdHasInstances(ctx context.Context, name string) (bool, error) {
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := r.Get(ctx, client.ObjectKey{Name: name}, crd); apierrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("error getting CRD %q: %w", name, err)
+	}
+
+	for _, version := range crd.Spec.Versions {
+		if !version.Served {
+			continue
+		}
+
+		instances := &unstructured.UnstructuredList{}
+		instances.SetGroupVersionKind(schema.GroupVersionKind{Group: crd.Spec.Group, Version: version.Name, Kind: crd.Spec.Names.ListKind})
+
+		if err := r.List(ctx, instances, client.Limit(1)); err != nil {
+			return false, fmt.Errorf("error listing instances of CRD %q: %w", name, err)
+		}
+
+		return len(instances.Items) > 0, nil
+	}
+
+	return false, nil
+}
+
+// getInventory returns the components applied for a provider by the previous reconcile.
+func (r *CapiInstallerController) getInventory(ctx context.Context, providerName string) ([]inventoryEntry, error) {
+	cm := &corev1.ConfigMap{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: r.ManagedNamespace, Name: inventoryConfigMapPrefix + providerName}, cm); apierrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error getting CAPI provider %q inventory: %w", providerName, err)
+	}
+
+	inventory := []inventoryEntry{}
+	if err := json.Unmarshal([]byte(cm.Data[inventoryDataKey]), &inventory); err != nil {
+		return nil, fmt.Errorf("error parsing CAPI provider %q inventory: %w", providerName, err)
+	}
+
+	return inventory, nil
+}
+
+// applyInventory records the components applied for a provider, using a server side apply patch.
+func (r *CapiInstallerController) applyInventory(ctx context.Context, providerName string, inventory []inventoryEntry) error {
+	data, err := json.Marshal(inventory)
+	if err != nil {
+		return fmt.Errorf("error marshalling CAPI provider %q inventory: %w", providerName, err)
+	}
+
+	name := inventoryConfigMapPrefix + providerName
+	cmAc := corev1applyconfigs.ConfigMap(name, r.ManagedNamespace).
+		WithData(map[string]string{inventoryDataKey: string(data)})
+
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: r.ManagedNamespace}}
+
+	if err := r.Patch(ctx, cm, util.ApplyConfigPatch(cmAc), client.ForceOwnership, client.FieldOwner(inventoryFieldOwner)); err != nil {
+		return fmt.Errorf("error applying CAPI provider %q inventory: %w", providerName, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package capiinstaller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/openshift/cluster-capi-operator/pkg/operatorstatus"
+	"github.com/openshift/cluster-capi-operator/pkg/util"
+)
+
+var testServiceAccountManifest = `apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: capa-controller-manager
+  namespace: openshift-cluster-api
+`
+
+var testCRDManifest = `apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: awsmachines.infrastructure.cluster.x-k8s.io
+`
+
+var _ = Describe("Pruning provider components", func() {
+	It("should build a sorted inventory of the components", func() {
+		inventory, err := newInventory(scheme.Scheme, []string{testManifest, testServiceAccountManifest})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(inventory).To(Equal([]inventoryEntry{
+			{Group: "", Version: "v1", Kind: "ServiceAccount", Namespace: "openshift-cluster-api", Name: "capa-controller-manager"},
+			{Group: "apps", Version: "v1", Kind: "Deployment", Name: "nginx-deployment"},
+		}))
+	})
+
+	It("should only report the components which are no longer desired as stale", func() {
+		previous := []inventoryEntry{
+			{Group: "apps", Version: "v1", Kind: "Deployment", Namespace: "openshift-cluster-api", Name: "removed"},
+			{Group: "apiextensions.k8s.io", Version: "v1beta1", Kind: "CustomResourceDefinition", Name: "awsmachines.infrastructure.cluster.x-k8s.io"},
+		}
+
+		desired, err := newInventory(scheme.Scheme, []string{testCRDManifest})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(staleInventory(previous, desired)).To(Equal([]inventoryEntry{previous[0]}))
+	})
+
+	It("should not report any stale component without a previous inventory", func() {
+		Expect(staleInventory(nil, []inventoryEntry{{Kind: "ServiceAccount", Name: "foo"}})).To(BeEmpty())
+	})
+
+	It("should report a stale component found more than once only once", func() {
+		stale := inventoryEntry{Group: "apps", Version: "v1", Kind: "Deployment", Namespace: "openshift-cluster-api", Name: "removed"}
+
+		Expect(staleInventory([]inventoryEntry{stale, stale}, nil)).To(Equal([]inventoryEntry{stale}))
+	})
+
+	Context("with the components on the cluster", func() {
+		const namespace = "openshift-cluster-api"
+
+		var (
+			ctx context.Context
+			cl  client.Client
+			r   *CapiInstallerController
+		)
+
+		serviceAccount := func(name, provider string) *corev1.ServiceAccount {
+			sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+			if provider != "" {
+				sa.Labels = map[string]string{ownedProviderComponentName: provider}
+			}
+
+			return sa
+		}
+
+		BeforeEach(func() {
+			ctx = logf.IntoContext(context.Background(), GinkgoLogr)
+
+			cl = fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+				serviceAccount("capa-controller-manager", "infrastructure-aws"),
+				serviceAccount("ipam-controller-manager", "ipam-in-cluster"),
+				serviceAccount("kubevirt-controller-manager", "infrastructure-kubevirt"),
+				serviceAccount("unlabelled", ""),
+				&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: inventoryConfigMapPrefix + "in-cluster", Namespace: namespace}, Data: map[string]string{
+					inventoryDataKey: `[{"group":"","version":"v1","kind":"ServiceAccount","namespace":"openshift-cluster-api","name":"ipam-controller-manager"},` +
+						`{"group":"","version":"v1","kind":"ServiceAccount","namespace":"openshift-cluster-api","name":"ipam-manager-old"},` +
+						`{"group":"","version":"v1","kind":"ServiceAccount","namespace":"openshift-cluster-api","name":"unlabelled"}]`,
+				}},
+			).WithObjects(serviceAccount("ipam-manager-old", "ipam-in-cluster")).Build()
+
+			r = &CapiInstallerController{
+				ClusterOperatorStatusClient: operatorstatus.ClusterOperatorStatusClient{Client: cl, ManagedNamespace: namespace},
+				Scheme:                      scheme.Scheme,
+			}
+		})
+
+		It("should list the components of a provider by their provider label", func() {
+			Expect(r.listProviderComponents(ctx, client.MatchingLabels{ownedProviderComponentName: "infrastructure-aws"})).To(ConsistOf(
+				inventoryEntry{Group: "", Version: "v1", Kind: "ServiceAccount", Namespace: namespace, Name: "capa-controller-manager"},
+			))
+		})
+
+		It("should prune the components and inventory of the providers which are no longer desired", func() {
+			Expect(r.pruneDroppedProviders(ctx, GinkgoLogr, []util.Provider{
+				{Type: coreProviderType, Name: defaultCoreProviderComponentName},
+				{Type: infrastructureProviderType, Name: "aws"},
+			})).To(Succeed())
+
+			for _, name := range []string{"ipam-controller-manager", "ipam-manager-old"} {
+				Expect(cl.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &corev1.ServiceAccount{})).To(MatchError(apierrors.IsNotFound, "IsNotFound"))
+			}
+
+			Expect(cl.Get(ctx, client.ObjectKey{Namespace: namespace, Name: inventoryConfigMapPrefix + "in-cluster"}, &corev1.ConfigMap{})).To(MatchError(apierrors.IsNotFound, "IsNotFound"))
+
+			for _, name := range []string{"capa-controller-manager", "kubevirt-controller-manager", "unlabelled"} {
+				Expect(cl.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &corev1.ServiceAccount{})).To(Succeed())
+			}
+		})
+
+		It("should not prune the components of other providers, or components without a provider label, outside of an inventory", func() {
+			Expect(r.pruneDroppedProviders(ctx, GinkgoLogr, []util.Provider{
+				{Type: coreProviderType, Name: defaultCoreProviderComponentName},
+				{Type: infrastructureProviderType, Name: "aws"},
+				{Type: "ipam", Name: "in-cluster"},
+			})).To(Succeed())
+
+			for _, name := range []string{"capa-controller-manager", "ipam-controller-manager", "kubevirt-controller-manager", "unlabelled"} {
+				Expect(cl.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &corev1.ServiceAccount{})).To(Succeed())
+			}
+		})
+	})
+
+	DescribeTable("should return the provider label value of the components",
+		func(providerType, providerName, expected string) {
+			Expect(providerComponentName(providerType, providerName)).To(Equal(expected))
+		},
+		Entry("for the core provider", "core", "cluster-api", "cluster-api"),
+		Entry("for an infrastructure provider", "infrastructure", "aws", "infrastructure-aws"),
+	)
+})
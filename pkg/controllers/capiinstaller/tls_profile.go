@@ -0,0 +1,54 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package capiinstaller
+
+import (
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+
+	"github.com/openshift/cluster-capi-operator/pkg/util"
+)
+
+const (
+	// apiServerObjectName is the name of the cluster APIServer configuration holding the TLS profile.
+	apiServerObjectName = "cluster"
+
+	tlsMinVersionFlag   = "--tls-min-version"
+	tlsCipherSuitesFlag = "--tls-cipher-suites"
+)
+
+// tlsArgs returns the manager arguments applying the TLS profile to the provider webhook and metrics servers.
+func tlsArgs(profile util.TLSProfile) []string {
+	var args []string
+
+	if profile.MinVersion != "" {
+		args = append(args, tlsMinVersionFlag+"="+profile.MinVersion)
+	}
+
+	if len(profile.CipherSuites) > 0 {
+		args = append(args, tlsCipherSuitesFlag+"="+strings.Join(profile.CipherSuites, ","))
+	}
+
+	return args
+}
+
+// injectTLSArgs sets the TLS arguments on the manager container of the Deployment, when its manager accepts them.
+func injectTLSArgs(deployment *appsv1.Deployment, args []string) {
+	if deploymentManagerFlags()[deployment.Name].tls {
+		injectManagerArgs(deployment, args)
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package capiinstaller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/cluster-capi-operator/pkg/util"
+)
+
+var _ = Describe("TLS profile", func() {
+	It("should default to the Intermediate profile", func() {
+		Expect(tlsArgs(util.NewTLSProfile(nil))).To(ConsistOf(
+			"--tls-min-version=VersionTLS12",
+			HavePrefix("--tls-cipher-suites=TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,"),
+		))
+	})
+
+	It("should not list cipher suites for a TLS 1.3 profile", func() {
+		profile := &configv1.TLSSecurityProfile{Type: configv1.TLSProfileModernType}
+
+		Expect(tlsArgs(util.NewTLSProfile(profile))).To(Equal([]string{"--tls-min-version=VersionTLS13"}))
+	})
+
+	It("should translate a custom profile", func() {
+		profile := &configv1.TLSSecurityProfile{
+			Type: configv1.TLSProfileCustomType,
+			Custom: &configv1.CustomTLSProfile{TLSProfileSpec: configv1.TLSProfileSpec{
+				Ciphers:       []string{"ECDHE-RSA-AES128-GCM-SHA256", "ECDHE-RSA-AES256-GCM-SHA384"},
+				MinTLSVersion: configv1.VersionTLS11,
+			}},
+		}
+
+		Expect(tlsArgs(util.NewTLSProfile(profile))).To(Equal([]string{
+			"--tls-min-version=VersionTLS11",
+			"--tls-cipher-suites=TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384",
+		}))
+	})
+
+	It("should only set the arguments of the manager container", func() {
+		d := &appsv1.Deployment{Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "manager", Args: []string{"--leader-elect", "--tls-min-version=VersionTLS10"}},
+				{Name: "kube-rbac-proxy", Args: []string{"--secure-listen-address=0.0.0.0:8443"}},
+			},
+		}}}}
+
+		injectManagerArgs(d, []string{"--tls-min-version=VersionTLS12", "--tls-cipher-suites=TLS_AES_128_GCM_SHA256"})
+
+		Expect(d.Spec.Template.Spec.Containers[0].Args).To(Equal([]string{
+			"--leader-elect", "--tls-min-version=VersionTLS12", "--tls-cipher-suites=TLS_AES_128_GCM_SHA256",
+		}))
+		Expect(d.Spec.Template.Spec.Containers[1].Args).To(Equal([]string{"--secure-listen-address=0.0.0.0:8443"}))
+	})
+	DescribeTable("should only set the arguments on the Deployments whose manager accepts them",
+		func(name string, expected []string) {
+			d := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "manager", Args: []string{"--leader-elect"}}},
+				}}},
+			}
+
+			injectTLSArgs(d, []string{"--tls-min-version=VersionTLS12"})
+
+			Expect(d.Spec.Template.Spec.Containers[0].Args).To(Equal(expected))
+		},
+		Entry("for the CAPZ manager", "capz-controller-manager", []string{"--leader-elect", "--tls-min-version=VersionTLS12"}),
+		Entry("for the Azure Service Operator", "azureserviceoperator-controller-manager", []string{"--leader-elect"}),
+	)
+})
@@ -0,0 +1,198 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package capiinstaller
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/cluster-capi-operator/pkg/util"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+)
+
+const (
+	// controlPlaneNodeRoleLabel selects the control plane nodes the provider Deployments run on.
+	controlPlaneNodeRoleLabel = "node-role.kubernetes.io/master"
+
+	// controlPlaneNodeTaintKey is the taint of the control plane nodes on recent clusters.
+	controlPlaneNodeTaintKey = "node-role.kubernetes.io/control-plane"
+
+	// highlyAvailableReplicas is the number of replicas of the provider Deployments on highly available topologies.
+	highlyAvailableReplicas = 2
+)
+
+// deploymentTopology is the topology the provider Deployments are placed on.
+type deploymentTopology struct {
+	// mode is the topology of the nodes the provider Deployments run on.
+	mode configv1.TopologyMode
+
+	// controlPlane is whether the provider Deployments run on the control plane nodes,
+	// which is not the case when the control plane is hosted outside of the cluster.
+	controlPlane bool
+}
+
+// newDeploymentTopology returns the topology the provider Deployments are placed on.
+func newDeploymentTopology(infra *configv1.Infrastructure) deploymentTopology {
+	if infra == nil {
+		return deploymentTopology{mode: configv1.HighlyAvailableTopologyMode, controlPlane: true}
+	}
+
+	if infra.Status.ControlPlaneTopology == configv1.ExternalTopologyMode {
+		return deploymentTopology{mode: infra.Status.InfrastructureTopology}
+	}
+
+	return deploymentTopology{mode: infra.Status.ControlPlaneTopology, controlPlane: true}
+}
+
+// singleReplica is whether the provider Deployments run a single replica.
+func (t deploymentTopology) singleReplica() bool {
+	return t.mode == configv1.SingleReplicaTopologyMode
+}
+
+// leaderElectionArgs returns the manager arguments tuning the leader election for the topology.
+func (t deploymentTopology) leaderElectionArgs() []string {
+	leaseDuration, renewDeadline, retryPeriod := util.LeaseDuration, util.RenewDeadline, util.RetryPeriod
+
+	if t.singleReplica() {
+		leaseDuration, renewDeadline, retryPeriod = util.SingleReplicaLeaseDuration, util.SingleReplicaRenewDeadline, util.SingleReplicaRetryPeriod
+	}
+
+	return []string{
+		"--leader-elect=true",
+		"--leader-elect-lease-duration=" + leaseDuration.Duration.String(),
+		"--leader-elect-renew-deadline=" + renewDeadline.Duration.String(),
+		"--leader-elect-retry-period=" + retryPeriod.Duration.String(),
+	}
+}
+
+// injectTopology sets the replicas, leader election and placement of the Deployment for the topology.
+// The leader election is only tuned on the managers which accept the leader election flags.
+// On highly available topologies the replicas are spread across nodes, and rolled out one at a time
+// so that the spreading never blocks a rollout.
+func injectTopology(deployment *appsv1.Deployment, topology deploymentTopology) {
+	podSpec := &deployment.Spec.Template.Spec
+
+	if deploymentManagerFlags()[deployment.Name].leaderElection {
+		injectManagerArgs(deployment, topology.leaderElectionArgs())
+	}
+
+	if topology.controlPlane {
+		podSpec.NodeSelector = util.MergeMaps(podSpec.NodeSelector, map[string]string{controlPlaneNodeRoleLabel: ""})
+		podSpec.Tolerations = setToleration(podSpec.Tolerations, controlPlaneNodeRoleLabel)
+		podSpec.Tolerations = setToleration(podSpec.Tolerations, controlPlaneNodeTaintKey)
+	}
+
+	if topology.singleReplica() {
+		deployment.Spec.Replicas = ptr.To[int32](1)
+
+		return
+	}
+
+	deployment.Spec.Replicas = ptr.To[int32](highlyAvailableReplicas)
+
+	if podSpec.Affinity == nil {
+		podSpec.Affinity = &corev1.Affinity{}
+	}
+
+	if podSpec.Affinity.PodAntiAffinity == nil {
+		podSpec.Affinity.PodAntiAffinity = &corev1.PodAntiAffinity{}
+	}
+
+	antiAffinity := podSpec.Affinity.PodAntiAffinity
+	antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution = setPodAffinityTerm(antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+		corev1.PodAffinityTerm{
+			LabelSelector: deployment.Spec.Selector,
+			TopologyKey:   corev1.LabelHostname,
+		})
+
+	maxSurge, maxUnavailable := intstr.FromInt32(0), intstr.FromInt32(1)
+	deployment.Spec.Strategy = appsv1.DeploymentStrategy{
+		Type:          appsv1.RollingUpdateDeploymentStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateDeployment{MaxSurge: &maxSurge, MaxUnavailable: &maxUnavailable},
+	}
+}
+
+// setPodAffinityTerm adds the pod affinity term to the terms of the provider manifests, unless it is already set.
+func setPodAffinityTerm(terms []corev1.PodAffinityTerm, term corev1.PodAffinityTerm) []corev1.PodAffinityTerm {
+	for _, t := range terms {
+		if equality.Semantic.DeepEqual(t, term) {
+			return terms
+		}
+	}
+
+	return append(terms, term)
+}
+
+// setToleration adds a toleration of the NoSchedule taint of the given key, unless it is already tolerated.
+func setToleration(tolerations []corev1.Toleration, key string) []corev1.Toleration {
+	for _, toleration := range tolerations {
+		if toleration.Key == key && toleration.Operator == corev1.TolerationOpExists {
+			return tolerations
+		}
+	}
+
+	return append(tolerations, corev1.Toleration{Key: key, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule})
+}
+
+// podDisruptionBudget returns the PodDisruptionBudget of the Deployment.
+// It is owned by the Deployment, so that it is garbage collected when the Deployment is pruned.
+func podDisruptionBudget(deployment *appsv1.Deployment) *policyv1.PodDisruptionBudget {
+	maxUnavailable := intstr.FromInt32(1)
+
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            deployment.Name,
+			Namespace:       deployment.Namespace,
+			Labels:          deployment.Labels,
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(deployment, appsv1.SchemeGroupVersion.WithKind("Deployment"))},
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MaxUnavailable: &maxUnavailable,
+			Selector:       deployment.Spec.Selector,
+		},
+	}
+}
+
+// reconcilePodDisruptionBudget applies the PodDisruptionBudget of the Deployment on highly available topologies,
+// and deletes it on single replica topologies where it would block the node drains.
+func (r *CapiInstallerController) reconcilePodDisruptionBudget(ctx context.Context, deployment *appsv1.Deployment, topology deploymentTopology) error {
+	pdb := podDisruptionBudget(deployment)
+	recorder := events.NewInMemoryRecorder("cluster-capi-operator-capi-installer-apply-client")
+
+	if topology.singleReplica() {
+		if _, _, err := resourceapply.DeletePodDisruptionBudget(ctx, r.ApplyClient.PolicyV1(), recorder, pdb); err != nil {
+			return fmt.Errorf("error deleting CAPI provider pod disruption budget %q: %w", pdb.Name, err)
+		}
+
+		return nil
+	}
+
+	if _, _, err := resourceapply.ApplyPodDisruptionBudget(ctx, r.ApplyClient.PolicyV1(), recorder, pdb); err != nil {
+		return fmt.Errorf("error applying CAPI provider pod disruption budget %q: %w", pdb.Name, err)
+	}
+
+	return nil
+}
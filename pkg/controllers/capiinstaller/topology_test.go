@@ -0,0 +1,139 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package capiinstaller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+var _ = Describe("Deployment topology", func() {
+	newInfra := func(controlPlane, infrastructure configv1.TopologyMode) *configv1.Infrastructure {
+		return &configv1.Infrastructure{Status: configv1.InfrastructureStatus{
+			ControlPlaneTopology:   controlPlane,
+			InfrastructureTopology: infrastructure,
+		}}
+	}
+
+	newDeployment := func() *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "capi-controller-manager", Namespace: "openshift-cluster-api", UID: "uid"},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: ptr.To[int32](1),
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"cluster.x-k8s.io/provider": "cluster-api"}},
+				Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "manager", Args: []string{"--leader-elect"}}},
+					Tolerations: []corev1.Toleration{
+						{Key: "node-role.kubernetes.io/master", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+					},
+				}},
+			},
+		}
+	}
+
+	DescribeTable("should place the provider Deployments for the topology",
+		func(infra *configv1.Infrastructure, expected deploymentTopology) {
+			Expect(newDeploymentTopology(infra)).To(Equal(expected))
+		},
+		Entry("on highly available control planes", newInfra(configv1.HighlyAvailableTopologyMode, configv1.HighlyAvailableTopologyMode),
+			deploymentTopology{mode: configv1.HighlyAvailableTopologyMode, controlPlane: true}),
+		Entry("on single node clusters", newInfra(configv1.SingleReplicaTopologyMode, configv1.SingleReplicaTopologyMode),
+			deploymentTopology{mode: configv1.SingleReplicaTopologyMode, controlPlane: true}),
+		Entry("on the workers of hosted control planes", newInfra(configv1.ExternalTopologyMode, configv1.SingleReplicaTopologyMode),
+			deploymentTopology{mode: configv1.SingleReplicaTopologyMode}),
+		Entry("without an infrastructure", nil,
+			deploymentTopology{mode: configv1.HighlyAvailableTopologyMode, controlPlane: true}),
+	)
+
+	It("should run two spread replicas on the control plane of highly available topologies", func() {
+		d := newDeployment()
+		injectTopology(d, deploymentTopology{mode: configv1.HighlyAvailableTopologyMode, controlPlane: true})
+
+		podSpec := d.Spec.Template.Spec
+		Expect(d.Spec.Replicas).To(HaveValue(BeEquivalentTo(2)))
+		Expect(d.Spec.Strategy.RollingUpdate.MaxSurge).To(HaveValue(Equal(intstr.FromInt32(0))))
+		Expect(podSpec.NodeSelector).To(Equal(map[string]string{"node-role.kubernetes.io/master": ""}))
+		Expect(podSpec.Tolerations).To(ConsistOf(
+			HaveField("Key", "node-role.kubernetes.io/master"),
+			HaveField("Key", "node-role.kubernetes.io/control-plane"),
+		))
+		Expect(podSpec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution).To(ConsistOf(corev1.PodAffinityTerm{
+			LabelSelector: d.Spec.Selector,
+			TopologyKey:   corev1.LabelHostname,
+		}))
+		Expect(podSpec.Containers[0].Args).To(ContainElements("--leader-elect=true", "--leader-elect-lease-duration=2m17s"))
+	})
+
+	It("should run a single replica without anti-affinity on single replica topologies", func() {
+		d := newDeployment()
+		injectTopology(d, deploymentTopology{mode: configv1.SingleReplicaTopologyMode})
+
+		podSpec := d.Spec.Template.Spec
+		Expect(d.Spec.Replicas).To(HaveValue(BeEquivalentTo(1)))
+		Expect(podSpec.Affinity).To(BeNil())
+		Expect(podSpec.NodeSelector).To(BeEmpty())
+		Expect(podSpec.Containers[0].Args).To(ContainElements("--leader-elect-lease-duration=4m30s", "--leader-elect-retry-period=1m0s"))
+	})
+
+	It("should keep the anti-affinity of the provider manifests", func() {
+		preferred := corev1.WeightedPodAffinityTerm{Weight: 100, PodAffinityTerm: corev1.PodAffinityTerm{TopologyKey: corev1.LabelTopologyZone}}
+		required := corev1.PodAffinityTerm{TopologyKey: corev1.LabelTopologyZone}
+
+		d := newDeployment()
+		d.Spec.Template.Spec.Affinity = &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution:  []corev1.PodAffinityTerm{required},
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{preferred},
+		}}
+
+		topology := deploymentTopology{mode: configv1.HighlyAvailableTopologyMode, controlPlane: true}
+		injectTopology(d, topology)
+		injectTopology(d, topology)
+
+		antiAffinity := d.Spec.Template.Spec.Affinity.PodAntiAffinity
+		Expect(antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution).To(ConsistOf(preferred))
+		Expect(antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution).To(ConsistOf(required, corev1.PodAffinityTerm{
+			LabelSelector: d.Spec.Selector,
+			TopologyKey:   corev1.LabelHostname,
+		}))
+	})
+
+	It("should not tune the leader election of managers which do not accept the leader election flags", func() {
+		d := newDeployment()
+		d.Name = "azureserviceoperator-controller-manager"
+
+		injectTopology(d, deploymentTopology{mode: configv1.HighlyAvailableTopologyMode, controlPlane: true})
+
+		Expect(d.Spec.Template.Spec.Containers[0].Args).To(Equal([]string{"--leader-elect"}))
+	})
+
+	It("should build a PodDisruptionBudget owned by the Deployment", func() {
+		d := newDeployment()
+		pdb := podDisruptionBudget(d)
+
+		Expect(pdb.Name).To(Equal(d.Name))
+		Expect(pdb.Namespace).To(Equal(d.Namespace))
+		Expect(pdb.Spec.Selector).To(Equal(d.Spec.Selector))
+		Expect(pdb.Spec.MaxUnavailable).To(HaveValue(Equal(intstr.FromInt32(1))))
+		Expect(pdb.OwnerReferences).To(ConsistOf(HaveField("UID", d.UID)))
+	})
+})
@@ -0,0 +1,75 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package capiinstaller
+
+import (
+	"fmt"
+
+	"github.com/openshift/cluster-capi-operator/pkg/conversion"
+	"github.com/openshift/cluster-capi-operator/pkg/util"
+
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// unsupportedFieldsComponents returns the manifests of the ValidatingAdmissionPolicies denying the InfraMachine fields
+// which can not be converted to Machine API. They are applied, and pruned, as components of the infrastructure provider.
+// Nothing is returned for the other providers, or on platforms without a registered conversion.
+func (r *CapiInstallerController) unsupportedFieldsComponents(provider util.Provider) ([]string, error) {
+	infraProvider := platformToInfraProviderComponentName(r.Platform)
+	if providerComponentName(provider.Type, provider.Name) != infraProvider || !conversion.IsPlatformSupported(r.Platform) {
+		return nil, nil
+	}
+
+	platform, err := conversion.PlatformFor(r.Platform)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversion for platform: %w", err)
+	}
+
+	policies, bindings, err := platform.UnsupportedFields.ValidatingAdmissionPolicies(r.ManagedNamespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate unsupported fields policies: %w", err)
+	}
+
+	objs := []client.Object{}
+
+	for _, policy := range policies {
+		policy.SetGroupVersionKind(admissionregistrationv1beta1.SchemeGroupVersion.WithKind("ValidatingAdmissionPolicy"))
+		objs = append(objs, policy)
+	}
+
+	for _, binding := range bindings {
+		binding.SetGroupVersionKind(admissionregistrationv1beta1.SchemeGroupVersion.WithKind("ValidatingAdmissionPolicyBinding"))
+		objs = append(objs, binding)
+	}
+
+	components := make([]string, 0, len(objs))
+
+	for _, obj := range objs {
+		// Label the policies as components of the infrastructure provider, so that changes to them are reconciled.
+		obj.SetLabels(map[string]string{ownedProviderComponentName: infraProvider})
+
+		manifest, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, fmt.Errorf("error marshalling unsupported fields policy %q: %w", obj.GetName(), err)
+		}
+
+		components = append(components, string(manifest))
+	}
+
+	return components, nil
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package capiinstaller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/cluster-capi-operator/pkg/operatorstatus"
+	"github.com/openshift/cluster-capi-operator/pkg/util"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+var _ = Describe("Unsupported fields components", func() {
+	It("should render the policies as components of the infrastructure provider", func() {
+		r := &CapiInstallerController{
+			ClusterOperatorStatusClient: operatorstatus.ClusterOperatorStatusClient{ManagedNamespace: "openshift-cluster-api"},
+			Platform:                    configv1.AWSPlatformType,
+		}
+
+		components, err := r.unsupportedFieldsComponents(util.Provider{Type: infrastructureProviderType, Name: "aws"})
+		Expect(err).NotTo(HaveOccurred())
+
+		inventory, err := newInventory(scheme.Scheme, components)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(inventory).To(ConsistOf(
+			inventoryEntry{Group: "admissionregistration.k8s.io", Version: "v1beta1", Kind: "ValidatingAdmissionPolicy", Name: "openshift-cluster-api-unsupported-awsmachines"},
+			inventoryEntry{Group: "admissionregistration.k8s.io", Version: "v1beta1", Kind: "ValidatingAdmissionPolicy", Name: "openshift-cluster-api-unsupported-awsmachinetemplates"},
+			inventoryEntry{Group: "admissionregistration.k8s.io", Version: "v1beta1", Kind: "ValidatingAdmissionPolicyBinding", Name: "openshift-cluster-api-unsupported-awsmachines"},
+			inventoryEntry{Group: "admissionregistration.k8s.io", Version: "v1beta1", Kind: "ValidatingAdmissionPolicyBinding", Name: "openshift-cluster-api-unsupported-awsmachinetemplates"},
+		))
+
+		for _, component := range components {
+			u, err := yamlToUnstructured(scheme.Scheme, component)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(u.GetLabels()).To(HaveKeyWithValue(ownedProviderComponentName, "infrastructure-aws"))
+		}
+	})
+
+	It("should not render any component for the other providers", func() {
+		r := &CapiInstallerController{
+			ClusterOperatorStatusClient: operatorstatus.ClusterOperatorStatusClient{ManagedNamespace: "openshift-cluster-api"},
+			Platform:                    configv1.AWSPlatformType,
+		}
+
+		Expect(r.unsupportedFieldsComponents(util.Provider{Type: coreProviderType, Name: defaultCoreProviderComponentName})).To(BeEmpty())
+	})
+
+	It("should not render any component on a platform without a conversion", func() {
+		r := &CapiInstallerController{
+			ClusterOperatorStatusClient: operatorstatus.ClusterOperatorStatusClient{ManagedNamespace: "openshift-cluster-api"},
+			Platform:                    configv1.GCPPlatformType,
+		}
+
+		Expect(r.unsupportedFieldsComponents(util.Provider{Type: infrastructureProviderType, Name: "gcp"})).To(BeEmpty())
+	})
+})
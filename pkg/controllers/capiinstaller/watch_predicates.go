@@ -40,6 +40,20 @@ func clusterOperatorPredicates() predicate.Funcs {
 	}
 }
 
+// clusterConfigPredicate defines a predicate function for a cluster-wide configuration object, e.g. the FeatureGate.
+func clusterConfigPredicate(name string) predicate.Funcs {
+	isClusterConfig := func(obj client.Object) bool {
+		return obj.GetName() == name
+	}
+
+	return predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return isClusterConfig(e.Object) },
+		UpdateFunc:  func(e event.UpdateEvent) bool { return isClusterConfig(e.ObjectNew) },
+		GenericFunc: func(e event.GenericEvent) bool { return isClusterConfig(e.Object) },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return isClusterConfig(e.Object) },
+	}
+}
+
 // toClusterOperator maps a reconcile request to the cluster-api ClusterOperator.
 func toClusterOperator(ctx context.Context, cO client.Object) []reconcile.Request {
 	return []reconcile.Request{{
@@ -47,47 +61,63 @@ func toClusterOperator(ctx context.Context, cO client.Object) []reconcile.Reques
 	}}
 }
 
-// configMapPredicate defines a predicate function for owned ConfigMaps.
-func configMapPredicate(namespace string, platform configv1.PlatformType) predicate.Funcs {
+// configMapPredicate defines a predicate function for the transport ConfigMaps of the providers,
+// the trusted CA bundle, images and providers ConfigMaps, and owned ConfigMaps.
+// The ConfigMaps of any provider are watched, as the desired providers are only known when reconciling.
+func configMapPredicate(namespace string) predicate.Funcs {
+	isProviderConfigMap := func(obj runtime.Object) bool {
+		return isTransportConfigMap(obj, namespace) || isTrustedCABundleConfigMap(obj, namespace) ||
+			isImagesConfigMap(obj, namespace) || isProvidersConfigMap(obj, namespace) || isOwnedProviderComponent(obj, namespace)
+	}
+
 	return predicate.Funcs{
-		CreateFunc:  func(e event.CreateEvent) bool { return isOwnedProviderComponent(e.Object, namespace, platform) },
-		UpdateFunc:  func(e event.UpdateEvent) bool { return isOwnedProviderComponent(e.ObjectNew, namespace, platform) },
-		DeleteFunc:  func(e event.DeleteEvent) bool { return isOwnedProviderComponent(e.Object, namespace, platform) },
-		GenericFunc: func(e event.GenericEvent) bool { return isOwnedProviderComponent(e.Object, namespace, platform) },
+		CreateFunc:  func(e event.CreateEvent) bool { return isProviderConfigMap(e.Object) },
+		UpdateFunc:  func(e event.UpdateEvent) bool { return isProviderConfigMap(e.ObjectNew) },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return isProviderConfigMap(e.Object) },
+		GenericFunc: func(e event.GenericEvent) bool { return isProviderConfigMap(e.Object) },
 	}
 }
 
-// ownedPlatformLabelPredicate defines a predicate function for owned objects.
-func ownedPlatformLabelPredicate(namespace string, platform configv1.PlatformType) predicate.Funcs {
+// ownedProviderLabelPredicate defines a predicate function for owned objects.
+func ownedProviderLabelPredicate(namespace string) predicate.Funcs {
 	return predicate.Funcs{
-		UpdateFunc: func(e event.UpdateEvent) bool { return isOwnedProviderComponent(e.ObjectNew, namespace, platform) },
-		DeleteFunc: func(e event.DeleteEvent) bool { return isOwnedProviderComponent(e.Object, namespace, platform) },
+		UpdateFunc: func(e event.UpdateEvent) bool { return isOwnedProviderComponent(e.ObjectNew, namespace) },
+		DeleteFunc: func(e event.DeleteEvent) bool { return isOwnedProviderComponent(e.Object, namespace) },
 	}
 }
 
-// isOwnedProviderComponent checks whether an object is an owned provider component.
-func isOwnedProviderComponent(obj runtime.Object, namespace string, platform configv1.PlatformType) bool {
+// isTransportConfigMap checks whether an object is a transport ConfigMap of a provider.
+func isTransportConfigMap(obj runtime.Object, namespace string) bool {
 	cO, ok := obj.(client.Object)
-	if !ok {
+	if !ok || cO.GetNamespace() != namespace {
 		return false
 	}
 
-	if cO.GetNamespace() != namespace {
+	_, hasName := cO.GetLabels()[providerConfigMapLabelNameKey]
+	_, hasType := cO.GetLabels()[providerConfigMapLabelTypeKey]
+
+	return hasName && hasType
+}
+
+// isTrustedCABundleConfigMap checks whether an object is the ConfigMap the trusted CA bundle is injected into.
+func isTrustedCABundleConfigMap(obj runtime.Object, namespace string) bool {
+	cO, ok := obj.(client.Object)
+
+	return ok && cO.GetNamespace() == namespace && cO.GetName() == trustedCABundleConfigMapName
+}
+
+// isOwnedProviderComponent checks whether an object is an owned provider component.
+func isOwnedProviderComponent(obj runtime.Object, namespace string) bool {
+	cO, ok := obj.(client.Object)
+	if !ok {
 		return false
 	}
 
-	providerName, hasLabel := cO.GetLabels()[ownedProviderComponentName]
-	if !hasLabel {
+	if cO.GetNamespace() != namespace {
 		return false
 	}
 
-	switch {
-	case providerName == defaultCoreProviderComponentName:
-		// this is the core CAPI provider.
-		return true
-	case providerName == platformToInfraProviderComponentName(platform):
-		return true
-	}
+	_, hasLabel := cO.GetLabels()[ownedProviderComponentName]
 
-	return false
+	return hasLabel
 }
@@ -43,4 +43,41 @@ const (
 	// ReasonResourceSynchronized denotes that the resource is synchronized
 	// successfully.
 	ReasonResourceSynchronized = "ResourceSynchronized"
+
+	// LosslessConversionCondition is used to denote whether a MAPI resource
+	// could be converted without any loss. This condition is false when the
+	// last conversion reported any fields, the message then holds the JSON
+	// encoded list of the reported fields, truncated to a bounded length.
+	// The full list is found in the migration readiness ConfigMap.
+	LosslessConversionCondition machinev1beta1.ConditionType = "LosslessConversion"
+
+	// ReasonConversionLossless denotes that the conversion did not report any fields.
+	ReasonConversionLossless = "ConversionLossless"
+
+	// ReasonConversionLossy denotes that the conversion dropped, altered or
+	// defaulted the value of at least one field.
+	ReasonConversionLossy = "ConversionLossy"
+
+	// ReasonConversionFailed denotes that at least one field could not be
+	// converted.
+	ReasonConversionFailed = "ConversionFailed"
+
+	// MigrationReadinessConfigMapName is the name of the ConfigMap holding
+	// the migration readiness of every MAPI resource, with the fields
+	// reported by their conversion.
+	MigrationReadinessConfigMapName = "machine-api-migration-readiness"
+
+	// PausedCondition is used to denote whether a MAPI or CAPI resource is
+	// paused. The synchronization controllers pause the non-authoritative
+	// resource so that only the controllers of the authoritative API act on
+	// the underlying infrastructure.
+	PausedCondition machinev1beta1.ConditionType = "Paused"
+
+	// ReasonAuthoritativeAPIMachineAPI denotes that the MAPI resource is
+	// authoritative, so the CAPI resources are paused.
+	ReasonAuthoritativeAPIMachineAPI = "AuthoritativeAPIMachineAPI"
+
+	// ReasonAuthoritativeAPIClusterAPI denotes that the CAPI resource is
+	// authoritative, so the MAPI resources are paused.
+	ReasonAuthoritativeAPIClusterAPI = "AuthoritativeAPIClusterAPI"
 )
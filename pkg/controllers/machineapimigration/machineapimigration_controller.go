@@ -0,0 +1,160 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package machineapimigration
+
+import (
+	"context"
+	"fmt"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	migrationv1alpha1 "github.com/openshift/cluster-capi-operator/pkg/apis/migration/v1alpha1"
+	consts "github.com/openshift/cluster-capi-operator/pkg/controllers"
+	"github.com/openshift/cluster-capi-operator/pkg/util"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// fieldOwner is the field manager used to flip the authoritative API of the MAPI MachineSets.
+const fieldOwner = "machine-api-migration-controller"
+
+// MachineAPIMigrationReconciler migrates the MAPI MachineSets selected by a MachineAPIMigration
+// to its target authority, in batches.
+type MachineAPIMigrationReconciler struct {
+	client.Client
+
+	MAPINamespace string
+}
+
+// SetupWithManager sets the MachineAPIMigrationReconciler controller up with the given manager.
+func (r *MachineAPIMigrationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// Allow the namespace to be set externally for test purposes, when not set,
+	// default to the production namespace.
+	if r.MAPINamespace == "" {
+		r.MAPINamespace = consts.DefaultMAPIManagedNamespace
+	}
+
+	if err := ctrl.NewControllerManagedBy(mgr).
+		For(&migrationv1alpha1.MachineAPIMigration{}, builder.WithPredicates(util.FilterNamespace(r.MAPINamespace))).
+		Watches(
+			&machinev1beta1.MachineSet{},
+			handler.EnqueueRequestsFromMapFunc(r.toMachineAPIMigrations),
+			builder.WithPredicates(util.FilterNamespace(r.MAPINamespace)),
+		).
+		Complete(r); err != nil {
+		return fmt.Errorf("failed to create controller: %w", err)
+	}
+
+	r.Client = mgr.GetClient()
+
+	return nil
+}
+
+// Reconcile observes the MachineSets selected by a MachineAPIMigration, flips the next ones
+// according to the batches of the migration, and reports the progress in its status.
+func (r *MachineAPIMigrationReconciler) Reconcile(ctx context.Context, req reconcile.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx, "namespace", req.Namespace, "name", req.Name)
+
+	logger.V(1).Info("Reconciling machine API migration")
+	defer logger.V(1).Info("Finished reconciling machine API migration")
+
+	migration := &migrationv1alpha1.MachineAPIMigration{}
+	if err := r.Get(ctx, req.NamespacedName, migration); apierrors.IsNotFound(err) {
+		return ctrl.Result{}, nil
+	} else if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get machine API migration: %w", err)
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&migration.Spec.Selector)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to parse machine API migration selector: %w", err)
+	}
+
+	machineSets := &machinev1beta1.MachineSetList{}
+	if err := r.List(ctx, machineSets, client.InNamespace(migration.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list MAPI machine sets: %w", err)
+	}
+
+	now := metav1.Now()
+	p := newPlan(migration, machineSets.Items, now)
+
+	if err := r.flipMachineSets(ctx, p, machineSets.Items, migration.Spec.TargetAuthority, now); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	patchBase := client.MergeFrom(migration.DeepCopy())
+	migration.Status = p.status
+
+	if err := r.Status().Patch(ctx, migration, patchBase); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to patch machine API migration status: %w", err)
+	}
+
+	logger.Info("Updated machine API migration", "phase", p.status.Phase, "batch", p.status.CurrentBatch, "flipped", p.flip)
+
+	return ctrl.Result{RequeueAfter: p.requeueAfter}, nil
+}
+
+// flipMachineSets sets spec.authoritativeAPI to the target authority on the MachineSets planned to be flipped.
+// A MachineSet whose flip is denied, e.g. by the authoritative API webhook, fails and pauses the migration.
+func (r *MachineAPIMigrationReconciler) flipMachineSets(ctx context.Context, p *plan, machineSets []machinev1beta1.MachineSet, target machinev1beta1.MachineAuthority, now metav1.Time) error {
+	machineSetsByName := make(map[string]*machinev1beta1.MachineSet, len(machineSets))
+	for i := range machineSets {
+		machineSetsByName[machineSets[i].Name] = &machineSets[i]
+	}
+
+	for _, name := range p.flip {
+		mapiMachineSet := machineSetsByName[name]
+
+		patchBase := client.MergeFrom(mapiMachineSet.DeepCopy())
+		mapiMachineSet.Spec.AuthoritativeAPI = target
+
+		err := r.Patch(ctx, mapiMachineSet, patchBase, client.FieldOwner(fieldOwner))
+
+		switch {
+		case apierrors.IsInvalid(err), apierrors.IsForbidden(err):
+			p.fail(name, err.Error(), now)
+
+			return nil
+		case err != nil:
+			return fmt.Errorf("failed to set authoritative API of MAPI machine set %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// toMachineAPIMigrations enqueues every MachineAPIMigration in the namespace of the given MachineSet.
+func (r *MachineAPIMigrationReconciler) toMachineAPIMigrations(ctx context.Context, obj client.Object) []reconcile.Request {
+	migrations := &migrationv1alpha1.MachineAPIMigrationList{}
+	if err := r.List(ctx, migrations, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list machine API migrations")
+
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(migrations.Items))
+	for _, migration := range migrations.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&migration)})
+	}
+
+	return requests
+}
@@ -0,0 +1,308 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package machineapimigration
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	migrationv1alpha1 "github.com/openshift/cluster-capi-operator/pkg/apis/migration/v1alpha1"
+	consts "github.com/openshift/cluster-capi-operator/pkg/controllers"
+	"github.com/openshift/cluster-capi-operator/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// defaultSettleDuration is how long a migrated batch settles when the spec does not set it.
+const defaultSettleDuration = time.Minute
+
+// plan is the outcome of a reconciliation of a MachineAPIMigration.
+type plan struct {
+	// status is the new status of the MachineAPIMigration.
+	status migrationv1alpha1.MachineAPIMigrationStatus
+
+	// flip lists the MachineSets whose spec.authoritativeAPI must be set to the target authority.
+	flip []string
+
+	// requeueAfter is how long to wait for the current batch to settle.
+	requeueAfter time.Duration
+}
+
+// newPlan observes the selected MachineSets, advances the batches of the migration,
+// and returns the MachineSets to flip along with the new status of the migration.
+func newPlan(migration *migrationv1alpha1.MachineAPIMigration, machineSets []machinev1beta1.MachineSet, now metav1.Time) *plan {
+	p := &plan{status: *migration.Status.DeepCopy()}
+	target := migration.Spec.TargetAuthority
+
+	if p.status.TargetAuthority != target {
+		// A new target, e.g. a rollback, plans the batches again.
+		p.status.TargetAuthority = target
+		p.status.CurrentBatch = 0
+		p.status.BatchCompletedTime = nil
+
+		for i := range p.status.Objects {
+			p.status.Objects[i].Batch = 0
+		}
+	}
+
+	// MachineSets whose flip was denied are retried once the spec of the migration changes.
+	retryFailed := migration.Generation != migration.Status.ObservedGeneration
+	p.status.ObservedGeneration = migration.Generation
+	p.status.Objects = observeObjects(p.status.Objects, machineSets, target, retryFailed, now)
+
+	p.progress(migration.Spec, now)
+
+	return p
+}
+
+// observeObjects returns the migration progress of every MachineSet, sorted by name.
+func observeObjects(previous []migrationv1alpha1.MachineAPIMigrationObjectStatus, machineSets []machinev1beta1.MachineSet,
+	target machinev1beta1.MachineAuthority, retryFailed bool, now metav1.Time) []migrationv1alpha1.MachineAPIMigrationObjectStatus {
+	previousByName := make(map[string]migrationv1alpha1.MachineAPIMigrationObjectStatus, len(previous))
+	for _, obj := range previous {
+		previousByName[obj.Name] = obj
+	}
+
+	objects := make([]migrationv1alpha1.MachineAPIMigrationObjectStatus, 0, len(machineSets))
+
+	for i := range machineSets {
+		obj, ok := previousByName[machineSets[i].Name]
+		if !ok {
+			obj = migrationv1alpha1.MachineAPIMigrationObjectStatus{Name: machineSets[i].Name}
+		}
+
+		phase, message := observeMachineSet(&machineSets[i], target)
+		if phase == migrationv1alpha1.ObjectPhasePending && obj.Phase == migrationv1alpha1.ObjectPhaseFailed && !retryFailed {
+			phase, message = obj.Phase, obj.Message
+		}
+
+		objects = append(objects, setObjectPhase(obj, phase, message, now))
+	}
+
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Name < objects[j].Name })
+
+	return objects
+}
+
+// observeMachineSet returns the migration phase of a MachineSet towards the target authority, and a message explaining it.
+func observeMachineSet(mapiMachineSet *machinev1beta1.MachineSet, target machinev1beta1.MachineAuthority) (migrationv1alpha1.ObjectPhase, string) {
+	if mapiMachineSet.Spec.AuthoritativeAPI != target {
+		return migrationv1alpha1.ObjectPhasePending, ""
+	}
+
+	if mapiMachineSet.Status.AuthoritativeAPI != target {
+		return migrationv1alpha1.ObjectPhaseMigrating, fmt.Sprintf("Waiting for status.authoritativeAPI to be %s", target)
+	}
+
+	synchronized := findCondition(mapiMachineSet.Status.Conditions, consts.SynchronizedCondition)
+	paused := findCondition(mapiMachineSet.Status.Conditions, consts.PausedCondition)
+	pausedReason, _ := util.PausedConditionReason(target)
+
+	switch {
+	case synchronized != nil && synchronized.Status == corev1.ConditionFalse:
+		return migrationv1alpha1.ObjectPhaseFailed, fmt.Sprintf("The MachineSet failed to synchronize: %s", synchronized.Message)
+	case synchronized == nil || synchronized.Status != corev1.ConditionTrue:
+		return migrationv1alpha1.ObjectPhaseMigrating, "Waiting for the MachineSet to be synchronized"
+	case paused == nil || paused.Reason != pausedReason:
+		return migrationv1alpha1.ObjectPhaseMigrating, "Waiting for the non-authoritative resources to be paused"
+	}
+
+	return migrationv1alpha1.ObjectPhaseMigrated, ""
+}
+
+// progress sets the phase of the migration, and advances its batches while it is progressing.
+func (p *plan) progress(spec migrationv1alpha1.MachineAPIMigrationSpec, now metav1.Time) {
+	migrated := 0
+
+	for _, obj := range p.status.Objects {
+		switch obj.Phase { //nolint:exhaustive
+		case migrationv1alpha1.ObjectPhaseFailed:
+			p.pause(fmt.Sprintf("MachineSet %s failed to migrate: %s", obj.Name, obj.Message))
+
+			return
+		case migrationv1alpha1.ObjectPhaseMigrated:
+			migrated++
+		}
+	}
+
+	switch {
+	case len(p.status.Objects) == 0:
+		p.status.Phase = migrationv1alpha1.MigrationPhasePending
+		p.status.Message = "No MachineSet matches the selector"
+	case migrated == len(p.status.Objects):
+		p.status.Phase = migrationv1alpha1.MigrationPhaseCompleted
+		p.status.Message = fmt.Sprintf("All MachineSets are migrated to %s", spec.TargetAuthority)
+	case spec.Paused:
+		p.pause("The migration is paused")
+	default:
+		p.status.Phase = migrationv1alpha1.MigrationPhaseProgressing
+		p.advance(spec, now)
+	}
+}
+
+// advance starts the next batch once the current batch is migrated and settled,
+// and flips the MachineSets of the current batch up to the maximum concurrency.
+func (p *plan) advance(spec migrationv1alpha1.MachineAPIMigrationSpec, now metav1.Time) {
+	if p.batchMigrated() {
+		if !p.batchSettled(spec.SettleDuration, now) {
+			p.status.Message = fmt.Sprintf("Batch %d is migrated, waiting for it to settle", p.status.CurrentBatch)
+
+			return
+		}
+
+		p.startBatch(spec.BatchSize)
+	}
+
+	p.flipBatch(spec.MaxConcurrency, now)
+	p.status.Message = fmt.Sprintf("Migrating batch %d", p.status.CurrentBatch)
+}
+
+// batchSettled records when the current batch was migrated, and returns true once it settled
+// for the settle duration. Otherwise, the plan is requeued for the remaining time.
+func (p *plan) batchSettled(settleDuration *metav1.Duration, now metav1.Time) bool {
+	if p.status.CurrentBatch == 0 {
+		return true
+	}
+
+	if p.status.BatchCompletedTime == nil {
+		p.status.BatchCompletedTime = &now
+	}
+
+	duration := defaultSettleDuration
+	if settleDuration != nil {
+		duration = settleDuration.Duration
+	}
+
+	if remaining := p.status.BatchCompletedTime.Add(duration).Sub(now.Time); remaining > 0 {
+		p.requeueAfter = remaining
+
+		return false
+	}
+
+	return true
+}
+
+// batchMigrated returns true when every MachineSet of the current batch is migrated,
+// or when no batch was started yet.
+func (p *plan) batchMigrated() bool {
+	if p.status.CurrentBatch == 0 {
+		return true
+	}
+
+	for _, obj := range p.status.Objects {
+		if obj.Batch == p.status.CurrentBatch && obj.Phase != migrationv1alpha1.ObjectPhaseMigrated {
+			return false
+		}
+	}
+
+	return true
+}
+
+// startBatch assigns up to batchSize MachineSets that are not migrated yet to the next batch.
+func (p *plan) startBatch(batchSize int32) {
+	p.status.CurrentBatch++
+	p.status.BatchCompletedTime = nil
+
+	remaining := max(batchSize, 1)
+
+	for i := range p.status.Objects {
+		obj := &p.status.Objects[i]
+
+		if remaining > 0 && obj.Phase != migrationv1alpha1.ObjectPhaseMigrated && obj.Batch < p.status.CurrentBatch {
+			obj.Batch = p.status.CurrentBatch
+			remaining--
+		}
+	}
+}
+
+// flipBatch marks pending MachineSets of the current batch to be flipped,
+// so that at most maxConcurrency MachineSets are migrating at the same time.
+func (p *plan) flipBatch(maxConcurrency int32, now metav1.Time) {
+	inFlight := int32(0)
+
+	for _, obj := range p.status.Objects {
+		if obj.Batch == p.status.CurrentBatch && obj.Phase == migrationv1alpha1.ObjectPhaseMigrating {
+			inFlight++
+		}
+	}
+
+	for i := range p.status.Objects {
+		obj := &p.status.Objects[i]
+
+		if inFlight < max(maxConcurrency, 1) && obj.Batch == p.status.CurrentBatch && obj.Phase == migrationv1alpha1.ObjectPhasePending {
+			*obj = setObjectPhase(*obj, migrationv1alpha1.ObjectPhaseMigrating, fmt.Sprintf("Set spec.authoritativeAPI to %s", p.status.TargetAuthority), now)
+			p.flip = append(p.flip, obj.Name)
+			inFlight++
+		}
+	}
+}
+
+// fail marks a MachineSet which could not be flipped as failed, and pauses the migration.
+// The MachineSets planned to be flipped after it are left pending.
+func (p *plan) fail(name, message string, now metav1.Time) {
+	unflipped := map[string]bool{}
+
+	for i := len(p.flip) - 1; i >= 0 && p.flip[i] != name; i-- {
+		unflipped[p.flip[i]] = true
+	}
+
+	for i := range p.status.Objects {
+		obj := &p.status.Objects[i]
+
+		switch {
+		case obj.Name == name:
+			*obj = setObjectPhase(*obj, migrationv1alpha1.ObjectPhaseFailed, message, now)
+		case unflipped[obj.Name]:
+			*obj = setObjectPhase(*obj, migrationv1alpha1.ObjectPhasePending, "", now)
+		}
+	}
+
+	p.flip = nil
+	p.pause(fmt.Sprintf("MachineSet %s failed to migrate: %s", name, message))
+}
+
+// pause stops the migration from flipping further MachineSets.
+func (p *plan) pause(message string) {
+	p.status.Phase = migrationv1alpha1.MigrationPhasePaused
+	p.status.Message = message
+	p.requeueAfter = 0
+}
+
+// setObjectPhase sets the phase and message of a MachineSet, and its transition time when the phase changes.
+func setObjectPhase(obj migrationv1alpha1.MachineAPIMigrationObjectStatus, phase migrationv1alpha1.ObjectPhase, message string, now metav1.Time) migrationv1alpha1.MachineAPIMigrationObjectStatus {
+	if obj.Phase != phase {
+		obj.LastTransitionTime = now
+	}
+
+	obj.Phase = phase
+	obj.Message = message
+
+	return obj
+}
+
+// findCondition returns the condition of the given type, or nil when it is not set.
+func findCondition(conditions []machinev1beta1.Condition, condType machinev1beta1.ConditionType) *machinev1beta1.Condition {
+	for i := range conditions {
+		if conditions[i].Type == condType {
+			return &conditions[i]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package machineapimigration
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	migrationv1alpha1 "github.com/openshift/cluster-capi-operator/pkg/apis/migration/v1alpha1"
+	consts "github.com/openshift/cluster-capi-operator/pkg/controllers"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newMachineSet(name string, specAPI, statusAPI machinev1beta1.MachineAuthority, synchronized corev1.ConditionStatus, pausedReason string) machinev1beta1.MachineSet {
+	ms := machinev1beta1.MachineSet{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	ms.Spec.AuthoritativeAPI = specAPI
+	ms.Status.AuthoritativeAPI = statusAPI
+
+	if synchronized != "" {
+		ms.Status.Conditions = append(ms.Status.Conditions, machinev1beta1.Condition{Type: consts.SynchronizedCondition, Status: synchronized, Message: "sync message"})
+	}
+
+	if pausedReason != "" {
+		ms.Status.Conditions = append(ms.Status.Conditions, machinev1beta1.Condition{Type: consts.PausedCondition, Status: corev1.ConditionTrue, Reason: pausedReason})
+	}
+
+	return ms
+}
+
+func pendingMachineSet(name string) machinev1beta1.MachineSet {
+	return newMachineSet(name, machinev1beta1.MachineAuthorityMachineAPI, machinev1beta1.MachineAuthorityMachineAPI, corev1.ConditionTrue, consts.ReasonAuthoritativeAPIMachineAPI)
+}
+
+func migratedMachineSet(name string) machinev1beta1.MachineSet {
+	return newMachineSet(name, machinev1beta1.MachineAuthorityClusterAPI, machinev1beta1.MachineAuthorityClusterAPI, corev1.ConditionTrue, consts.ReasonAuthoritativeAPIClusterAPI)
+}
+
+func objectPhases(status migrationv1alpha1.MachineAPIMigrationStatus) map[string]migrationv1alpha1.ObjectPhase {
+	phases := map[string]migrationv1alpha1.ObjectPhase{}
+	for _, obj := range status.Objects {
+		phases[obj.Name] = obj.Phase
+	}
+
+	return phases
+}
+
+var _ = Describe("MachineAPIMigration plan", func() {
+	var migration *migrationv1alpha1.MachineAPIMigration
+
+	now := metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	BeforeEach(func() {
+		migration = &migrationv1alpha1.MachineAPIMigration{
+			ObjectMeta: metav1.ObjectMeta{Name: "migration", Generation: 1},
+			Spec: migrationv1alpha1.MachineAPIMigrationSpec{
+				TargetAuthority: machinev1beta1.MachineAuthorityClusterAPI,
+				BatchSize:       2,
+				MaxConcurrency:  1,
+				SettleDuration:  &metav1.Duration{Duration: time.Minute},
+			},
+		}
+	})
+
+	DescribeTable("should observe the migration phase of a MachineSet",
+		func(ms machinev1beta1.MachineSet, expected migrationv1alpha1.ObjectPhase) {
+			phase, _ := observeMachineSet(&ms, machinev1beta1.MachineAuthorityClusterAPI)
+			Expect(phase).To(Equal(expected))
+		},
+		Entry("when spec.authoritativeAPI is not the target", pendingMachineSet("a"), migrationv1alpha1.ObjectPhasePending),
+		Entry("when status.authoritativeAPI is not the target yet",
+			newMachineSet("a", machinev1beta1.MachineAuthorityClusterAPI, machinev1beta1.MachineAuthorityMigrating, corev1.ConditionTrue, consts.ReasonAuthoritativeAPIMachineAPI),
+			migrationv1alpha1.ObjectPhaseMigrating),
+		Entry("when the MAPI resources are not paused yet",
+			newMachineSet("a", machinev1beta1.MachineAuthorityClusterAPI, machinev1beta1.MachineAuthorityClusterAPI, corev1.ConditionTrue, consts.ReasonAuthoritativeAPIMachineAPI),
+			migrationv1alpha1.ObjectPhaseMigrating),
+		Entry("when the MachineSet is not synchronized yet",
+			newMachineSet("a", machinev1beta1.MachineAuthorityClusterAPI, machinev1beta1.MachineAuthorityClusterAPI, "", consts.ReasonAuthoritativeAPIClusterAPI),
+			migrationv1alpha1.ObjectPhaseMigrating),
+		Entry("when the MachineSet failed to synchronize",
+			newMachineSet("a", machinev1beta1.MachineAuthorityClusterAPI, machinev1beta1.MachineAuthorityClusterAPI, corev1.ConditionFalse, consts.ReasonAuthoritativeAPIClusterAPI),
+			migrationv1alpha1.ObjectPhaseFailed),
+		Entry("when the MachineSet is synchronized and paused", migratedMachineSet("a"), migrationv1alpha1.ObjectPhaseMigrated),
+	)
+
+	It("should start the first batch and flip up to the maximum concurrency", func() {
+		p := newPlan(migration, []machinev1beta1.MachineSet{pendingMachineSet("c"), pendingMachineSet("a"), pendingMachineSet("b")}, now)
+
+		Expect(p.status.Phase).To(Equal(migrationv1alpha1.MigrationPhaseProgressing))
+		Expect(p.status.CurrentBatch).To(BeEquivalentTo(1))
+		Expect(p.flip).To(Equal([]string{"a"}))
+		Expect(p.status.Objects).To(HaveLen(3))
+		Expect(p.status.Objects[0].Batch).To(BeEquivalentTo(1))
+		Expect(p.status.Objects[1].Batch).To(BeEquivalentTo(1))
+		Expect(p.status.Objects[2].Batch).To(BeEquivalentTo(0))
+		Expect(objectPhases(p.status)).To(Equal(map[string]migrationv1alpha1.ObjectPhase{
+			"a": migrationv1alpha1.ObjectPhaseMigrating,
+			"b": migrationv1alpha1.ObjectPhasePending,
+			"c": migrationv1alpha1.ObjectPhasePending,
+		}))
+	})
+
+	It("should not flip more MachineSets while the maximum concurrency is reached", func() {
+		migration.Status = newPlan(migration, []machinev1beta1.MachineSet{pendingMachineSet("a"), pendingMachineSet("b")}, now).status
+
+		inFlight := newMachineSet("a", machinev1beta1.MachineAuthorityClusterAPI, machinev1beta1.MachineAuthorityMigrating, "", "")
+		p := newPlan(migration, []machinev1beta1.MachineSet{inFlight, pendingMachineSet("b")}, now)
+
+		Expect(p.flip).To(BeEmpty())
+	})
+
+	It("should wait for a migrated batch to settle before starting the next batch", func() {
+		migration.Spec.BatchSize = 1
+		migration.Status = newPlan(migration, []machinev1beta1.MachineSet{pendingMachineSet("a"), pendingMachineSet("b")}, now).status
+
+		machineSets := []machinev1beta1.MachineSet{migratedMachineSet("a"), pendingMachineSet("b")}
+
+		p := newPlan(migration, machineSets, now)
+		Expect(p.flip).To(BeEmpty())
+		Expect(p.status.BatchCompletedTime).To(Equal(&now))
+		Expect(p.requeueAfter).To(Equal(time.Minute))
+
+		migration.Status = p.status
+		p = newPlan(migration, machineSets, metav1.NewTime(now.Add(time.Minute)))
+		Expect(p.status.CurrentBatch).To(BeEquivalentTo(2))
+		Expect(p.status.BatchCompletedTime).To(BeNil())
+		Expect(p.flip).To(Equal([]string{"b"}))
+	})
+
+	It("should complete once every MachineSet is migrated", func() {
+		p := newPlan(migration, []machinev1beta1.MachineSet{migratedMachineSet("a"), migratedMachineSet("b")}, now)
+
+		Expect(p.status.Phase).To(Equal(migrationv1alpha1.MigrationPhaseCompleted))
+		Expect(p.flip).To(BeEmpty())
+	})
+
+	It("should not flip any MachineSet while paused", func() {
+		migration.Spec.Paused = true
+		p := newPlan(migration, []machinev1beta1.MachineSet{pendingMachineSet("a")}, now)
+
+		Expect(p.status.Phase).To(Equal(migrationv1alpha1.MigrationPhasePaused))
+		Expect(p.flip).To(BeEmpty())
+	})
+
+	It("should pause when a MachineSet fails to synchronize", func() {
+		failed := newMachineSet("a", machinev1beta1.MachineAuthorityClusterAPI, machinev1beta1.MachineAuthorityClusterAPI, corev1.ConditionFalse, "")
+		p := newPlan(migration, []machinev1beta1.MachineSet{failed, pendingMachineSet("b")}, now)
+
+		Expect(p.status.Phase).To(Equal(migrationv1alpha1.MigrationPhasePaused))
+		Expect(p.status.Message).To(ContainSubstring("MachineSet a failed to migrate"))
+		Expect(p.flip).To(BeEmpty())
+	})
+
+	Context("when a flip is denied", func() {
+		var p *plan
+
+		BeforeEach(func() {
+			migration.Spec.MaxConcurrency = 2
+			p = newPlan(migration, []machinev1beta1.MachineSet{pendingMachineSet("a"), pendingMachineSet("b")}, now)
+			Expect(p.flip).To(Equal([]string{"a", "b"}))
+
+			p.fail("a", "denied", now)
+		})
+
+		It("should pause the migration and leave the MachineSets flipped after it pending", func() {
+			Expect(p.status.Phase).To(Equal(migrationv1alpha1.MigrationPhasePaused))
+			Expect(p.flip).To(BeEmpty())
+			Expect(objectPhases(p.status)).To(Equal(map[string]migrationv1alpha1.ObjectPhase{
+				"a": migrationv1alpha1.ObjectPhaseFailed,
+				"b": migrationv1alpha1.ObjectPhasePending,
+			}))
+		})
+
+		It("should keep the MachineSet failed until the spec of the migration changes", func() {
+			migration.Status = p.status
+			machineSets := []machinev1beta1.MachineSet{pendingMachineSet("a"), pendingMachineSet("b")}
+
+			Expect(newPlan(migration, machineSets, now).status.Phase).To(Equal(migrationv1alpha1.MigrationPhasePaused))
+
+			migration.Generation++
+			retried := newPlan(migration, machineSets, now)
+			Expect(retried.status.Phase).To(Equal(migrationv1alpha1.MigrationPhaseProgressing))
+			Expect(retried.flip).To(Equal([]string{"a", "b"}))
+		})
+	})
+
+	It("should plan the batches again when rolling back", func() {
+		migration.Status = newPlan(migration, []machinev1beta1.MachineSet{pendingMachineSet("a"), pendingMachineSet("b")}, now).status
+
+		migration.Generation++
+		migration.Spec.TargetAuthority = machinev1beta1.MachineAuthorityMachineAPI
+		inFlight := newMachineSet("a", machinev1beta1.MachineAuthorityClusterAPI, machinev1beta1.MachineAuthorityMigrating, "", "")
+
+		p := newPlan(migration, []machinev1beta1.MachineSet{inFlight, pendingMachineSet("b")}, now)
+		Expect(p.status.TargetAuthority).To(Equal(machinev1beta1.MachineAuthorityMachineAPI))
+		Expect(p.status.CurrentBatch).To(BeEquivalentTo(1))
+		Expect(p.flip).To(Equal([]string{"a"}))
+		Expect(objectPhases(p.status)).To(HaveKeyWithValue("b", migrationv1alpha1.ObjectPhaseMigrated))
+	})
+})
@@ -0,0 +1,75 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package machinesetsync
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// toApplyObject returns an unstructured apply configuration holding only the fields produced by the conversion.
+// Server populated metadata and the status are removed, as are the empty values left by fields without omitempty,
+// so that the controller does not take ownership of, and reset, fields it never set.
+func toApplyObject(obj client.Object, gvk schema.GroupVersionKind) (*unstructured.Unstructured, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert %T to unstructured: %w", obj, err)
+	}
+
+	u := &unstructured.Unstructured{Object: content}
+	u.SetGroupVersionKind(gvk)
+
+	for _, field := range []string{"creationTimestamp", "deletionTimestamp", "generation", "managedFields", "resourceVersion", "selfLink", "uid"} {
+		unstructured.RemoveNestedField(u.Object, "metadata", field)
+	}
+
+	unstructured.RemoveNestedField(u.Object, "status")
+	pruneEmptyFields(u.Object)
+
+	return u, nil
+}
+
+// pruneEmptyFields removes the null values, empty strings and empty objects from content, recursing into nested
+// objects and lists. Zero numbers, false and empty lists are kept, so that explicitly set zero values,
+// such as zero replicas, are applied.
+func pruneEmptyFields(content map[string]interface{}) {
+	for key, value := range content {
+		switch v := value.(type) {
+		case nil:
+			delete(content, key)
+		case string:
+			if v == "" {
+				delete(content, key)
+			}
+		case map[string]interface{}:
+			pruneEmptyFields(v)
+
+			if len(v) == 0 {
+				delete(content, key)
+			}
+		case []interface{}:
+			for _, item := range v {
+				if m, ok := item.(map[string]interface{}); ok {
+					pruneEmptyFields(m)
+				}
+			}
+		}
+	}
+}
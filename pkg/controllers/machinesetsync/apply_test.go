@@ -0,0 +1,129 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package machinesetsync
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+var _ = Describe("toApplyObject", func() {
+	var capiMachineSet *capiv1beta1.MachineSet
+
+	BeforeEach(func() {
+		capiMachineSet = &capiv1beta1.MachineSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "foo",
+				Namespace:         "openshift-cluster-api",
+				ResourceVersion:   "1",
+				CreationTimestamp: metav1.Now(),
+			},
+			Spec: capiv1beta1.MachineSetSpec{
+				ClusterName: "cluster-foo",
+				Replicas:    ptr.To[int32](0),
+				Template: capiv1beta1.MachineTemplateSpec{
+					Spec: capiv1beta1.MachineSpec{
+						InfrastructureRef: corev1.ObjectReference{Kind: "AWSMachineTemplate", Name: "foo"},
+					},
+				},
+			},
+			Status: capiv1beta1.MachineSetStatus{Replicas: 1},
+		}
+	})
+
+	It("should only hold the fields set on the object", func() {
+		applyObj, err := toApplyObject(capiMachineSet, capiv1beta1.GroupVersion.WithKind("MachineSet"))
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(applyObj.Object).To(Equal(map[string]interface{}{
+			"apiVersion": "cluster.x-k8s.io/v1beta1",
+			"kind":       "MachineSet",
+			"metadata": map[string]interface{}{
+				"name":      "foo",
+				"namespace": "openshift-cluster-api",
+			},
+			"spec": map[string]interface{}{
+				"clusterName": "cluster-foo",
+				"replicas":    int64(0),
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"infrastructureRef": map[string]interface{}{
+							"kind": "AWSMachineTemplate",
+							"name": "foo",
+						},
+					},
+				},
+			},
+		}))
+	})
+
+	It("should not hold the zero value of fields which are not omitted when empty", func() {
+		applyObj, err := toApplyObject(capiMachineSet, capiv1beta1.GroupVersion.WithKind("MachineSet"))
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(applyObj.Object).ToNot(HaveKeyWithValue("spec", HaveKey("selector")))
+		Expect(applyObj.Object).ToNot(HaveKeyWithValue("spec", HaveKeyWithValue("template", HaveKeyWithValue("spec", HaveKey("bootstrap")))))
+		Expect(applyObj.Object).ToNot(HaveKeyWithValue("spec", HaveKeyWithValue("template", HaveKeyWithValue("spec", HaveKey("clusterName")))))
+		Expect(applyObj.Object).ToNot(HaveKeyWithValue("spec", HaveKeyWithValue("template", HaveKey("metadata"))))
+	})
+})
+
+var _ = Describe("convertedMetadataIsSet", func() {
+	var existing, converted *capiv1beta1.MachineSet
+
+	BeforeEach(func() {
+		converted = &capiv1beta1.MachineSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "foo",
+				Annotations: map[string]string{capiv1beta1.PausedAnnotation: ""},
+			},
+		}
+
+		existing = &capiv1beta1.MachineSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo",
+				Annotations: map[string]string{
+					capiv1beta1.PausedAnnotation:  "",
+					"other-controller/annotation": "bar",
+				},
+				OwnerReferences: []metav1.OwnerReference{{Kind: "Cluster", Name: "cluster-foo"}},
+				Finalizers:      []string{"other-controller/finalizer"},
+			},
+		}
+	})
+
+	It("should ignore metadata set by other controllers", func() {
+		Expect(convertedMetadataIsSet(existing, converted)).To(BeTrue())
+	})
+
+	It("should detect a converted annotation missing from the existing object", func() {
+		existing.SetAnnotations(map[string]string{"other-controller/annotation": "bar"})
+
+		Expect(convertedMetadataIsSet(existing, converted)).To(BeFalse())
+	})
+
+	It("should detect a converted label with a different value on the existing object", func() {
+		converted.SetLabels(map[string]string{"foo": "bar"})
+		existing.SetLabels(map[string]string{"foo": "baz"})
+
+		Expect(convertedMetadataIsSet(existing, converted)).To(BeFalse())
+	})
+})
@@ -0,0 +1,66 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package machinesetsync
+
+import (
+	"context"
+	"fmt"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	machinev1applyconfigs "github.com/openshift/client-go/machine/applyconfigurations/machine/v1beta1"
+	"github.com/openshift/cluster-capi-operator/pkg/controllers/syncmetrics"
+	"github.com/openshift/cluster-capi-operator/pkg/conversion/report"
+	"github.com/openshift/cluster-capi-operator/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// reportConversion logs and records an event for each warning of the conversion report, counts the conversion
+// in the sync metrics, and surfaces the report on the LosslessConversion condition of the MAPI MachineSet.
+// The conversion error is only used to mark the condition as failed when the report holds no error entries.
+func (r *MachineSetSyncReconciler) reportConversion(ctx context.Context, mapiMachineSet *machinev1beta1.MachineSet, direction report.Direction, conversionReport report.Report, conversionErr error) error {
+	logger := log.FromContext(ctx)
+
+	syncmetrics.RecordConversion(r.Platform, direction, conversionReport, conversionErr)
+
+	for _, warning := range conversionReport.Warnings() {
+		logger.Info("Warning during conversion", "warning", warning)
+		r.Recorder.Event(mapiMachineSet, corev1.EventTypeWarning, "ConversionWarning", warning)
+	}
+
+	return r.updateLosslessConversionConditionWithPatch(ctx, mapiMachineSet, conversionReport, conversionErr)
+}
+
+// updateLosslessConversionConditionWithPatch updates the lossless conversion condition
+// using a server side apply patch. A separate field owner is used so that
+// the 'Synchronized' condition is not removed by this patch.
+func (r *MachineSetSyncReconciler) updateLosslessConversionConditionWithPatch(ctx context.Context, mapiMachineSet *machinev1beta1.MachineSet, conversionReport report.Report, conversionErr error) error {
+	conditionAc, err := util.LosslessConversionCondition(mapiMachineSet.Status.Conditions, conversionReport, conversionErr)
+	if err != nil {
+		return fmt.Errorf("failed to build lossless conversion condition: %w", err)
+	}
+
+	msAc := machinev1applyconfigs.MachineSet(mapiMachineSet.GetName(), mapiMachineSet.GetNamespace()).
+		WithStatus(machinev1applyconfigs.MachineSetStatus().WithConditions(conditionAc))
+
+	if err := r.Status().Patch(ctx, mapiMachineSet, util.ApplyConfigPatch(msAc), client.ForceOwnership, client.FieldOwner("machineset-sync-controller-conversion-report")); err != nil {
+		return fmt.Errorf("failed to patch MAPI machine set status with lossless conversion condition: %w", err)
+	}
+
+	return nil
+}
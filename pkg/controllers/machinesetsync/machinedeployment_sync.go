@@ -0,0 +1,345 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package machinesetsync
+
+import (
+	"context"
+	"fmt"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	machinev1applyconfigs "github.com/openshift/client-go/machine/applyconfigurations/machine/v1beta1"
+	consts "github.com/openshift/cluster-capi-operator/pkg/controllers"
+	"github.com/openshift/cluster-capi-operator/pkg/controllers/syncmetrics"
+	"github.com/openshift/cluster-capi-operator/pkg/conversion/report"
+	"github.com/openshift/cluster-capi-operator/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	reasonFailedToConvertCAPIMachineDeploymentToMAPI = "FailedToConvertCAPIMachineDeploymentToMAPI"
+	reasonFailedToConvertMAPIMachineSetToCAPIMD      = "FailedToConvertMAPIMachineSetToCAPIMachineDeployment"
+	reasonFailedToApplyCAPIMachineDeployment         = "FailedToApplyCAPIMachineDeployment"
+
+	messageSuccessfullySynchronizedMachineDeployment = "Successfully synchronized CAPI MachineDeployment to MAPI"
+
+	// replicasFieldOwner is the field owner used to patch the replica counts of a MAPI MachineSet
+	// mirroring a CAPI MachineDeployment. It is separate from the owner of the Synchronized
+	// condition so that the two patches do not remove each other's fields.
+	replicasFieldOwner = "machineset-sync-controller-replicas"
+)
+
+// fetchCAPIMachineDeployment fetches the CAPI MachineDeployment mirroring a MAPI MachineSet.
+func (r *MachineSetSyncReconciler) fetchCAPIMachineDeployment(ctx context.Context, name string) (*capiv1beta1.MachineDeployment, error) {
+	logger := log.FromContext(ctx)
+
+	capiMachineDeployment := &capiv1beta1.MachineDeployment{}
+
+	if err := r.Get(ctx, client.ObjectKey{Namespace: r.CAPINamespace, Name: name}, capiMachineDeployment); apierrors.IsNotFound(err) {
+		logger.Info("CAPI machine deployment not found")
+
+		capiMachineDeployment = nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get CAPI machine deployment: %w", err)
+	}
+
+	return capiMachineDeployment, nil
+}
+
+// syncMachineDeployments synchronizes a MAPI MachineSet with a CAPI MachineDeployment based on the authoritative API.
+func (r *MachineSetSyncReconciler) syncMachineDeployments(ctx context.Context, mapiMachineSet *machinev1beta1.MachineSet) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	capiMachineDeployment, err := r.fetchCAPIMachineDeployment(ctx, mapiMachineSet.Name)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to fetch machine deployment: %w", err)
+	}
+
+	if capiMachineDeployment != nil {
+		if err := r.forwardReplicas(ctx, mapiMachineSet, capiMachineDeployment, capiMachineDeployment.Spec.Replicas); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	authoritativeAPI := mapiMachineSet.Status.AuthoritativeAPI
+
+	switch {
+	case authoritativeAPI == machinev1beta1.MachineAuthorityMachineAPI:
+		return r.reconcileMAPIMachineSetToCAPIMachineDeployment(ctx, mapiMachineSet)
+	case authoritativeAPI == machinev1beta1.MachineAuthorityClusterAPI && capiMachineDeployment == nil:
+		return r.reconcileMAPIMachineSetToCAPIMachineDeployment(ctx, mapiMachineSet)
+	case authoritativeAPI == machinev1beta1.MachineAuthorityClusterAPI && capiMachineDeployment != nil:
+		return r.reconcileCAPIMachineDeploymentToMAPIMachineSet(ctx, capiMachineDeployment, mapiMachineSet)
+	case authoritativeAPI == machinev1beta1.MachineAuthorityMigrating:
+		logger.Info("machine set is currently being migrated")
+		return ctrl.Result{}, nil
+
+	default:
+		logger.Info("unexpected value for authoritativeAPI", "AuthoritativeAPI", mapiMachineSet.Status.AuthoritativeAPI)
+
+		return ctrl.Result{}, nil
+	}
+}
+
+// reconcileMAPIMachineSetToCAPIMachineDeployment reconciles a MAPI MachineSet to a CAPI MachineDeployment.
+func (r *MachineSetSyncReconciler) reconcileMAPIMachineSetToCAPIMachineDeployment(ctx context.Context, mapiMachineSet *machinev1beta1.MachineSet) (ctrl.Result, error) {
+	syncmetrics.ObserveGeneration(machineSetKind, mapiMachineSet.Name, mapiMachineSet.Generation, mapiMachineSet.Status.SynchronizedGeneration)
+
+	newCAPIMachineDeployment, newCAPIInfraMachineTemplate, conversionReport, err := r.convertMAPIToCAPIMachineDeployment(mapiMachineSet)
+	if reportErr := r.reportConversion(ctx, mapiMachineSet, report.MAPIToCAPI, conversionReport, err); reportErr != nil {
+		return ctrl.Result{}, utilerrors.NewAggregate([]error{err, reportErr})
+	}
+
+	if err != nil {
+		conversionErr := fmt.Errorf("failed to convert MAPI machine set to CAPI machine deployment: %w", err)
+		if condErr := r.updateSynchronizedConditionWithPatch(ctx, mapiMachineSet, corev1.ConditionFalse, reasonFailedToConvertMAPIMachineSetToCAPIMD, conversionErr.Error(), nil); condErr != nil {
+			return ctrl.Result{}, utilerrors.NewAggregate([]error{conversionErr, condErr})
+		}
+
+		return ctrl.Result{}, conversionErr
+	}
+
+	newCAPIMachineDeployment.SetNamespace(r.CAPINamespace)
+	newCAPIMachineDeployment.Spec.Template.Spec.InfrastructureRef.Namespace = r.CAPINamespace
+	newCAPIInfraMachineTemplate.SetNamespace(r.CAPINamespace)
+
+	// The InfraMachineTemplates of a MachineDeployment are labelled, so the ones it rolled away from can be deleted.
+	newCAPIInfraMachineTemplate.SetLabels(util.MergeMaps(newCAPIInfraMachineTemplate.GetLabels(),
+		map[string]string{capiv1beta1.MachineDeploymentNameLabel: newCAPIMachineDeployment.Name}))
+
+	setPausedAnnotations(newCAPIMachineDeployment, mapiMachineSet.Status.AuthoritativeAPI, true)
+	setPausedAnnotations(newCAPIInfraMachineTemplate, mapiMachineSet.Status.AuthoritativeAPI, true)
+
+	// The InfraMachineTemplate name contains a hash of its spec, so a change in the providerSpec
+	// results in a new InfraMachineTemplate and the MachineDeployment rolls out new Machines.
+	if err := r.applyCAPIInfraMachineTemplate(ctx, mapiMachineSet, newCAPIInfraMachineTemplate); err != nil {
+		return ctrl.Result{}, fmt.Errorf("unable to ensure CAPI infra machine template: %w", err)
+	}
+
+	if err := r.applyCAPIResource(ctx, mapiMachineSet, newCAPIMachineDeployment, reasonFailedToApplyCAPIMachineDeployment); err != nil {
+		return ctrl.Result{}, fmt.Errorf("unable to ensure CAPI machine deployment: %w", err)
+	}
+
+	if err := r.recordSynchronizedGeneration(ctx, newCAPIMachineDeployment); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.syncPaused(ctx, mapiMachineSet, newCAPIMachineDeployment, newCAPIInfraMachineTemplate); err != nil {
+		return ctrl.Result{}, fmt.Errorf("unable to ensure paused state of machine sets: %w", err)
+	}
+
+	if err := r.adoptCAPIMachineSet(ctx, newCAPIMachineDeployment); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.deleteStaleInfraMachineTemplates(ctx, newCAPIMachineDeployment); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, r.updateSynchronizedConditionWithPatch(ctx, mapiMachineSet, corev1.ConditionTrue,
+		consts.ReasonResourceSynchronized, messageSuccessfullySynchronizedMachineDeployment, &mapiMachineSet.Generation)
+}
+
+// reconcileCAPIMachineDeploymentToMAPIMachineSet reconciles a CAPI MachineDeployment to a MAPI MachineSet.
+// The MAPI MachineSet reports the replica counts of the MachineDeployment, aggregated across all of its CAPI MachineSets.
+func (r *MachineSetSyncReconciler) reconcileCAPIMachineDeploymentToMAPIMachineSet(ctx context.Context, capiMachineDeployment *capiv1beta1.MachineDeployment, mapiMachineSet *machinev1beta1.MachineSet) (ctrl.Result, error) {
+	syncmetrics.ObserveGeneration(machineSetKind, mapiMachineSet.Name, capiMachineDeployment.Generation, mapiMachineSet.Status.SynchronizedGeneration)
+
+	infraCluster, infraMachineTemplate, err := r.fetchCAPIInfraResources(ctx, capiMachineDeployment.Namespace,
+		capiMachineDeployment.Spec.ClusterName, capiMachineDeployment.Spec.Template.Spec.InfrastructureRef)
+	if err != nil {
+		fetchErr := fmt.Errorf("failed to fetch CAPI infra resources: %w", err)
+
+		if condErr := r.updateSynchronizedConditionWithPatch(
+			ctx, mapiMachineSet, corev1.ConditionFalse, reasonFailedToGetCAPIInfraResources, fetchErr.Error(), nil); condErr != nil {
+			return ctrl.Result{}, utilerrors.NewAggregate([]error{fetchErr, condErr})
+		}
+
+		return ctrl.Result{}, fetchErr
+	}
+
+	newMapiMachineSet, conversionReport, err := r.convertCAPIMachineDeploymentToMAPIMachineSet(capiMachineDeployment, infraMachineTemplate, infraCluster)
+	if reportErr := r.reportConversion(ctx, mapiMachineSet, report.CAPIToMAPI, conversionReport, err); reportErr != nil {
+		return ctrl.Result{}, utilerrors.NewAggregate([]error{err, reportErr})
+	}
+
+	if err != nil {
+		conversionErr := fmt.Errorf("failed to convert CAPI machine deployment to MAPI machine set: %w", err)
+
+		if condErr := r.updateSynchronizedConditionWithPatch(
+			ctx, mapiMachineSet, corev1.ConditionFalse, reasonFailedToConvertCAPIMachineDeploymentToMAPI, conversionErr.Error(), nil); condErr != nil {
+			return ctrl.Result{}, utilerrors.NewAggregate([]error{conversionErr, condErr})
+		}
+
+		return ctrl.Result{}, conversionErr
+	}
+
+	setPausedAnnotations(newMapiMachineSet, mapiMachineSet.Status.AuthoritativeAPI, false)
+
+	if err := r.updateMAPIMachineSet(ctx, mapiMachineSet, newMapiMachineSet); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.syncPaused(ctx, mapiMachineSet, capiMachineDeployment, infraMachineTemplate); err != nil {
+		return ctrl.Result{}, fmt.Errorf("unable to ensure paused state of machine sets: %w", err)
+	}
+
+	if err := r.updateReplicasWithPatch(ctx, mapiMachineSet, capiMachineDeployment.Status); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.adoptCAPIMachineSet(ctx, capiMachineDeployment); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.deleteStaleInfraMachineTemplates(ctx, capiMachineDeployment); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, r.updateSynchronizedConditionWithPatch(ctx, mapiMachineSet, corev1.ConditionTrue,
+		consts.ReasonResourceSynchronized, messageSuccessfullySynchronizedMachineDeployment, &capiMachineDeployment.Generation)
+}
+
+// convertCAPIMachineDeploymentToMAPIMachineSet converts a CAPI MachineDeployment to a MAPI MachineSet using the converter registered for the platform.
+func (r *MachineSetSyncReconciler) convertCAPIMachineDeploymentToMAPIMachineSet(capiMachineDeployment *capiv1beta1.MachineDeployment, infraMachineTemplate client.Object, infraCluster client.Object) (*machinev1beta1.MachineSet, report.Report, error) {
+	converter, err := r.conversion.FromCAPIMachineDeployment(capiMachineDeployment, infraMachineTemplate, infraCluster)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create CAPI machine deployment converter: %w", err)
+	}
+
+	return converter.ToMachineSet() //nolint:wrapcheck
+}
+
+// convertMAPIToCAPIMachineDeployment converts a MAPI MachineSet to a CAPI MachineDeployment using the converter registered for the platform.
+func (r *MachineSetSyncReconciler) convertMAPIToCAPIMachineDeployment(mapiMachineSet *machinev1beta1.MachineSet) (*capiv1beta1.MachineDeployment, client.Object, report.Report, error) {
+	return r.conversion.FromMAPIMachineSet(mapiMachineSet, r.Infra).ToMachineDeploymentAndMachineTemplate() //nolint:wrapcheck
+}
+
+// updateReplicasWithPatch updates the replica counts of a MAPI MachineSet from the status of the CAPI MachineDeployment
+// it mirrors, using a server side apply patch.
+func (r *MachineSetSyncReconciler) updateReplicasWithPatch(ctx context.Context, mapiMachineSet *machinev1beta1.MachineSet, status capiv1beta1.MachineDeploymentStatus) error {
+	// CAPI does not track fully labeled replicas, all replicas carry the labels of the MachineDeployment selector.
+	statusAc := machinev1applyconfigs.MachineSetStatus().
+		WithReplicas(status.Replicas).
+		WithFullyLabeledReplicas(status.Replicas).
+		WithReadyReplicas(status.ReadyReplicas).
+		WithAvailableReplicas(status.AvailableReplicas)
+
+	msAc := machinev1applyconfigs.MachineSet(mapiMachineSet.GetName(), mapiMachineSet.GetNamespace()).
+		WithStatus(statusAc)
+
+	if err := r.Status().Patch(ctx, mapiMachineSet, util.ApplyConfigPatch(msAc), client.ForceOwnership, client.FieldOwner(replicasFieldOwner)); err != nil {
+		return fmt.Errorf("failed to patch MAPI machine set status with machine deployment replicas: %w", err)
+	}
+
+	return nil
+}
+
+// adoptCAPIMachineSet hands the CAPI MachineSet which mirrored the MAPI MachineSet, before it was opted into
+// a MachineDeployment, over to the MachineDeployment. The MachineDeployment then rolls its existing Machines
+// out to its own MachineSets, rather than leaving them behind.
+func (r *MachineSetSyncReconciler) adoptCAPIMachineSet(ctx context.Context, capiMachineDeployment *capiv1beta1.MachineDeployment) error {
+	logger := log.FromContext(ctx)
+
+	capiMachineSet := &capiv1beta1.MachineSet{}
+
+	if err := r.Get(ctx, client.ObjectKey{Namespace: capiMachineDeployment.Namespace, Name: capiMachineDeployment.Name}, capiMachineSet); apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to get CAPI machine set to adopt: %w", err)
+	}
+
+	if metav1.GetControllerOf(capiMachineSet) != nil {
+		return nil
+	}
+
+	patchBase := client.MergeFrom(capiMachineSet.DeepCopy())
+
+	if err := controllerutil.SetControllerReference(capiMachineDeployment, capiMachineSet, r.Scheme); err != nil {
+		return fmt.Errorf("failed to set CAPI machine deployment as the controller of the CAPI machine set: %w", err)
+	}
+
+	// The MachineDeployment only manages the MachineSets matching its selector and labelled with its name.
+	deploymentLabels := util.MergeMaps(capiMachineDeployment.Spec.Selector.MatchLabels,
+		map[string]string{capiv1beta1.MachineDeploymentNameLabel: capiMachineDeployment.Name})
+	capiMachineSet.Labels = util.MergeMaps(capiMachineSet.Labels, deploymentLabels)
+	capiMachineSet.Spec.Template.Labels = util.MergeMaps(capiMachineSet.Spec.Template.Labels, deploymentLabels)
+
+	if err := r.Patch(ctx, capiMachineSet, patchBase); err != nil {
+		return fmt.Errorf("failed to adopt CAPI machine set into the CAPI machine deployment: %w", err)
+	}
+
+	logger.Info("Adopted CAPI machine set into the CAPI machine deployment", "machineset", capiMachineSet.Name)
+
+	return nil
+}
+
+// deleteStaleInfraMachineTemplates deletes the InfraMachineTemplates created for a MachineDeployment once it has rolled
+// away from them, that is when neither the MachineDeployment nor any of its MachineSets reference them anymore.
+// Old MachineSets are deleted by the MachineDeployment as soon as they are scaled down, see the RevisionHistoryLimit.
+func (r *MachineSetSyncReconciler) deleteStaleInfraMachineTemplates(ctx context.Context, capiMachineDeployment *capiv1beta1.MachineDeployment) error {
+	logger := log.FromContext(ctx)
+
+	deploymentLabel := client.MatchingLabels{capiv1beta1.MachineDeploymentNameLabel: capiMachineDeployment.Name}
+
+	machineSets := &capiv1beta1.MachineSetList{}
+	if err := r.List(ctx, machineSets, client.InNamespace(capiMachineDeployment.Namespace), deploymentLabel); err != nil {
+		return fmt.Errorf("failed to list CAPI machine sets of the CAPI machine deployment: %w", err)
+	}
+
+	inUse := map[string]bool{capiMachineDeployment.Spec.Template.Spec.InfrastructureRef.Name: true}
+	for _, ms := range machineSets.Items {
+		inUse[ms.Spec.Template.Spec.InfrastructureRef.Name] = true
+	}
+
+	gvk, err := apiutil.GVKForObject(r.conversion.NewInfraMachineTemplate(), r.Scheme)
+	if err != nil {
+		return fmt.Errorf("failed to get GroupVersionKind for the infra machine template: %w", err)
+	}
+
+	templates := &unstructured.UnstructuredList{}
+	templates.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+
+	if err := r.List(ctx, templates, client.InNamespace(capiMachineDeployment.Namespace), deploymentLabel); err != nil {
+		return fmt.Errorf("failed to list CAPI infra machine templates of the CAPI machine deployment: %w", err)
+	}
+
+	for i := range templates.Items {
+		template := &templates.Items[i]
+		if inUse[template.GetName()] {
+			continue
+		}
+
+		if err := r.Delete(ctx, template); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete stale CAPI infra machine template %q: %w", template.GetName(), err)
+		}
+
+		logger.Info("Deleted stale CAPI infra machine template", "name", template.GetName())
+	}
+
+	return nil
+}
@@ -18,16 +18,18 @@ package machinesetsync
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"reflect"
+	"slices"
 
 	"github.com/go-logr/logr"
 	configv1 "github.com/openshift/api/config/v1"
 	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
 	consts "github.com/openshift/cluster-capi-operator/pkg/controllers"
-	"github.com/openshift/cluster-capi-operator/pkg/conversion/capi2mapi"
-	"github.com/openshift/cluster-capi-operator/pkg/conversion/mapi2capi"
+	"github.com/openshift/cluster-capi-operator/pkg/controllers/syncmetrics"
+	"github.com/openshift/cluster-capi-operator/pkg/conversion"
+	"github.com/openshift/cluster-capi-operator/pkg/conversion/report"
+	conversionutil "github.com/openshift/cluster-capi-operator/pkg/conversion/util"
 	"github.com/openshift/cluster-capi-operator/pkg/util"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -38,43 +40,33 @@ import (
 
 	machinev1applyconfigs "github.com/openshift/client-go/machine/applyconfigurations/machine/v1beta1"
 	"k8s.io/client-go/tools/record"
-	awscapiv1beta1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
 	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
-var (
-	// errPlatformNotSupported is returned when the platform is not supported.
-	errPlatformNotSupported = errors.New("error determining InfraMachineTemplate type, platform not supported")
-
-	// errUnexpectedInfraMachineTemplateType is returned when we receive an unexpected InfraMachineTemplate type.
-	errUnexpectedInfraMachineTemplateType = errors.New("unexpected InfraMachineTemplate type")
-
-	// errUnexpectedInfraClusterType is returned when we receive an unexpected InfraCluster type.
-	errUnexpectedInfraClusterType = errors.New("unexpected InfraCluster type")
-
-	// errAssertingCAPIAWSMachineTemplate is returned when we encounter an issue asserting a client.Object into a AWSMachineTemplate.
-	errAssertingCAPIAWSMachineTemplate = errors.New("error asserting the CAPI AWSMachineTemplate object")
-)
-
 const (
-	reasonFailedToGetCAPIInfraResources          = "FailedToGetCAPIInfraResources"
-	reasonFailedToConvertCAPIMachineSetToMAPI    = "FailedToConvertCAPIMachineSetToMAPI"
-	reasonFailedToConvertMAPIMachineSetToCAPI    = "FailedToConvertMAPIMachineSetToCAPI"
-	reasonFailedToUpdateMAPIMachineSet           = "FailedToUpdateMAPIMachineSet"
-	reasonFailedToUpdateCAPIMachineSet           = "FailedToUpdateCAPIMachineSet"
-	reasonFailedToUpdateCAPIInfraMachineTemplate = "FailedToUpdateCAPIInfraMachineTemplate"
-	reasonFailedToCreateCAPIMachineSet           = "FailedToCreateCAPIMachineSet"
-	reasonFailedToCreateCAPIInfraMachineTemplate = "FailedToCreateCAPIInfraMachineTemplate"
-	reasonFailedToGetCAPIMachineSet              = "FailedToGetCAPIMachineSet"
-	reasonResourceSynchronized                   = "ResourceSynchronized"
+	reasonFailedToGetCAPIInfraResources         = "FailedToGetCAPIInfraResources"
+	reasonFailedToConvertCAPIMachineSetToMAPI   = "FailedToConvertCAPIMachineSetToMAPI"
+	reasonFailedToConvertMAPIMachineSetToCAPI   = "FailedToConvertMAPIMachineSetToCAPI"
+	reasonFailedToUpdateMAPIMachineSet          = "FailedToUpdateMAPIMachineSet"
+	reasonFailedToApplyCAPIMachineSet           = "FailedToApplyCAPIMachineSet"
+	reasonFailedToApplyCAPIInfraMachineTemplate = "FailedToApplyCAPIInfraMachineTemplate"
+	reasonFailedToGetCAPIMachineSet             = "FailedToGetCAPIMachineSet"
+	reasonResourceSynchronized                  = "ResourceSynchronized"
 
 	messageSuccessfullySynchronized = "Successfully synchronized CAPI MachineSet to MAPI"
+
+	// capiFieldManager is the field manager used to apply the CAPI resources converted from MAPI.
+	capiFieldManager = "machineset-sync-controller-capi"
+
+	// machineSetKind is the kind reported in the sync metrics.
+	machineSetKind = "MachineSet"
 )
 
 // MachineSetSyncReconciler reconciles CAPI and MAPI MachineSets.
@@ -87,15 +79,24 @@ type MachineSetSyncReconciler struct {
 	Platform      configv1.PlatformType
 	CAPINamespace string
 	MAPINamespace string
+
+	// ForwardReplicas enables forwarding changes of spec.replicas made on the non-authoritative
+	// machine set to the authoritative one, instead of overwriting them on the next synchronization.
+	ForwardReplicas bool
+
+	// conversion holds the infrastructure types and converters registered for the Platform.
+	conversion conversion.Platform
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *MachineSetSyncReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	infraMachineTemplate, err := getInfraMachineTemplateFromProvider(r.Platform)
+	platformConversion, err := conversion.PlatformFor(r.Platform)
 	if err != nil {
-		return fmt.Errorf("failed to get infrastructure machine template from Provider: %w", err)
+		return fmt.Errorf("failed to get conversion for platform: %w", err)
 	}
 
+	r.conversion = platformConversion
+
 	// Allow the namespaces to be set externally for test purposes, when not set,
 	// default to the production namespaces.
 	if r.CAPINamespace == "" {
@@ -114,7 +115,12 @@ func (r *MachineSetSyncReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			builder.WithPredicates(util.FilterNamespace(r.CAPINamespace)),
 		).
 		Watches(
-			infraMachineTemplate,
+			&capiv1beta1.MachineDeployment{},
+			handler.EnqueueRequestsFromMapFunc(util.RewriteNamespace(r.MAPINamespace)),
+			builder.WithPredicates(util.FilterNamespace(r.CAPINamespace)),
+		).
+		Watches(
+			r.conversion.NewInfraMachineTemplate(),
 			handler.EnqueueRequestsFromMapFunc(util.ResolveCAPIMachineSetFromObject(r.MAPINamespace)),
 			builder.WithPredicates(util.FilterNamespace(r.CAPINamespace)),
 		).
@@ -143,6 +149,10 @@ func (r *MachineSetSyncReconciler) Reconcile(ctx context.Context, req reconcile.
 		return ctrl.Result{}, fmt.Errorf("failed to fetch machine sets: %w", err)
 	}
 
+	if mapiMachineSet == nil {
+		syncmetrics.Forget(machineSetKind, req.Name)
+	}
+
 	if mapiMachineSet == nil && capiMachineSet == nil {
 		logger.Info("Both MAPI and CAPI machine sets not found, nothing to do")
 		return ctrl.Result{}, nil
@@ -153,6 +163,10 @@ func (r *MachineSetSyncReconciler) Reconcile(ctx context.Context, req reconcile.
 		return ctrl.Result{}, nil
 	}
 
+	if conversionutil.IsMachineDeploymentEnabled(mapiMachineSet.Annotations) {
+		return r.syncMachineDeployments(ctx, mapiMachineSet)
+	}
+
 	return r.syncMachineSets(ctx, mapiMachineSet, capiMachineSet)
 }
 
@@ -184,27 +198,20 @@ func (r *MachineSetSyncReconciler) fetchMachineSets(ctx context.Context, name st
 }
 
 // fetchCAPIInfraResources fetches the provider specific infrastructure resources depending on which provider is set.
-func (r *MachineSetSyncReconciler) fetchCAPIInfraResources(ctx context.Context, capiMachineSet *capiv1beta1.MachineSet) (client.Object, client.Object, error) {
-	var infraCluster, infraMachineTemplate client.Object
-
+// The cluster name and infrastructure reference are taken from either a CAPI MachineSet or MachineDeployment.
+func (r *MachineSetSyncReconciler) fetchCAPIInfraResources(ctx context.Context, namespace, clusterName string, infraMachineTemplateRef corev1.ObjectReference) (client.Object, client.Object, error) {
 	infraClusterKey := client.ObjectKey{
-		Namespace: capiMachineSet.Namespace,
-		Name:      capiMachineSet.Spec.ClusterName,
+		Namespace: namespace,
+		Name:      clusterName,
 	}
 
-	infraMachineTemplateRef := capiMachineSet.Spec.Template.Spec.InfrastructureRef
 	infraMachineTemplateKey := client.ObjectKey{
 		Namespace: infraMachineTemplateRef.Namespace,
 		Name:      infraMachineTemplateRef.Name,
 	}
 
-	switch r.Platform {
-	case configv1.AWSPlatformType:
-		infraCluster = &awscapiv1beta1.AWSCluster{}
-		infraMachineTemplate = &awscapiv1beta1.AWSMachineTemplate{}
-	default:
-		return nil, nil, fmt.Errorf("%w: %s", errPlatformNotSupported, r.Platform)
-	}
+	infraCluster := r.conversion.NewInfraCluster()
+	infraMachineTemplate := r.conversion.NewInfraMachineTemplate()
 
 	if err := r.Get(ctx, infraClusterKey, infraCluster); err != nil {
 		return nil, nil, fmt.Errorf("failed to get CAPI infrastructure cluster: %w", err)
@@ -221,13 +228,19 @@ func (r *MachineSetSyncReconciler) fetchCAPIInfraResources(ctx context.Context,
 func (r *MachineSetSyncReconciler) syncMachineSets(ctx context.Context, mapiMachineSet *machinev1beta1.MachineSet, capiMachineSet *capiv1beta1.MachineSet) (ctrl.Result, error) {
 	logger := log.FromContext(ctx)
 
+	if capiMachineSet != nil {
+		if err := r.forwardReplicas(ctx, mapiMachineSet, capiMachineSet, capiMachineSet.Spec.Replicas); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	authoritativeAPI := mapiMachineSet.Status.AuthoritativeAPI
 
 	switch {
 	case authoritativeAPI == machinev1beta1.MachineAuthorityMachineAPI:
-		return r.reconcileMAPIMachineSetToCAPIMachineSet(ctx, mapiMachineSet, capiMachineSet)
+		return r.reconcileMAPIMachineSetToCAPIMachineSet(ctx, mapiMachineSet)
 	case authoritativeAPI == machinev1beta1.MachineAuthorityClusterAPI && capiMachineSet == nil:
-		return r.reconcileMAPIMachineSetToCAPIMachineSet(ctx, mapiMachineSet, capiMachineSet)
+		return r.reconcileMAPIMachineSetToCAPIMachineSet(ctx, mapiMachineSet)
 	case authoritativeAPI == machinev1beta1.MachineAuthorityClusterAPI && capiMachineSet != nil:
 		return r.reconcileCAPIMachineSetToMAPIMachineSet(ctx, capiMachineSet, mapiMachineSet)
 	case authoritativeAPI == machinev1beta1.MachineAuthorityMigrating:
@@ -242,10 +255,14 @@ func (r *MachineSetSyncReconciler) syncMachineSets(ctx context.Context, mapiMach
 }
 
 // reconcileMAPIMachineSetToCAPIMachineSet reconciles a MAPI MachineSet to a CAPI MachineSet.
-func (r *MachineSetSyncReconciler) reconcileMAPIMachineSetToCAPIMachineSet(ctx context.Context, mapiMachineSet *machinev1beta1.MachineSet, capiMachineSet *capiv1beta1.MachineSet) (ctrl.Result, error) {
-	logger := log.FromContext(ctx)
+func (r *MachineSetSyncReconciler) reconcileMAPIMachineSetToCAPIMachineSet(ctx context.Context, mapiMachineSet *machinev1beta1.MachineSet) (ctrl.Result, error) {
+	syncmetrics.ObserveGeneration(machineSetKind, mapiMachineSet.Name, mapiMachineSet.Generation, mapiMachineSet.Status.SynchronizedGeneration)
+
+	newCAPIMachineSet, newCAPIInfraMachineTemplate, conversionReport, err := r.convertMAPIToCAPIMachineSet(mapiMachineSet)
+	if reportErr := r.reportConversion(ctx, mapiMachineSet, report.MAPIToCAPI, conversionReport, err); reportErr != nil {
+		return ctrl.Result{}, utilerrors.NewAggregate([]error{err, reportErr})
+	}
 
-	newCAPIMachineSet, newCAPIInfraMachineTemplate, warns, err := r.convertMAPIToCAPIMachineSet(mapiMachineSet)
 	if err != nil {
 		conversionErr := fmt.Errorf("failed to convert MAPI machine set to CAPI machine set: %w", err)
 		if condErr := r.updateSynchronizedConditionWithPatch(ctx, mapiMachineSet, corev1.ConditionFalse, reasonFailedToConvertMAPIMachineSetToCAPI, conversionErr.Error(), nil); condErr != nil {
@@ -255,36 +272,29 @@ func (r *MachineSetSyncReconciler) reconcileMAPIMachineSetToCAPIMachineSet(ctx c
 		return ctrl.Result{}, conversionErr
 	}
 
-	for _, warning := range warns {
-		logger.Info("Warning during conversion", "warning", warning)
-		r.Recorder.Event(mapiMachineSet, corev1.EventTypeWarning, "ConversionWarning", warning)
-	}
-
-	newCAPIMachineSet.SetResourceVersion(getResourceVersion(client.Object(capiMachineSet)))
 	newCAPIMachineSet.SetNamespace(r.CAPINamespace)
 	newCAPIMachineSet.Spec.Template.Spec.InfrastructureRef.Namespace = r.CAPINamespace
+	newCAPIInfraMachineTemplate.SetNamespace(r.CAPINamespace)
 
-	_, infraMachineTemplate, err := r.fetchCAPIInfraResources(ctx, newCAPIMachineSet)
-	if err != nil && !apierrors.IsNotFound(err) {
-		fetchErr := fmt.Errorf("failed to fetch CAPI infra resources: %w", err)
-
-		if condErr := r.updateSynchronizedConditionWithPatch(
-			ctx, mapiMachineSet, corev1.ConditionFalse, reasonFailedToGetCAPIInfraResources, fetchErr.Error(), nil); condErr != nil {
-			return ctrl.Result{}, utilerrors.NewAggregate([]error{fetchErr, condErr})
-		}
+	// The CAPI resources are paused before they are applied, so that the CAPI controllers
+	// never act on them while MAPI is authoritative.
+	setPausedAnnotations(newCAPIMachineSet, mapiMachineSet.Status.AuthoritativeAPI, true)
+	setPausedAnnotations(newCAPIInfraMachineTemplate, mapiMachineSet.Status.AuthoritativeAPI, true)
 
-		return ctrl.Result{}, fetchErr
+	if err := r.applyCAPIInfraMachineTemplate(ctx, mapiMachineSet, newCAPIInfraMachineTemplate); err != nil {
+		return ctrl.Result{}, fmt.Errorf("unable to ensure CAPI infra machine template: %w", err)
 	}
 
-	newCAPIInfraMachineTemplate.SetResourceVersion(getResourceVersion(infraMachineTemplate))
-	newCAPIInfraMachineTemplate.SetNamespace(r.CAPINamespace)
+	if err := r.applyCAPIResource(ctx, mapiMachineSet, newCAPIMachineSet, reasonFailedToApplyCAPIMachineSet); err != nil {
+		return ctrl.Result{}, fmt.Errorf("unable to ensure CAPI machine set: %w", err)
+	}
 
-	if result, err := r.createOrUpdateCAPIInfraMachineTemplate(ctx, mapiMachineSet, infraMachineTemplate, newCAPIInfraMachineTemplate); err != nil {
-		return result, fmt.Errorf("unable to ensure CAPI infra machine template: %w", err)
+	if err := r.recordSynchronizedGeneration(ctx, newCAPIMachineSet); err != nil {
+		return ctrl.Result{}, err
 	}
 
-	if result, err := r.createOrUpdateCAPIMachineSet(ctx, mapiMachineSet, capiMachineSet, newCAPIMachineSet); err != nil {
-		return result, fmt.Errorf("unable to ensure CAPI machine set: %w", err)
+	if err := r.syncPaused(ctx, mapiMachineSet, newCAPIMachineSet, newCAPIInfraMachineTemplate); err != nil {
+		return ctrl.Result{}, fmt.Errorf("unable to ensure paused state of machine sets: %w", err)
 	}
 
 	return ctrl.Result{}, r.updateSynchronizedConditionWithPatch(ctx, mapiMachineSet, corev1.ConditionTrue,
@@ -294,9 +304,9 @@ func (r *MachineSetSyncReconciler) reconcileMAPIMachineSetToCAPIMachineSet(ctx c
 // reconcileCAPIMachineSetToMAPIMachineSet reconciles a CAPI MachineSet to a
 // MAPI MachineSet.
 func (r *MachineSetSyncReconciler) reconcileCAPIMachineSetToMAPIMachineSet(ctx context.Context, capiMachineSet *capiv1beta1.MachineSet, mapiMachineSet *machinev1beta1.MachineSet) (ctrl.Result, error) {
-	logger := log.FromContext(ctx)
+	syncmetrics.ObserveGeneration(machineSetKind, mapiMachineSet.Name, capiMachineSet.Generation, mapiMachineSet.Status.SynchronizedGeneration)
 
-	infraCluster, infraMachineTemplate, err := r.fetchCAPIInfraResources(ctx, capiMachineSet)
+	infraCluster, infraMachineTemplate, err := r.fetchCAPIInfraResources(ctx, capiMachineSet.Namespace, capiMachineSet.Spec.ClusterName, capiMachineSet.Spec.Template.Spec.InfrastructureRef)
 	if err != nil {
 		fetchErr := fmt.Errorf("failed to fetch CAPI infra resources: %w", err)
 
@@ -308,7 +318,11 @@ func (r *MachineSetSyncReconciler) reconcileCAPIMachineSetToMAPIMachineSet(ctx c
 		return ctrl.Result{}, fetchErr
 	}
 
-	newMapiMachineSet, warns, err := r.convertCAPIToMAPIMachineSet(capiMachineSet, infraMachineTemplate, infraCluster)
+	newMapiMachineSet, conversionReport, err := r.convertCAPIToMAPIMachineSet(capiMachineSet, infraMachineTemplate, infraCluster)
+	if reportErr := r.reportConversion(ctx, mapiMachineSet, report.CAPIToMAPI, conversionReport, err); reportErr != nil {
+		return ctrl.Result{}, utilerrors.NewAggregate([]error{err, reportErr})
+	}
+
 	if err != nil {
 		conversionErr := fmt.Errorf("failed to convert CAPI machine set to MAPI machine set: %w", err)
 
@@ -320,11 +334,24 @@ func (r *MachineSetSyncReconciler) reconcileCAPIMachineSetToMAPIMachineSet(ctx c
 		return ctrl.Result{}, conversionErr
 	}
 
-	for _, warning := range warns {
-		logger.Info("Warning during conversion", "warning", warning)
-		r.Recorder.Event(mapiMachineSet, corev1.EventTypeWarning, "ConversionWarning", warning)
+	setPausedAnnotations(newMapiMachineSet, mapiMachineSet.Status.AuthoritativeAPI, false)
+
+	if err := r.updateMAPIMachineSet(ctx, mapiMachineSet, newMapiMachineSet); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if err := r.syncPaused(ctx, mapiMachineSet, capiMachineSet, infraMachineTemplate); err != nil {
+		return ctrl.Result{}, fmt.Errorf("unable to ensure paused state of machine sets: %w", err)
 	}
 
+	return ctrl.Result{}, r.updateSynchronizedConditionWithPatch(ctx, mapiMachineSet, corev1.ConditionTrue,
+		consts.ReasonResourceSynchronized, messageSuccessfullySynchronized, &capiMachineSet.Generation)
+}
+
+// updateMAPIMachineSet updates the MAPI MachineSet with the spec and metadata converted from CAPI, if it is out of date.
+func (r *MachineSetSyncReconciler) updateMAPIMachineSet(ctx context.Context, mapiMachineSet, newMapiMachineSet *machinev1beta1.MachineSet) error {
+	logger := log.FromContext(ctx)
+
 	newMapiMachineSet.Spec.Template.Labels = util.MergeMaps(mapiMachineSet.Spec.Template.Labels, newMapiMachineSet.Spec.Template.Labels)
 
 	newMapiMachineSet.SetNamespace(mapiMachineSet.GetNamespace())
@@ -341,10 +368,10 @@ func (r *MachineSetSyncReconciler) reconcileCAPIMachineSetToMAPIMachineSet(ctx c
 
 			if condErr := r.updateSynchronizedConditionWithPatch(
 				ctx, mapiMachineSet, corev1.ConditionFalse, reasonFailedToUpdateMAPIMachineSet, updateErr.Error(), nil); condErr != nil {
-				return ctrl.Result{}, utilerrors.NewAggregate([]error{updateErr, condErr})
+				return utilerrors.NewAggregate([]error{updateErr, condErr})
 			}
 
-			return ctrl.Result{}, updateErr
+			return updateErr
 		}
 
 		logger.Info("Successfully updated MAPI machine set")
@@ -352,41 +379,22 @@ func (r *MachineSetSyncReconciler) reconcileCAPIMachineSetToMAPIMachineSet(ctx c
 		logger.Info("No changes detected in MAPI machine set")
 	}
 
-	return ctrl.Result{}, r.updateSynchronizedConditionWithPatch(ctx, mapiMachineSet, corev1.ConditionTrue,
-		consts.ReasonResourceSynchronized, messageSuccessfullySynchronized, &capiMachineSet.Generation)
+	return nil
 }
 
-// convertCAPIToMAPIMachineSet converts a CAPI MachineSet to a MAPI MachineSet, selecting the correct converter based on the platform.
-func (r *MachineSetSyncReconciler) convertCAPIToMAPIMachineSet(capiMachineSet *capiv1beta1.MachineSet, infraMachineTemplate client.Object, infraCluster client.Object) (*machinev1beta1.MachineSet, []string, error) {
-	switch r.Platform {
-	case configv1.AWSPlatformType:
-		awsMachineTemplate, ok := infraMachineTemplate.(*awscapiv1beta1.AWSMachineTemplate)
-		if !ok {
-			return nil, nil, fmt.Errorf("%w, expected AWSMachineTemplate, got %T", errUnexpectedInfraMachineTemplateType, infraMachineTemplate)
-		}
-
-		awsCluster, ok := infraCluster.(*awscapiv1beta1.AWSCluster)
-		if !ok {
-			return nil, nil, fmt.Errorf("%w, expected AWSCluster, got %T", errUnexpectedInfraClusterType, infraCluster)
-		}
-
-		return capi2mapi.FromMachineSetAndAWSMachineTemplateAndAWSCluster( //nolint: wrapcheck
-			capiMachineSet, awsMachineTemplate, awsCluster,
-		).ToMachineSet()
-
-	default:
-		return nil, nil, fmt.Errorf("%w: %s", errPlatformNotSupported, r.Platform)
+// convertCAPIToMAPIMachineSet converts a CAPI MachineSet to a MAPI MachineSet using the converter registered for the platform.
+func (r *MachineSetSyncReconciler) convertCAPIToMAPIMachineSet(capiMachineSet *capiv1beta1.MachineSet, infraMachineTemplate client.Object, infraCluster client.Object) (*machinev1beta1.MachineSet, report.Report, error) {
+	converter, err := r.conversion.FromCAPIMachineSet(capiMachineSet, infraMachineTemplate, infraCluster)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create CAPI machine set converter: %w", err)
 	}
+
+	return converter.ToMachineSet() //nolint:wrapcheck
 }
 
-// convertMAPIToCAPIMachineSet converts a MAPI MachineSet to a CAPI MachineSet, selecting the correct converter based on the platform.
-func (r *MachineSetSyncReconciler) convertMAPIToCAPIMachineSet(mapiMachineSet *machinev1beta1.MachineSet) (*capiv1beta1.MachineSet, client.Object, []string, error) {
-	switch r.Platform {
-	case configv1.AWSPlatformType:
-		return mapi2capi.FromAWSMachineSetAndInfra(mapiMachineSet, r.Infra).ToMachineSetAndMachineTemplate() //nolint:wrapcheck
-	default:
-		return nil, nil, nil, fmt.Errorf("%w: %s", errPlatformNotSupported, r.Platform)
-	}
+// convertMAPIToCAPIMachineSet converts a MAPI MachineSet to a CAPI MachineSet using the converter registered for the platform.
+func (r *MachineSetSyncReconciler) convertMAPIToCAPIMachineSet(mapiMachineSet *machinev1beta1.MachineSet) (*capiv1beta1.MachineSet, client.Object, report.Report, error) {
+	return r.conversion.FromMAPIMachineSet(mapiMachineSet, r.Infra).ToMachineSetAndMachineTemplate() //nolint:wrapcheck
 }
 
 // updateSynchronizedConditionWithPatch updates the synchronized condition
@@ -407,7 +415,7 @@ func (r *MachineSetSyncReconciler) updateSynchronizedConditionWithPatch(ctx cont
 		WithMessage(message).
 		WithSeverity(severity)
 
-	setLastTransitionTime(consts.SynchronizedCondition, mapiMachineSet.Status.Conditions, conditionAc)
+	util.SetLastTransitionTime(consts.SynchronizedCondition, mapiMachineSet.Status.Conditions, conditionAc)
 
 	statusAc := machinev1applyconfigs.MachineSetStatus().
 		WithConditions(conditionAc)
@@ -423,150 +431,127 @@ func (r *MachineSetSyncReconciler) updateSynchronizedConditionWithPatch(ctx cont
 		return fmt.Errorf("failed to patch MAPI machine set status with synchronized condition: %w", err)
 	}
 
+	if status == corev1.ConditionTrue && generation != nil {
+		syncmetrics.ObserveSynchronized(machineSetKind, mapiMachineSet.GetName())
+	}
+
 	return nil
 }
 
-// createOrUpdateCAPIInfraMachineTemplate creates a CAPI infra machine template from a MAPI machine set, or updates if it exists and it is out of date.
-func (r *MachineSetSyncReconciler) createOrUpdateCAPIInfraMachineTemplate(ctx context.Context, mapiMachineSet *machinev1beta1.MachineSet, infraMachineTemplate client.Object, newCAPIInfraMachineTemplate client.Object) (ctrl.Result, error) { //nolint:unparam
+// applyCAPIResource creates or updates a CAPI resource converted from a MAPI machine set using a server side apply patch.
+// The controller only owns the fields produced by the conversion, so fields set by other controllers,
+// such as the CAPI MachineSet controller or the autoscaler, are left untouched.
+func (r *MachineSetSyncReconciler) applyCAPIResource(ctx context.Context, mapiMachineSet *machinev1beta1.MachineSet, obj client.Object, reason string) error {
 	logger := log.FromContext(ctx)
 
-	if infraMachineTemplate == nil {
-		if err := r.Create(ctx, newCAPIInfraMachineTemplate); err != nil {
-			logger.Error(err, "Failed to create CAPI infra machine template")
-			createErr := fmt.Errorf("failed to create CAPI infra machine template: %w", err)
-
-			if condErr := r.updateSynchronizedConditionWithPatch(ctx, mapiMachineSet, corev1.ConditionFalse, reasonFailedToCreateCAPIInfraMachineTemplate, createErr.Error(), nil); condErr != nil {
-				return ctrl.Result{}, utilerrors.NewAggregate([]error{createErr, condErr})
-			}
-
-			return ctrl.Result{}, createErr
-		}
-
-		logger.Info("Successfully created CAPI infra machine template")
-
-		return ctrl.Result{}, nil
-	}
-
-	isEqualCAPIInfraMachineTemplate, err := capiInfraMachineTemplateIsEqual(r.Platform, infraMachineTemplate, newCAPIInfraMachineTemplate)
+	gvk, err := apiutil.GVKForObject(obj, r.Client.Scheme())
 	if err != nil {
-		logger.Error(err, "Failed to check CAPI infra machine template diff")
-		updateErr := fmt.Errorf("failed to check CAPI infra machine template diff: %w", err)
-
-		if condErr := r.updateSynchronizedConditionWithPatch(
-			ctx, mapiMachineSet, corev1.ConditionFalse, reasonFailedToUpdateCAPIInfraMachineTemplate, updateErr.Error(), nil); condErr != nil {
-			return ctrl.Result{}, utilerrors.NewAggregate([]error{updateErr, condErr})
-		}
-
-		return ctrl.Result{}, updateErr
+		return fmt.Errorf("failed to get GroupVersionKind for %T: %w", obj, err)
 	}
 
-	if isEqualCAPIInfraMachineTemplate {
-		logger.Info("No changes detected in CAPI infra machine template")
-		return ctrl.Result{}, nil
-	}
+	logger = logger.WithValues("kind", gvk.Kind, "name", obj.GetName())
 
-	logger.Info("Updating CAPI infra machine template")
+	applyObj, err := toApplyObject(obj, gvk)
+	if err != nil {
+		return err
+	}
 
-	if err := r.Update(ctx, newCAPIInfraMachineTemplate); err != nil {
-		logger.Error(err, "Failed to update CAPI infra machine template")
+	if err := r.Patch(ctx, applyObj, client.Apply, client.ForceOwnership, client.FieldOwner(capiFieldManager)); err != nil {
+		logger.Error(err, "Failed to apply CAPI resource")
 
-		updateErr := fmt.Errorf("failed to update CAPI infra machine template: %w", err)
+		applyErr := fmt.Errorf("failed to apply CAPI %s: %w", gvk.Kind, err)
 
-		if condErr := r.updateSynchronizedConditionWithPatch(
-			ctx, mapiMachineSet, corev1.ConditionFalse, reasonFailedToUpdateCAPIInfraMachineTemplate, updateErr.Error(), nil); condErr != nil {
-			return ctrl.Result{}, utilerrors.NewAggregate([]error{updateErr, condErr})
+		if condErr := r.updateSynchronizedConditionWithPatch(ctx, mapiMachineSet, corev1.ConditionFalse, reason, applyErr.Error(), nil); condErr != nil {
+			return utilerrors.NewAggregate([]error{applyErr, condErr})
 		}
 
-		return ctrl.Result{}, updateErr
+		return applyErr
+	}
+
+	// Reflect the applied state of the resource in the object, so that it can be patched further.
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(applyObj.Object, obj); err != nil {
+		return fmt.Errorf("failed to convert applied CAPI %s: %w", gvk.Kind, err)
 	}
 
-	logger.Info("Successfully updated CAPI infra machine template")
+	logger.Info("Successfully applied CAPI resource")
 
-	return ctrl.Result{}, nil
+	return nil
 }
 
-// createOrUpdateCAPIMachineSet creates a CAPI machine set from a MAPI one, or updates if it exists and it is out of date.
-func (r *MachineSetSyncReconciler) createOrUpdateCAPIMachineSet(ctx context.Context, mapiMachineSet *machinev1beta1.MachineSet, capiMachineSet *capiv1beta1.MachineSet, newCAPIMachineSet *capiv1beta1.MachineSet) (ctrl.Result, error) { //nolint:unparam
+// applyCAPIInfraMachineTemplate applies a CAPI infra machine template converted from a MAPI machine set,
+// unless the existing CAPI infra machine template is already equal to it.
+func (r *MachineSetSyncReconciler) applyCAPIInfraMachineTemplate(ctx context.Context, mapiMachineSet *machinev1beta1.MachineSet, infraMachineTemplate client.Object) error {
 	logger := log.FromContext(ctx)
 
-	if capiMachineSet == nil {
-		if err := r.Create(ctx, newCAPIMachineSet); err != nil {
-			logger.Error(err, "Failed to create CAPI machine set")
+	existingInfraMachineTemplate := r.conversion.NewInfraMachineTemplate()
 
-			createErr := fmt.Errorf("failed to create CAPI machine set: %w", err)
-			if condErr := r.updateSynchronizedConditionWithPatch(
-				ctx, mapiMachineSet, corev1.ConditionFalse, reasonFailedToCreateCAPIMachineSet, createErr.Error(), nil); condErr != nil {
-				return ctrl.Result{}, utilerrors.NewAggregate([]error{createErr, condErr})
-			}
-
-			return ctrl.Result{}, createErr
-		}
-
-		logger.Info("Successfully created CAPI machine set")
-
-		return ctrl.Result{}, nil
-	}
-
-	if reflect.DeepEqual(newCAPIMachineSet.Spec, capiMachineSet.Spec) && objectMetaIsEqual(newCAPIMachineSet.ObjectMeta, capiMachineSet.ObjectMeta) {
-		logger.Info("No changes detected in CAPI machine set")
-		return ctrl.Result{}, nil
+	err := r.Get(ctx, client.ObjectKeyFromObject(infraMachineTemplate), existingInfraMachineTemplate)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to get CAPI infra machine template: %w", err)
 	}
 
-	logger.Info("Updating CAPI machine set")
+	if err == nil {
+		isEqual, err := r.capiInfraMachineTemplateIsEqual(existingInfraMachineTemplate, infraMachineTemplate)
+		if err != nil {
+			return err
+		}
 
-	if err := r.Update(ctx, newCAPIMachineSet); err != nil {
-		logger.Error(err, "Failed to update CAPI machine set")
+		if isEqual {
+			logger.Info("No changes detected in CAPI infra machine template")
 
-		updateErr := fmt.Errorf("failed to update CAPI machine set: %w", err)
+			// Reflect the existing state of the template in the object, so that it can be patched further.
+			if err := r.Get(ctx, client.ObjectKeyFromObject(infraMachineTemplate), infraMachineTemplate); err != nil {
+				return fmt.Errorf("failed to get CAPI infra machine template: %w", err)
+			}
 
-		if condErr := r.updateSynchronizedConditionWithPatch(ctx, mapiMachineSet, corev1.ConditionFalse, reasonFailedToUpdateCAPIMachineSet, updateErr.Error(), nil); condErr != nil {
-			return ctrl.Result{}, utilerrors.NewAggregate([]error{updateErr, condErr})
+			return nil
 		}
-
-		return ctrl.Result{}, updateErr
 	}
 
-	logger.Info("Successfully updated CAPI machine set")
-
-	return ctrl.Result{}, nil
+	return r.applyCAPIResource(ctx, mapiMachineSet, infraMachineTemplate, reasonFailedToApplyCAPIInfraMachineTemplate)
 }
 
-// getInfraMachineTemplateFromProvider returns the correct InfraMachineTemplate implementation
-// for a given provider.
-func getInfraMachineTemplateFromProvider(platform configv1.PlatformType) (client.Object, error) {
-	switch platform {
-	case configv1.AWSPlatformType:
-		return &awscapiv1beta1.AWSMachineTemplate{}, nil
-	default:
-		return nil, fmt.Errorf("%w: %s", errPlatformNotSupported, platform)
+// capiInfraMachineTemplateIsEqual checks whether the existing CAPI infra machine template already holds the spec and
+// the metadata of the converted one. Metadata set by other controllers, such as their own annotations, owner
+// references and finalizers, is ignored as it is not managed by the apply.
+func (r *MachineSetSyncReconciler) capiInfraMachineTemplateIsEqual(existing, converted client.Object) (bool, error) {
+	specIsEqual, err := r.conversion.InfraMachineTemplateIsEqual(existing, converted)
+	if err != nil {
+		return false, fmt.Errorf("failed to compare CAPI infra machine templates: %w", err)
 	}
+
+	return specIsEqual && convertedMetadataIsSet(existing, converted), nil
 }
 
-// setLastTransitionTime determines if the last transition time should be set or updated for a given condition type.
-func setLastTransitionTime(condType machinev1beta1.ConditionType, conditions []machinev1beta1.Condition, conditionAc *machinev1applyconfigs.ConditionApplyConfiguration) {
-	for _, condition := range conditions {
-		if condition.Type == condType {
-			if !hasSameState(&condition, conditionAc) {
-				conditionAc.WithLastTransitionTime(metav1.Now())
+// convertedMetadataIsSet determines whether the labels, annotations, owner references and finalizers
+// of the converted object are all set on the existing object.
+func convertedMetadataIsSet(existing, converted client.Object) bool {
+	return mapContains(existing.GetLabels(), converted.GetLabels()) &&
+		mapContains(existing.GetAnnotations(), converted.GetAnnotations()) &&
+		sliceContains(existing.GetOwnerReferences(), converted.GetOwnerReferences()) &&
+		sliceContains(existing.GetFinalizers(), converted.GetFinalizers())
+}
 
-				return
-			}
+// mapContains determines whether every key of subset is set to the same value in m.
+func mapContains(m, subset map[string]string) bool {
+	for k, v := range subset {
+		if value, ok := m[k]; !ok || value != v {
+			return false
+		}
+	}
 
-			conditionAc.WithLastTransitionTime(condition.LastTransitionTime)
+	return true
+}
 
-			return
+// sliceContains determines whether every item of subset is present in s.
+func sliceContains[T any](s, subset []T) bool {
+	for _, item := range subset {
+		if !slices.ContainsFunc(s, func(i T) bool { return reflect.DeepEqual(i, item) }) {
+			return false
 		}
 	}
-	// Condition does not exist; set the transition time
-	conditionAc.WithLastTransitionTime(metav1.Now())
-}
 
-// hasSameState returns true if a condition has the same state as a condition
-// apply config; state is defined by the union of following fields: Type,
-// Status.
-func hasSameState(i *machinev1beta1.Condition, j *machinev1applyconfigs.ConditionApplyConfiguration) bool {
-	return i.Type == *j.Type &&
-		i.Status == *j.Status
+	return true
 }
 
 // objectMetaIsEqual determines if the two ObjectMeta are equal for the fields we care about
@@ -578,26 +563,6 @@ func objectMetaIsEqual(a, b metav1.ObjectMeta) bool {
 		reflect.DeepEqual(a.OwnerReferences, b.OwnerReferences)
 }
 
-// capiInfraMachineTemplateIsEqual checks whether the provided CAPI infra machine templates are equal.
-func capiInfraMachineTemplateIsEqual(platform configv1.PlatformType, infraMachineTemplate1, infraMachineTemplate2 client.Object) (bool, error) {
-	switch platform {
-	case configv1.AWSPlatformType:
-		typedInfraMachineTemplate1, ok := infraMachineTemplate1.(*awscapiv1beta1.AWSMachineTemplate)
-		if !ok {
-			return false, errAssertingCAPIAWSMachineTemplate
-		}
-
-		typedinfraMachineTemplate2, ok := infraMachineTemplate2.(*awscapiv1beta1.AWSMachineTemplate)
-		if !ok {
-			return false, errAssertingCAPIAWSMachineTemplate
-		}
-
-		return reflect.DeepEqual(typedInfraMachineTemplate1.Spec, typedinfraMachineTemplate2.Spec) && objectMetaIsEqual(typedInfraMachineTemplate1.ObjectMeta, typedinfraMachineTemplate2.ObjectMeta), nil
-	default:
-		return false, fmt.Errorf("%w: %s", errPlatformNotSupported, platform)
-	}
-}
-
 // getResourceVersion returns the object ResourceVersion or the zero value for it.
 func getResourceVersion(obj client.Object) string {
 	if obj == nil || reflect.ValueOf(obj).IsNil() {
@@ -31,6 +31,7 @@ import (
 	machinev1resourcebuilder "github.com/openshift/cluster-api-actuator-pkg/testutils/resourcebuilder/machine/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	capav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
 
 	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -152,6 +153,8 @@ var _ = Describe("With a running MachineSetSync controller", func() {
 			Platform:      configv1.AWSPlatformType,
 			CAPINamespace: capiNamespace.GetName(),
 			MAPINamespace: mapiNamespace.GetName(),
+
+			ForwardReplicas: true,
 		}
 		Expect(reconciler.SetupWithManager(mgr)).To(Succeed(),
 			"Reconciler should be able to setup with manager")
@@ -177,6 +180,7 @@ var _ = Describe("With a running MachineSetSync controller", func() {
 		testutils.CleanupResources(Default, ctx, cfg, k8sClient, capiNamespace.GetName(),
 			&capiv1beta1.Machine{},
 			&capiv1beta1.MachineSet{},
+			&capiv1beta1.MachineDeployment{},
 			&capav1.AWSCluster{},
 			&capav1.AWSMachineTemplate{},
 		)
@@ -218,6 +222,48 @@ var _ = Describe("With a running MachineSetSync controller", func() {
 							))),
 					)
 				})
+
+				It("should update the lossless conversion condition on the MAPI machine set to True", func() {
+					Eventually(k.Object(mapiMachineSet), timeout).Should(
+						HaveField("Status.Conditions", ContainElement(
+							SatisfyAll(
+								HaveField("Type", Equal(consts.LosslessConversionCondition)),
+								HaveField("Status", Equal(corev1.ConditionTrue)),
+								HaveField("Reason", Equal(consts.ReasonConversionLossless)),
+							))),
+					)
+				})
+
+				It("should apply the CAPI machine set with the sync controller field manager", func() {
+					capiMachineSet = capiv1resourcebuilder.MachineSet().WithName(mapiMachineSet.Name).WithNamespace(capiNamespace.Name).Build()
+
+					Eventually(k.Object(capiMachineSet), timeout).Should(
+						HaveField("ManagedFields", ContainElement(
+							SatisfyAll(
+								HaveField("Manager", Equal("machineset-sync-controller-capi")),
+								HaveField("Operation", Equal(metav1.ManagedFieldsOperationApply)),
+							))),
+					)
+				})
+
+				It("should not update the CAPI machine set once it is synchronized", func() {
+					capiMachineSet = capiv1resourcebuilder.MachineSet().WithName(mapiMachineSet.Name).WithNamespace(capiNamespace.Name).Build()
+
+					Eventually(k.Object(mapiMachineSet), timeout).Should(
+						HaveField("Status.Conditions", ContainElement(
+							SatisfyAll(
+								HaveField("Type", Equal(consts.SynchronizedCondition)),
+								HaveField("Status", Equal(corev1.ConditionTrue)),
+							))),
+					)
+
+					Eventually(k.Get(capiMachineSet)).Should(Succeed())
+					resourceVersion := capiMachineSet.GetResourceVersion()
+
+					Consistently(k.Object(capiMachineSet), timeout).Should(
+						HaveField("ResourceVersion", Equal(resourceVersion)),
+					)
+				})
 			})
 
 			Context("when the CAPI machine set does exist", func() {
@@ -237,6 +283,70 @@ var _ = Describe("With a running MachineSetSync controller", func() {
 							))),
 					)
 				})
+
+				It("should forward a change of the replica count on the CAPI machine set to the MAPI machine set", func() {
+					Eventually(k.Object(capiMachineSet), timeout).Should(
+						HaveField("ObjectMeta.Annotations", HaveKey(synchronizedGenerationAnnotation)),
+					)
+
+					By("Scaling the non-authoritative CAPI machine set")
+					Eventually(k.Update(capiMachineSet, func() {
+						capiMachineSet.Spec.Replicas = ptr.To(int32(5))
+					})).Should(Succeed())
+
+					Eventually(k.Object(mapiMachineSet), timeout).Should(
+						HaveField("Spec.Replicas", Equal(ptr.To(int32(5)))),
+					)
+					Consistently(k.Object(capiMachineSet), timeout).Should(
+						HaveField("Spec.Replicas", Equal(ptr.To(int32(5)))),
+					)
+				})
+
+				It("should not undo a change of the replica count on the MAPI machine set", func() {
+					Eventually(k.Object(capiMachineSet), timeout).Should(
+						HaveField("ObjectMeta.Annotations", HaveKey(synchronizedGenerationAnnotation)),
+					)
+
+					By("Scaling the authoritative MAPI machine set")
+					Eventually(k.Update(mapiMachineSet, func() {
+						mapiMachineSet.Spec.Replicas = ptr.To(int32(6))
+					})).Should(Succeed())
+
+					Eventually(k.Object(capiMachineSet), timeout).Should(
+						HaveField("Spec.Replicas", Equal(ptr.To(int32(6)))),
+					)
+					Consistently(k.Object(mapiMachineSet), timeout).Should(
+						HaveField("Spec.Replicas", Equal(ptr.To(int32(6)))),
+					)
+				})
+
+				It("should pause the CAPI machine set", func() {
+					Eventually(k.Object(capiMachineSet), timeout).Should(
+						SatisfyAll(
+							HaveField("ObjectMeta.Annotations", HaveKey(capiv1beta1.PausedAnnotation)),
+							HaveField("Status.Conditions", ContainElement(
+								SatisfyAll(
+									HaveField("Type", Equal(capiv1beta1.ConditionType(consts.PausedCondition))),
+									HaveField("Status", Equal(corev1.ConditionTrue)),
+									HaveField("Reason", Equal(consts.ReasonAuthoritativeAPIMachineAPI)),
+								))),
+						),
+					)
+				})
+
+				It("should not pause the MAPI machine set", func() {
+					Eventually(k.Object(mapiMachineSet), timeout).Should(
+						SatisfyAll(
+							HaveField("ObjectMeta.Annotations", Not(HaveKey(capiv1beta1.PausedAnnotation))),
+							HaveField("Status.Conditions", ContainElement(
+								SatisfyAll(
+									HaveField("Type", Equal(consts.PausedCondition)),
+									HaveField("Status", Equal(corev1.ConditionFalse)),
+									HaveField("Reason", Equal(consts.ReasonAuthoritativeAPIMachineAPI)),
+								))),
+						),
+					)
+				})
 			})
 		})
 
@@ -276,6 +386,52 @@ var _ = Describe("With a running MachineSetSync controller", func() {
 						HaveField("Spec.Replicas", Equal(ptr.To(int32(4)))),
 					)
 				})
+
+				It("should forward a change of the replica count on the MAPI machine set to the CAPI machine set", func() {
+					Eventually(k.Object(mapiMachineSet), timeout).Should(
+						HaveField("Status.SynchronizedGeneration", Equal(capiMachineSet.GetGeneration())),
+					)
+
+					By("Scaling the non-authoritative MAPI machine set")
+					Eventually(k.Update(mapiMachineSet, func() {
+						mapiMachineSet.Spec.Replicas = ptr.To(int32(7))
+					})).Should(Succeed())
+
+					Eventually(k.Object(capiMachineSet), timeout).Should(
+						HaveField("Spec.Replicas", Equal(ptr.To(int32(7)))),
+					)
+					Consistently(k.Object(mapiMachineSet), timeout).Should(
+						HaveField("Spec.Replicas", Equal(ptr.To(int32(7)))),
+					)
+				})
+
+				It("should pause the MAPI machine set", func() {
+					Eventually(k.Object(mapiMachineSet), timeout).Should(
+						SatisfyAll(
+							HaveField("ObjectMeta.Annotations", Not(HaveKey(capiv1beta1.PausedAnnotation))),
+							HaveField("Status.Conditions", ContainElement(
+								SatisfyAll(
+									HaveField("Type", Equal(consts.PausedCondition)),
+									HaveField("Status", Equal(corev1.ConditionTrue)),
+									HaveField("Reason", Equal(consts.ReasonAuthoritativeAPIClusterAPI)),
+								))),
+						),
+					)
+				})
+
+				It("should not pause the CAPI machine set", func() {
+					Eventually(k.Object(capiMachineSet), timeout).Should(
+						SatisfyAll(
+							HaveField("ObjectMeta.Annotations", Not(HaveKey(capiv1beta1.PausedAnnotation))),
+							HaveField("Status.Conditions", ContainElement(
+								SatisfyAll(
+									HaveField("Type", Equal(capiv1beta1.ConditionType(consts.PausedCondition))),
+									HaveField("Status", Equal(corev1.ConditionFalse)),
+									HaveField("Reason", Equal(consts.ReasonAuthoritativeAPIClusterAPI)),
+								))),
+						),
+					)
+				})
 			})
 
 			Context("when the CAPI machine set exists and the object meta differs", func() {
@@ -357,12 +513,42 @@ var _ = Describe("With a running MachineSetSync controller", func() {
 					)
 
 				})
+
+				It("should report the failing fields on the lossless conversion condition of the MAPI machine set", func() {
+					Eventually(k.Object(mapiMachineSet), timeout).Should(
+						HaveField("Status.Conditions", ContainElement(
+							SatisfyAll(
+								HaveField("Type", Equal(consts.LosslessConversionCondition)),
+								HaveField("Status", Equal(corev1.ConditionFalse)),
+								HaveField("Severity", Equal(machinev1beta1.ConditionSeverityError)),
+								HaveField("Reason", Equal(consts.ReasonConversionFailed)),
+								HaveField("Message", ContainSubstring(`"field":"metadata.ownerReferences","direction":"CAPIToMAPI","severity":"Error"`)),
+							))),
+					)
+				})
 			})
 
 			Context("when the CAPI machine set exists and the conversion has warnings", func() {
-				// The AWS conversion library currently does not throw any warnings.
-				// When we have a conversion that does, this test should be filled out.
-				// We could also mock the conversion interface.
+				BeforeEach(func() {
+					By("Creating the CAPI machine set")
+					capiMachineSet = capiMachineSetBuilder.WithAnnotations(map[string]string{
+						"capacity.cluster-autoscaler.kubernetes.io/gpu-type": "amd.com/gpu",
+					}).Build()
+					Expect(k8sClient.Create(ctx, capiMachineSet)).Should(Succeed())
+				})
+
+				It("should report the lossy fields on the lossless conversion condition of the MAPI machine set", func() {
+					Eventually(k.Object(mapiMachineSet), timeout).Should(
+						HaveField("Status.Conditions", ContainElement(
+							SatisfyAll(
+								HaveField("Type", Equal(consts.LosslessConversionCondition)),
+								HaveField("Status", Equal(corev1.ConditionFalse)),
+								HaveField("Severity", Equal(machinev1beta1.ConditionSeverityWarning)),
+								HaveField("Reason", Equal(consts.ReasonConversionLossy)),
+								HaveField("Message", ContainSubstring(`"severity":"Lossy","reason":"ValueIgnored"`)),
+							))),
+					)
+				})
 			})
 
 			Context("when the CAPI machine set does not exist", func() {
@@ -415,6 +601,134 @@ var _ = Describe("With a running MachineSetSync controller", func() {
 			})
 		})
 
+		Context("when the MAPI machine set opts into being a CAPI machine deployment", func() {
+			BeforeEach(func() {
+				By("Creating the MAPI machine set with the machine deployment annotation")
+				mapiMachineSet = mapiMachineSetBuilder.WithAnnotations(map[string]string{
+					"machine.openshift.io/cluster-api-machine-deployment":           "true",
+					"machine.openshift.io/cluster-api-machine-deployment-max-surge": "1",
+				}).Build()
+				Expect(k8sClient.Create(ctx, mapiMachineSet)).Should(Succeed())
+
+				By("Setting the MAPI machine set AuthoritativeAPI to MachineAPI")
+				Eventually(k.UpdateStatus(mapiMachineSet, func() {
+					mapiMachineSet.Status.AuthoritativeAPI = machinev1beta1.MachineAuthorityMachineAPI
+				})).Should(Succeed())
+			})
+
+			It("should create the CAPI machine deployment with a rolling update strategy", func() {
+				capiMachineDeployment := &capiv1beta1.MachineDeployment{
+					ObjectMeta: metav1.ObjectMeta{Name: mapiMachineSet.Name, Namespace: capiNamespace.Name},
+				}
+
+				Eventually(k.Object(capiMachineDeployment), timeout).Should(
+					HaveField("Spec.Strategy.RollingUpdate.MaxSurge", HaveValue(Equal(intstr.FromInt32(1)))),
+				)
+			})
+
+			It("should not create a CAPI machine set", func() {
+				Consistently(k.Get(
+					capiv1resourcebuilder.MachineSet().WithName(mapiMachineSet.Name).WithNamespace(capiNamespace.Name).Build(),
+				), timeout).ShouldNot(Succeed())
+			})
+
+			It("should update the synchronized condition on the MAPI machine set to True", func() {
+				Eventually(k.Object(mapiMachineSet), timeout).Should(
+					HaveField("Status.Conditions", ContainElement(
+						SatisfyAll(
+							HaveField("Type", Equal(consts.SynchronizedCondition)),
+							HaveField("Status", Equal(corev1.ConditionTrue)),
+							HaveField("Reason", Equal("ResourceSynchronized")),
+							HaveField("Message", Equal("Successfully synchronized CAPI MachineDeployment to MAPI")),
+						))),
+				)
+			})
+		})
+
+		Context("when the MAPI machine set is a CAPI machine deployment and has MachineAuthority set to Cluster API", func() {
+			var capiMachineDeployment *capiv1beta1.MachineDeployment
+			var staleCAPAMachineTemplate *capav1.AWSMachineTemplate
+
+			BeforeEach(func() {
+				By("Creating the CAPI machine deployment")
+				capiMachineDeployment = &capiv1beta1.MachineDeployment{
+					ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: capiNamespace.Name},
+					Spec: capiv1beta1.MachineDeploymentSpec{
+						ClusterName: capaClusterBuilder.Build().GetName(),
+						Replicas:    ptr.To[int32](3),
+						Selector: metav1.LabelSelector{
+							MatchLabels: map[string]string{"machine.openshift.io/cluster-api-machineset": "foo"},
+						},
+						Template: capiv1beta1.MachineTemplateSpec{
+							ObjectMeta: capiv1beta1.ObjectMeta{
+								Labels: map[string]string{"machine.openshift.io/cluster-api-machineset": "foo"},
+							},
+							Spec: capiMachineSetBuilder.Build().Spec.Template.Spec,
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, capiMachineDeployment)).Should(Succeed())
+
+				By("Creating the CAPI machine set mirroring the MAPI machine set before it became a machine deployment")
+				capiMachineSet = capiMachineSetBuilder.Build()
+				Expect(k8sClient.Create(ctx, capiMachineSet)).Should(Succeed())
+
+				By("Creating an infra machine template the CAPI machine deployment has rolled away from")
+				staleCAPAMachineTemplate = capav1builder.AWSMachineTemplate().
+					WithNamespace(capiNamespace.GetName()).
+					WithName("machine-template-stale").
+					WithLabels(map[string]string{capiv1beta1.MachineDeploymentNameLabel: capiMachineDeployment.Name}).
+					Build()
+				Expect(k8sClient.Create(ctx, staleCAPAMachineTemplate)).Should(Succeed())
+
+				By("Creating the MAPI machine set with the machine deployment annotation")
+				mapiMachineSet = mapiMachineSetBuilder.WithAnnotations(map[string]string{
+					"machine.openshift.io/cluster-api-machine-deployment": "true",
+				}).Build()
+				Expect(k8sClient.Create(ctx, mapiMachineSet)).Should(Succeed())
+
+				By("Setting the MAPI machine set AuthoritativeAPI to ClusterAPI")
+				Eventually(k.UpdateStatus(mapiMachineSet, func() {
+					mapiMachineSet.Status.AuthoritativeAPI = machinev1beta1.MachineAuthorityClusterAPI
+				})).Should(Succeed())
+			})
+
+			It("should update the synchronized condition on the MAPI machine set to True", func() {
+				Eventually(k.Object(mapiMachineSet), timeout).Should(
+					HaveField("Status.Conditions", ContainElement(
+						SatisfyAll(
+							HaveField("Type", Equal(consts.SynchronizedCondition)),
+							HaveField("Status", Equal(corev1.ConditionTrue)),
+							HaveField("Reason", Equal("ResourceSynchronized")),
+							HaveField("Message", Equal("Successfully synchronized CAPI MachineDeployment to MAPI")),
+						))),
+				)
+			})
+
+			It("should sync the replica count of the CAPI machine deployment to the MAPI machine set", func() {
+				Eventually(k.Object(mapiMachineSet), timeout).Should(
+					HaveField("Spec.Replicas", HaveValue(BeEquivalentTo(3))),
+				)
+			})
+
+			It("should adopt the existing CAPI machine set into the CAPI machine deployment", func() {
+				Eventually(k.Object(capiMachineSet), timeout).Should(SatisfyAll(
+					HaveField("OwnerReferences", ContainElement(SatisfyAll(
+						HaveField("Kind", Equal("MachineDeployment")),
+						HaveField("Name", Equal(capiMachineDeployment.Name)),
+						HaveField("Controller", HaveValue(BeTrue())),
+					))),
+					HaveField("Labels", HaveKeyWithValue(capiv1beta1.MachineDeploymentNameLabel, capiMachineDeployment.Name)),
+					HaveField("Spec.Template.Labels", HaveKeyWithValue(capiv1beta1.MachineDeploymentNameLabel, capiMachineDeployment.Name)),
+				))
+			})
+
+			It("should delete the infra machine templates the CAPI machine deployment no longer references", func() {
+				Eventually(k.Get(staleCAPAMachineTemplate), timeout).Should(MatchError("awsmachinetemplates.infrastructure.cluster.x-k8s.io \"machine-template-stale\" not found"))
+				Consistently(k.Get(capaMachineTemplate), timeout).Should(Succeed())
+			})
+		})
+
 		Context("when the MAPI machine set has MachineAuthority not set", func() {
 			BeforeEach(func() {
 				By("Creating the CAPI and MAPI MachineSets")
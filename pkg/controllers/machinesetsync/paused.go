@@ -0,0 +1,98 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package machinesetsync
+
+import (
+	"context"
+	"fmt"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	machinev1applyconfigs "github.com/openshift/client-go/machine/applyconfigurations/machine/v1beta1"
+	consts "github.com/openshift/cluster-capi-operator/pkg/controllers"
+	"github.com/openshift/cluster-capi-operator/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const reasonFailedToUpdatePausedState = "FailedToUpdatePausedState"
+
+// setPausedAnnotations sets the pause annotation on a CAPI resource converted from the MAPI machine set,
+// so that the resource is created or updated already paused when Cluster API is not authoritative.
+// The MAPI controllers pause on the authoritative API in the status of the MAPI resources instead,
+// so the pause annotation is removed from MAPI resources, as the conversion copies annotations verbatim.
+func setPausedAnnotations(obj client.Object, authoritativeAPI machinev1beta1.MachineAuthority, isCAPIResource bool) {
+	util.SetPausedAnnotation(obj, capiv1beta1.PausedAnnotation, isCAPIResource && util.IsCAPIPaused(authoritativeAPI))
+}
+
+// syncPaused pauses the given CAPI resources when Machine API is authoritative, and unpauses them otherwise.
+// The MAPI machine set is paused by the MAPI controllers themselves, based on its authoritative API.
+// Each resource with conditions carries a Paused condition showing which side is currently paused.
+func (r *MachineSetSyncReconciler) syncPaused(ctx context.Context, mapiMachineSet *machinev1beta1.MachineSet, capiResources ...client.Object) error {
+	authoritativeAPI := mapiMachineSet.Status.AuthoritativeAPI
+
+	for _, obj := range capiResources {
+		if err := util.EnsureCAPIPaused(ctx, r.Client, obj, authoritativeAPI); err != nil {
+			return r.failedToUpdatePausedState(ctx, mapiMachineSet, fmt.Errorf("failed to update paused state of CAPI %T %q: %w", obj, obj.GetName(), err))
+		}
+	}
+
+	return r.updatePausedConditionWithPatch(ctx, mapiMachineSet)
+}
+
+// failedToUpdatePausedState sets the synchronized condition of the MAPI machine set to false
+// when the paused state of the resources could not be updated.
+func (r *MachineSetSyncReconciler) failedToUpdatePausedState(ctx context.Context, mapiMachineSet *machinev1beta1.MachineSet, pausedErr error) error {
+	if condErr := r.updateSynchronizedConditionWithPatch(
+		ctx, mapiMachineSet, corev1.ConditionFalse, reasonFailedToUpdatePausedState, pausedErr.Error(), nil); condErr != nil {
+		return utilerrors.NewAggregate([]error{pausedErr, condErr})
+	}
+
+	return pausedErr
+}
+
+// updatePausedConditionWithPatch updates the paused condition using a server
+// side apply patch. A separate field owner is used so that the other
+// conditions are not removed by this patch.
+func (r *MachineSetSyncReconciler) updatePausedConditionWithPatch(ctx context.Context, mapiMachineSet *machinev1beta1.MachineSet) error {
+	authoritativeAPI := mapiMachineSet.Status.AuthoritativeAPI
+	reason, message := util.PausedConditionReason(authoritativeAPI)
+
+	status := corev1.ConditionFalse
+	if util.IsMAPIPaused(authoritativeAPI) {
+		status = corev1.ConditionTrue
+	}
+
+	conditionAc := machinev1applyconfigs.Condition().
+		WithType(consts.PausedCondition).
+		WithStatus(status).
+		WithReason(reason).
+		WithMessage(message).
+		WithSeverity(machinev1beta1.ConditionSeverityNone)
+
+	util.SetLastTransitionTime(consts.PausedCondition, mapiMachineSet.Status.Conditions, conditionAc)
+
+	msAc := machinev1applyconfigs.MachineSet(mapiMachineSet.GetName(), mapiMachineSet.GetNamespace()).
+		WithStatus(machinev1applyconfigs.MachineSetStatus().WithConditions(conditionAc))
+
+	if err := r.Status().Patch(ctx, mapiMachineSet, util.ApplyConfigPatch(msAc), client.ForceOwnership, client.FieldOwner("machineset-sync-controller-paused")); err != nil {
+		return fmt.Errorf("failed to patch MAPI machine set status with paused condition: %w", err)
+	}
+
+	return nil
+}
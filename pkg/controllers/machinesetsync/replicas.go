@@ -0,0 +1,125 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package machinesetsync
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// synchronizedGenerationAnnotation records, on a CAPI resource mirroring an authoritative MAPI machine set,
+// the generation of the CAPI resource when it was last synchronized.
+const synchronizedGenerationAnnotation = "cluster-api.openshift.io/synchronized-generation"
+
+// forwardReplicas applies a change of spec.replicas made on the non-authoritative side to the authoritative side,
+// so that the change is not overwritten when the mirror is synchronized. This allows automation scaling the
+// MAPI MachineSet to keep working once CAPI is authoritative, and the other way around.
+//
+// The replicas of the mirror are only forwarded when the mirror itself changed since the last synchronization,
+// and the authoritative resource did not. Any other difference is synchronized as usual.
+func (r *MachineSetSyncReconciler) forwardReplicas(ctx context.Context, mapiMachineSet *machinev1beta1.MachineSet, capiObj client.Object, capiReplicas *int32) error {
+	if !r.ForwardReplicas {
+		return nil
+	}
+
+	synchronizedGeneration := mapiMachineSet.Status.SynchronizedGeneration
+
+	switch mapiMachineSet.Status.AuthoritativeAPI {
+	case machinev1beta1.MachineAuthorityMachineAPI:
+		if mapiMachineSet.Generation != synchronizedGeneration || !capiGenerationChangedSinceSync(capiObj) ||
+			capiReplicas == nil || ptr.Equal(mapiMachineSet.Spec.Replicas, capiReplicas) {
+			return nil
+		}
+
+		return r.patchForwardedReplicas(ctx, mapiMachineSet, mapiMachineSet, *capiReplicas)
+	case machinev1beta1.MachineAuthorityClusterAPI:
+		if capiObj.GetGeneration() != synchronizedGeneration || mapiMachineSet.Spec.Replicas == nil || ptr.Equal(mapiMachineSet.Spec.Replicas, capiReplicas) {
+			return nil
+		}
+
+		return r.patchForwardedReplicas(ctx, mapiMachineSet, capiObj, *mapiMachineSet.Spec.Replicas)
+	case machinev1beta1.MachineAuthorityMigrating:
+		return nil
+	default:
+		return nil
+	}
+}
+
+// capiGenerationChangedSinceSync determines whether the spec of a CAPI resource was changed since it was last
+// synchronized from the MAPI machine set. A CAPI resource without a recorded generation is not considered changed,
+// nor is a stale copy of the resource read before the last synchronization.
+func capiGenerationChangedSinceSync(capiObj client.Object) bool {
+	recorded, ok := capiObj.GetAnnotations()[synchronizedGenerationAnnotation]
+	if !ok {
+		return false
+	}
+
+	recordedGeneration, err := strconv.ParseInt(recorded, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	return capiObj.GetGeneration() > recordedGeneration
+}
+
+// recordSynchronizedGeneration records the generation of a CAPI resource synchronized from an authoritative MAPI
+// machine set, so that later changes to the CAPI resource can be told apart from the synchronization itself.
+func (r *MachineSetSyncReconciler) recordSynchronizedGeneration(ctx context.Context, capiObj client.Object) error {
+	if !r.ForwardReplicas {
+		return nil
+	}
+
+	generation := strconv.FormatInt(capiObj.GetGeneration(), 10)
+	if capiObj.GetAnnotations()[synchronizedGenerationAnnotation] == generation {
+		return nil
+	}
+
+	patch := client.RawPatch(types.MergePatchType,
+		[]byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, synchronizedGenerationAnnotation, generation)))
+
+	if err := r.Patch(ctx, capiObj, patch); err != nil {
+		return fmt.Errorf("failed to record the synchronized generation of %T: %w", capiObj, err)
+	}
+
+	return nil
+}
+
+// patchForwardedReplicas sets spec.replicas of the authoritative resource with a merge patch,
+// and records an event on the MAPI machine set.
+func (r *MachineSetSyncReconciler) patchForwardedReplicas(ctx context.Context, mapiMachineSet *machinev1beta1.MachineSet, obj client.Object, replicas int32) error {
+	logger := log.FromContext(ctx)
+
+	patch := client.RawPatch(types.MergePatchType, []byte(fmt.Sprintf(`{"spec":{"replicas":%d}}`, replicas)))
+
+	if err := r.Patch(ctx, obj, patch); err != nil {
+		return fmt.Errorf("failed to forward replicas to the authoritative %T: %w", obj, err)
+	}
+
+	logger.Info("Forwarded replicas from the non-authoritative machine set", "replicas", replicas)
+	r.Recorder.Eventf(mapiMachineSet, corev1.EventTypeNormal, "ReplicasForwarded",
+		"Forwarded %d replicas from the non-authoritative machine set to the authoritative %T", replicas, obj)
+
+	return nil
+}
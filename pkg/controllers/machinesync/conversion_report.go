@@ -0,0 +1,106 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinesync
+
+import (
+	"context"
+	"fmt"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	machinev1applyconfigs "github.com/openshift/client-go/machine/applyconfigurations/machine/v1beta1"
+	consts "github.com/openshift/cluster-capi-operator/pkg/controllers"
+	"github.com/openshift/cluster-capi-operator/pkg/controllers/syncmetrics"
+	"github.com/openshift/cluster-capi-operator/pkg/conversion/report"
+	"github.com/openshift/cluster-capi-operator/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// reportConversion logs and records an event for each warning of the conversion report, counts the conversion
+// in the sync metrics, and surfaces the report on the LosslessConversion condition of the MAPI machine.
+// The conversion error is only used to mark the condition as failed when the report holds no error entries.
+func (r *MachineSyncReconciler) reportConversion(ctx context.Context, mapiMachine *machinev1beta1.Machine, direction report.Direction, conversionReport report.Report, conversionErr error) error {
+	logger := log.FromContext(ctx)
+
+	syncmetrics.RecordConversion(r.Platform, direction, conversionReport, conversionErr)
+
+	for _, warning := range conversionReport.Warnings() {
+		logger.Info("Warning during conversion", "warning", warning)
+		r.Recorder.Event(mapiMachine, corev1.EventTypeWarning, "ConversionWarning", warning)
+	}
+
+	return r.updateLosslessConversionConditionWithPatch(ctx, mapiMachine, conversionReport, conversionErr)
+}
+
+// updateLosslessConversionConditionWithPatch updates the lossless conversion condition
+// using a server side apply patch. A separate field owner is used so that
+// the 'Synchronized' condition is not removed by this patch.
+func (r *MachineSyncReconciler) updateLosslessConversionConditionWithPatch(ctx context.Context, mapiMachine *machinev1beta1.Machine, conversionReport report.Report, conversionErr error) error {
+	conditionAc, err := util.LosslessConversionCondition(mapiMachine.Status.Conditions, conversionReport, conversionErr)
+	if err != nil {
+		return fmt.Errorf("failed to build lossless conversion condition: %w", err)
+	}
+
+	machineAc := machinev1applyconfigs.Machine(mapiMachine.GetName(), mapiMachine.GetNamespace()).
+		WithStatus(machinev1applyconfigs.MachineStatus().WithConditions(conditionAc))
+
+	if err := r.Status().Patch(ctx, mapiMachine, util.ApplyConfigPatch(machineAc), client.ForceOwnership, client.FieldOwner("machine-sync-controller-conversion-report")); err != nil {
+		return fmt.Errorf("failed to patch MAPI machine status with lossless conversion condition: %w", err)
+	}
+
+	return nil
+}
+
+// updateSynchronizedConditionWithPatch updates the synchronized condition
+// using a server side apply patch. We do this to force ownership of the
+// 'Synchronized' condition and 'SynchronizedGeneration'.
+func (r *MachineSyncReconciler) updateSynchronizedConditionWithPatch(ctx context.Context, mapiMachine *machinev1beta1.Machine, status corev1.ConditionStatus, reason, message string, generation *int64) error {
+	severity := machinev1beta1.ConditionSeverityError
+	if status == corev1.ConditionTrue {
+		severity = machinev1beta1.ConditionSeverityNone
+	}
+
+	conditionAc := machinev1applyconfigs.Condition().
+		WithType(consts.SynchronizedCondition).
+		WithStatus(status).
+		WithReason(reason).
+		WithMessage(message).
+		WithSeverity(severity)
+
+	util.SetLastTransitionTime(consts.SynchronizedCondition, mapiMachine.Status.Conditions, conditionAc)
+
+	statusAc := machinev1applyconfigs.MachineStatus().
+		WithConditions(conditionAc)
+
+	if status == corev1.ConditionTrue && generation != nil {
+		statusAc = statusAc.WithSynchronizedGeneration(*generation)
+	}
+
+	machineAc := machinev1applyconfigs.Machine(mapiMachine.GetName(), mapiMachine.GetNamespace()).
+		WithStatus(statusAc)
+
+	if err := r.Status().Patch(ctx, mapiMachine, util.ApplyConfigPatch(machineAc), client.ForceOwnership, client.FieldOwner("machine-sync-controller")); err != nil {
+		return fmt.Errorf("failed to patch MAPI machine status with synchronized condition: %w", err)
+	}
+
+	if status == corev1.ConditionTrue && generation != nil {
+		syncmetrics.ObserveSynchronized(machineKind, mapiMachine.GetName())
+	}
+
+	return nil
+}
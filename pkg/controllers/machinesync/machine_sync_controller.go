@@ -18,17 +18,21 @@ package machinesync
 
 import (
 	"context"
-	"errors"
 	"fmt"
 
 	"github.com/go-logr/logr"
 	configv1 "github.com/openshift/api/config/v1"
 	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	consts "github.com/openshift/cluster-capi-operator/pkg/controllers"
+	"github.com/openshift/cluster-capi-operator/pkg/controllers/syncmetrics"
+	"github.com/openshift/cluster-capi-operator/pkg/conversion"
+	"github.com/openshift/cluster-capi-operator/pkg/conversion/report"
 	"github.com/openshift/cluster-capi-operator/pkg/util"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/client-go/tools/record"
-	capav1beta2 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
 	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -43,11 +47,15 @@ const (
 	mapiNamespace  string = "openshift-machine-api"
 	machineSetKind string = "MachineSet"
 	controllerName string = "MachineSyncController"
-)
 
-var (
-	// errPlatformNotSupported is returned when the platform is not supported.
-	errPlatformNotSupported = errors.New("error determining InfraMachine type, platform not supported")
+	// machineKind is the kind reported in the sync metrics.
+	machineKind string = "Machine"
+
+	reasonFailedToGetCAPIInfraResources    = "FailedToGetCAPIInfraResources"
+	reasonFailedToConvertCAPIMachineToMAPI = "FailedToConvertCAPIMachineToMAPI"
+	reasonFailedToConvertMAPIMachineToCAPI = "FailedToConvertMAPIMachineToCAPI"
+
+	messageSuccessfullySynchronized = "Successfully synchronized the paused state and converted the authoritative machine"
 )
 
 // MachineSyncReconciler reconciles CAPI and MAPI machines.
@@ -60,15 +68,20 @@ type MachineSyncReconciler struct {
 	Platform      configv1.PlatformType
 	CAPINamespace string
 	MAPINamespace string
+
+	// conversion holds the infrastructure types and converters registered for the Platform.
+	conversion conversion.Platform
 }
 
 // SetupWithManager sets the CoreClusterReconciler controller up with the given manager.
 func (r *MachineSyncReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	infraMachine, err := getInfraMachineFromProvider(r.Platform)
+	platformConversion, err := conversion.PlatformFor(r.Platform)
 	if err != nil {
-		return fmt.Errorf("failed to get InfraMachine from Provider: %w", err)
+		return fmt.Errorf("failed to get conversion for platform: %w", err)
 	}
 
+	r.conversion = platformConversion
+
 	// Allow the namespaces to be set externally for test purposes, when not set,
 	// default to the production namespaces.
 	if r.CAPINamespace == "" {
@@ -88,7 +101,7 @@ func (r *MachineSyncReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			builder.WithPredicates(util.FilterNamespace(r.CAPINamespace)),
 		).
 		Watches(
-			infraMachine,
+			r.conversion.NewInfraMachine(),
 			handler.EnqueueRequestsFromMapFunc(util.RewriteNamespace(r.MAPINamespace)),
 			builder.WithPredicates(util.FilterNamespace(r.CAPINamespace)),
 		).
@@ -147,6 +160,10 @@ func (r *MachineSyncReconciler) Reconcile(ctx context.Context, req reconcile.Req
 		return ctrl.Result{}, fmt.Errorf("failed to get CAPI machine:: %w", err)
 	}
 
+	if mapiMachineNotFound {
+		syncmetrics.Forget(machineKind, req.Name)
+	}
+
 	if mapiMachineNotFound && capiMachineNotFound {
 		logger.Info("CAPI and MAPI machines not found, nothing to do")
 		return ctrl.Result{}, nil
@@ -158,10 +175,15 @@ func (r *MachineSyncReconciler) Reconcile(ctx context.Context, req reconcile.Req
 		if shouldReconcile, err := r.shouldMirrorCAPIMachineToMAPIMachine(ctx, logger, capiMachine); err != nil {
 			return ctrl.Result{}, err
 		} else if shouldReconcile {
-			return r.reconcileCAPIMachinetoMAPIMachine(ctx, capiMachine, mapiMachine)
+			return r.reconcileCAPIMachinetoMAPIMachine(ctx, capiMachine, nil)
 		}
 	}
 
+	// The reconcile functions expect a nil Machine when it does not exist.
+	if capiMachineNotFound {
+		capiMachine = nil
+	}
+
 	switch mapiMachine.Status.AuthoritativeAPI {
 	case machinev1beta1.MachineAuthorityMachineAPI:
 		return r.reconcileMAPIMachinetoCAPIMachine(ctx, mapiMachine, capiMachine)
@@ -177,33 +199,132 @@ func (r *MachineSyncReconciler) Reconcile(ctx context.Context, req reconcile.Req
 }
 
 // reconcileCAPIMachinetoMAPIMachine reconciles a CAPI Machine to a MAPI Machine.
-func (r *MachineSyncReconciler) reconcileCAPIMachinetoMAPIMachine(ctx context.Context, capiMachine *capiv1beta1.Machine, mapiMachine *machinev1beta1.Machine) (ctrl.Result, error) {
-	return ctrl.Result{}, nil
+// The converted MAPI Machine is not applied yet, the conversion is reported so that
+// Machines which can not be represented in MAPI are surfaced.
+func (r *MachineSyncReconciler) reconcileCAPIMachinetoMAPIMachine(ctx context.Context, capiMachine *capiv1beta1.Machine, mapiMachine *machinev1beta1.Machine) (ctrl.Result, error) { //nolint:unparam
+	// The MAPI Machine has not been mirrored yet, so there is nothing to pause.
+	if mapiMachine == nil {
+		return ctrl.Result{}, nil
+	}
+
+	if err := r.syncPaused(ctx, mapiMachine, capiMachine); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if capiMachine == nil {
+		return ctrl.Result{}, nil
+	}
+
+	syncmetrics.ObserveGeneration(machineKind, mapiMachine.Name, capiMachine.Generation, mapiMachine.Status.SynchronizedGeneration)
+
+	infraCluster, infraMachine, err := r.fetchCAPIInfraResources(ctx, capiMachine)
+	if err != nil {
+		fetchErr := fmt.Errorf("failed to fetch CAPI infra resources: %w", err)
+
+		if condErr := r.updateSynchronizedConditionWithPatch(
+			ctx, mapiMachine, corev1.ConditionFalse, reasonFailedToGetCAPIInfraResources, fetchErr.Error(), nil); condErr != nil {
+			return ctrl.Result{}, utilerrors.NewAggregate([]error{fetchErr, condErr})
+		}
+
+		return ctrl.Result{}, fetchErr
+	}
+
+	_, conversionReport, err := r.convertCAPIToMAPIMachine(capiMachine, infraMachine, infraCluster)
+	if reportErr := r.reportConversion(ctx, mapiMachine, report.CAPIToMAPI, conversionReport, err); reportErr != nil {
+		return ctrl.Result{}, utilerrors.NewAggregate([]error{err, reportErr})
+	}
+
+	if err != nil {
+		conversionErr := fmt.Errorf("failed to convert CAPI machine to MAPI machine: %w", err)
+
+		if condErr := r.updateSynchronizedConditionWithPatch(
+			ctx, mapiMachine, corev1.ConditionFalse, reasonFailedToConvertCAPIMachineToMAPI, conversionErr.Error(), nil); condErr != nil {
+			return ctrl.Result{}, utilerrors.NewAggregate([]error{conversionErr, condErr})
+		}
+
+		return ctrl.Result{}, conversionErr
+	}
+
+	return ctrl.Result{}, r.updateSynchronizedConditionWithPatch(ctx, mapiMachine, corev1.ConditionTrue,
+		consts.ReasonResourceSynchronized, messageSuccessfullySynchronized, &capiMachine.Generation)
 }
 
 // reconcileMAPIMachinetoCAPIMachine a MAPI Machine to a CAPI Machine.
-func (r *MachineSyncReconciler) reconcileMAPIMachinetoCAPIMachine(ctx context.Context, mapiMachine *machinev1beta1.Machine, capiMachine *capiv1beta1.Machine) (ctrl.Result, error) {
-	return ctrl.Result{}, nil
+// The converted CAPI Machine is not applied yet, the conversion is reported so that
+// Machines which can not be migrated to CAPI are surfaced.
+func (r *MachineSyncReconciler) reconcileMAPIMachinetoCAPIMachine(ctx context.Context, mapiMachine *machinev1beta1.Machine, capiMachine *capiv1beta1.Machine) (ctrl.Result, error) { //nolint:unparam
+	if err := r.syncPaused(ctx, mapiMachine, capiMachine); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	syncmetrics.ObserveGeneration(machineKind, mapiMachine.Name, mapiMachine.Generation, mapiMachine.Status.SynchronizedGeneration)
+
+	_, _, conversionReport, err := r.convertMAPIToCAPIMachine(mapiMachine)
+	if reportErr := r.reportConversion(ctx, mapiMachine, report.MAPIToCAPI, conversionReport, err); reportErr != nil {
+		return ctrl.Result{}, utilerrors.NewAggregate([]error{err, reportErr})
+	}
+
+	if err != nil {
+		conversionErr := fmt.Errorf("failed to convert MAPI machine to CAPI machine: %w", err)
+
+		if condErr := r.updateSynchronizedConditionWithPatch(
+			ctx, mapiMachine, corev1.ConditionFalse, reasonFailedToConvertMAPIMachineToCAPI, conversionErr.Error(), nil); condErr != nil {
+			return ctrl.Result{}, utilerrors.NewAggregate([]error{conversionErr, condErr})
+		}
+
+		return ctrl.Result{}, conversionErr
+	}
+
+	return ctrl.Result{}, r.updateSynchronizedConditionWithPatch(ctx, mapiMachine, corev1.ConditionTrue,
+		consts.ReasonResourceSynchronized, messageSuccessfullySynchronized, &mapiMachine.Generation)
 }
 
-// getInfraMachineFromProvider returns the correct InfraMachine implementation
-// for a given provider.
-//
-// As we implement other cloud providers, we'll need to update this list.
-func getInfraMachineFromProvider(platform configv1.PlatformType) (client.Object, error) {
-	switch platform {
-	case configv1.AWSPlatformType:
-		return &capav1beta2.AWSMachine{}, nil
-	default:
-		return nil, fmt.Errorf("%w: %s", errPlatformNotSupported, platform)
+// fetchCAPIInfraResources fetches the CAPI InfraCluster and InfraMachine of a CAPI machine.
+func (r *MachineSyncReconciler) fetchCAPIInfraResources(ctx context.Context, capiMachine *capiv1beta1.Machine) (client.Object, client.Object, error) {
+	infraClusterKey := client.ObjectKey{
+		Namespace: capiMachine.Namespace,
+		Name:      capiMachine.Spec.ClusterName,
+	}
+
+	infraMachineKey := client.ObjectKey{
+		Namespace: capiMachine.Namespace,
+		Name:      capiMachine.Spec.InfrastructureRef.Name,
 	}
+
+	infraCluster := r.conversion.NewInfraCluster()
+	infraMachine := r.conversion.NewInfraMachine()
+
+	if err := r.Get(ctx, infraClusterKey, infraCluster); err != nil {
+		return nil, nil, fmt.Errorf("failed to get CAPI infrastructure cluster: %w", err)
+	}
+
+	if err := r.Get(ctx, infraMachineKey, infraMachine); err != nil {
+		return nil, nil, fmt.Errorf("failed to get CAPI infrastructure machine: %w", err)
+	}
+
+	return infraCluster, infraMachine, nil
+}
+
+// convertCAPIToMAPIMachine converts a CAPI Machine, with its InfraMachine and InfraCluster, to a MAPI Machine.
+func (r *MachineSyncReconciler) convertCAPIToMAPIMachine(capiMachine *capiv1beta1.Machine, infraMachine, infraCluster client.Object) (*machinev1beta1.Machine, report.Report, error) {
+	converter, err := r.conversion.FromCAPIMachine(capiMachine, infraMachine, infraCluster)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create CAPI machine converter: %w", err)
+	}
+
+	return converter.ToMachine() //nolint:wrapcheck
+}
+
+// convertMAPIToCAPIMachine converts a MAPI Machine to a CAPI Machine and InfraMachine.
+func (r *MachineSyncReconciler) convertMAPIToCAPIMachine(mapiMachine *machinev1beta1.Machine) (*capiv1beta1.Machine, client.Object, report.Report, error) {
+	return r.conversion.FromMAPIMachine(mapiMachine, r.Infra).ToMachineAndInfrastructureMachine() //nolint:wrapcheck
 }
 
 // shouldMirrorCAPIMachineToMAPIMachine takes a CAPI machine and determines if there should
 // be a MAPI mirror, it returns true only if:
 //
 // 1. The CAPI Machine is owned by a CAPI MachineSet,
-// 2. That owning CAPI MachineSet has a MAPI MachineSet Mirror.
+// 2. That owning CAPI MachineSet, or the CAPI MachineDeployment owning it, has a MAPI MachineSet Mirror.
 func (r *MachineSyncReconciler) shouldMirrorCAPIMachineToMAPIMachine(ctx context.Context, logger logr.Logger, machine *capiv1beta1.Machine) (bool, error) {
 	logger.WithName("shouldMirrorCAPIMachineToMAPIMachine").
 		Info("checking if CAPI machine should be mirrored", "machine", machine.GetName())
@@ -216,16 +337,24 @@ func (r *MachineSyncReconciler) shouldMirrorCAPIMachineToMAPIMachine(ctx context
 
 		logger.Info("CAPI machine is owned by a machineset",
 			"machine", machine.GetName(), "machineset", ref.Name)
+
+		// MAPI MachineSets represented as a CAPI MachineDeployment are mirrored by the MachineDeployment,
+		// rather than by the CAPI MachineSets it creates.
+		mirrorName := ref.Name
+		if machineDeploymentName, ok := machine.Labels[capiv1beta1.MachineDeploymentNameLabel]; ok {
+			mirrorName = machineDeploymentName
+		}
+
 		// Checks if the CAPI MS has a mirror in MAPI namespace
 		key := client.ObjectKey{
 			Namespace: r.MAPINamespace,
-			Name:      ref.Name,
+			Name:      mirrorName,
 		}
 		mapiMachineSet := &machinev1beta1.MachineSet{}
 
 		if err := r.Get(ctx, key, mapiMachineSet); apierrors.IsNotFound(err) {
 			logger.Info("MAPI MachineSet mirror not found, nothing to do",
-				"machine", machine.GetName(), "machineset", ref.Name)
+				"machine", machine.GetName(), "machineset", mirrorName)
 
 			return false, nil
 		} else if err != nil {
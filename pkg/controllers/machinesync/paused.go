@@ -0,0 +1,102 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinesync
+
+import (
+	"context"
+	"fmt"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	machinev1applyconfigs "github.com/openshift/client-go/machine/applyconfigurations/machine/v1beta1"
+	consts "github.com/openshift/cluster-capi-operator/pkg/controllers"
+	"github.com/openshift/cluster-capi-operator/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// syncPaused pauses the CAPI machine and its InfraMachine when Machine API is authoritative, and unpauses them otherwise.
+// The MAPI machine is paused by the MAPI controllers themselves, based on its authoritative API.
+// Each of them carries a Paused condition showing which side is currently paused.
+func (r *MachineSyncReconciler) syncPaused(ctx context.Context, mapiMachine *machinev1beta1.Machine, capiMachine *capiv1beta1.Machine) error {
+	if capiMachine != nil {
+		if err := r.ensureCAPIMachinePaused(ctx, capiMachine, mapiMachine.Status.AuthoritativeAPI); err != nil {
+			return err
+		}
+	}
+
+	return r.updatePausedConditionWithPatch(ctx, mapiMachine)
+}
+
+// ensureCAPIMachinePaused pauses or unpauses the CAPI machine and its InfraMachine, when it exists.
+func (r *MachineSyncReconciler) ensureCAPIMachinePaused(ctx context.Context, capiMachine *capiv1beta1.Machine, authoritativeAPI machinev1beta1.MachineAuthority) error {
+	if err := util.EnsureCAPIPaused(ctx, r.Client, capiMachine, authoritativeAPI); err != nil {
+		return fmt.Errorf("failed to update paused state of CAPI machine: %w", err)
+	}
+
+	infraMachine := r.conversion.NewInfraMachine()
+	infraMachineKey := client.ObjectKey{
+		Namespace: capiMachine.Namespace,
+		Name:      capiMachine.Spec.InfrastructureRef.Name,
+	}
+
+	if err := r.Get(ctx, infraMachineKey, infraMachine); apierrors.IsNotFound(err) {
+		log.FromContext(ctx).Info("CAPI infra machine not found", "infraMachine", infraMachineKey.Name)
+
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to get CAPI infra machine: %w", err)
+	}
+
+	if err := util.EnsureCAPIPaused(ctx, r.Client, infraMachine, authoritativeAPI); err != nil {
+		return fmt.Errorf("failed to update paused state of CAPI infra machine: %w", err)
+	}
+
+	return nil
+}
+
+// updatePausedConditionWithPatch updates the paused condition of the MAPI machine
+// using a server side apply patch.
+func (r *MachineSyncReconciler) updatePausedConditionWithPatch(ctx context.Context, mapiMachine *machinev1beta1.Machine) error {
+	authoritativeAPI := mapiMachine.Status.AuthoritativeAPI
+	reason, message := util.PausedConditionReason(authoritativeAPI)
+
+	status := corev1.ConditionFalse
+	if util.IsMAPIPaused(authoritativeAPI) {
+		status = corev1.ConditionTrue
+	}
+
+	conditionAc := machinev1applyconfigs.Condition().
+		WithType(consts.PausedCondition).
+		WithStatus(status).
+		WithReason(reason).
+		WithMessage(message).
+		WithSeverity(machinev1beta1.ConditionSeverityNone)
+
+	util.SetLastTransitionTime(consts.PausedCondition, mapiMachine.Status.Conditions, conditionAc)
+
+	machineAc := machinev1applyconfigs.Machine(mapiMachine.GetName(), mapiMachine.GetNamespace()).
+		WithStatus(machinev1applyconfigs.MachineStatus().WithConditions(conditionAc))
+
+	if err := r.Status().Patch(ctx, mapiMachine, util.ApplyConfigPatch(machineAc), client.ForceOwnership, client.FieldOwner("machine-sync-controller-paused")); err != nil {
+		return fmt.Errorf("failed to patch MAPI machine status with paused condition: %w", err)
+	}
+
+	return nil
+}
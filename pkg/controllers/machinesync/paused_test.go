@@ -0,0 +1,213 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package machinesync
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	capiv1resourcebuilder "github.com/openshift/cluster-api-actuator-pkg/testutils/resourcebuilder/cluster-api/core/v1beta1"
+	capav1builder "github.com/openshift/cluster-api-actuator-pkg/testutils/resourcebuilder/cluster-api/infrastructure/v1beta2"
+	corev1resourcebuilder "github.com/openshift/cluster-api-actuator-pkg/testutils/resourcebuilder/core/v1"
+	machinev1resourcebuilder "github.com/openshift/cluster-api-actuator-pkg/testutils/resourcebuilder/machine/v1beta1"
+	consts "github.com/openshift/cluster-capi-operator/pkg/controllers"
+	"github.com/openshift/cluster-capi-operator/pkg/conversion"
+	"github.com/openshift/cluster-capi-operator/pkg/test"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	capav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/envtest/komega"
+)
+
+var _ = Describe("MachineSync paused state", func() {
+	var reconciler *MachineSyncReconciler
+
+	var mapiMachine *machinev1beta1.Machine
+	var capiMachine *capiv1beta1.Machine
+	var awsMachine *capav1.AWSMachine
+
+	setAuthoritativeAPI := func(authoritativeAPI machinev1beta1.MachineAuthority) {
+		Eventually(komega.UpdateStatus(mapiMachine, func() {
+			mapiMachine.Status.AuthoritativeAPI = authoritativeAPI
+		})).Should(Succeed())
+	}
+
+	pausedCondition := func(status corev1.ConditionStatus, reason string) OmegaMatcher {
+		return SatisfyAll(
+			HaveField("Type", BeEquivalentTo(consts.PausedCondition)),
+			HaveField("Status", Equal(status)),
+			HaveField("Reason", Equal(reason)),
+		)
+	}
+
+	BeforeEach(func() {
+		By("Setting up a namespace for the test")
+		namespace := corev1resourcebuilder.Namespace().WithGenerateName("machine-sync-paused-").Build()
+		Expect(k8sClient.Create(ctx, namespace)).To(Succeed())
+
+		platform, err := conversion.PlatformFor(configv1.AWSPlatformType)
+		Expect(err).ToNot(HaveOccurred())
+
+		reconciler = &MachineSyncReconciler{
+			Client:     k8sClient,
+			Platform:   configv1.AWSPlatformType,
+			conversion: platform,
+		}
+
+		By("Creating the CAPI infra machine, CAPI machine and MAPI machine")
+		awsMachine = capav1builder.AWSMachine().WithNamespace(namespace.GetName()).WithName("foo").Build()
+		Expect(k8sClient.Create(ctx, awsMachine)).To(Succeed())
+
+		capiMachine = capiv1resourcebuilder.Machine().
+			WithNamespace(namespace.GetName()).
+			WithName("foo").
+			WithClusterName("cluster-foo").
+			WithInfrastructureRef(corev1.ObjectReference{
+				APIVersion: capav1.GroupVersion.String(),
+				Kind:       "AWSMachine",
+				Name:       awsMachine.GetName(),
+			}).
+			Build()
+		Expect(k8sClient.Create(ctx, capiMachine)).To(Succeed())
+
+		mapiMachine = machinev1resourcebuilder.Machine().
+			WithNamespace(namespace.GetName()).
+			WithName("foo").
+			WithProviderSpecBuilder(machinev1resourcebuilder.AWSProviderSpec()).
+			Build()
+		Expect(k8sClient.Create(ctx, mapiMachine)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(test.CleanupAndWait(ctx, k8sClient, mapiMachine, capiMachine, awsMachine)).To(Succeed())
+	})
+
+	Context("when Machine API is authoritative", func() {
+		BeforeEach(func() {
+			setAuthoritativeAPI(machinev1beta1.MachineAuthorityMachineAPI)
+
+			Expect(reconciler.syncPaused(ctx, mapiMachine, capiMachine)).To(Succeed())
+		})
+
+		It("should pause the CAPI machine", func() {
+			Eventually(komega.Object(capiMachine)).Should(SatisfyAll(
+				HaveField("ObjectMeta.Annotations", HaveKey(capiv1beta1.PausedAnnotation)),
+				HaveField("Status.Conditions", ContainElement(pausedCondition(corev1.ConditionTrue, consts.ReasonAuthoritativeAPIMachineAPI))),
+			))
+		})
+
+		It("should pause the CAPI infra machine", func() {
+			Eventually(komega.Object(awsMachine)).Should(SatisfyAll(
+				HaveField("ObjectMeta.Annotations", HaveKey(capiv1beta1.PausedAnnotation)),
+				HaveField("Status.Conditions", ContainElement(pausedCondition(corev1.ConditionTrue, consts.ReasonAuthoritativeAPIMachineAPI))),
+			))
+		})
+
+		It("should report the MAPI machine as not paused", func() {
+			Eventually(komega.Object(mapiMachine)).Should(SatisfyAll(
+				HaveField("ObjectMeta.Annotations", Not(HaveKey(capiv1beta1.PausedAnnotation))),
+				HaveField("Status.Conditions", ContainElement(pausedCondition(corev1.ConditionFalse, consts.ReasonAuthoritativeAPIMachineAPI))),
+			))
+		})
+
+		Context("and then Cluster API becomes authoritative", func() {
+			BeforeEach(func() {
+				setAuthoritativeAPI(machinev1beta1.MachineAuthorityClusterAPI)
+
+				Expect(reconciler.syncPaused(ctx, mapiMachine, capiMachine)).To(Succeed())
+			})
+
+			It("should unpause the CAPI machine", func() {
+				Eventually(komega.Object(capiMachine)).Should(SatisfyAll(
+					HaveField("ObjectMeta.Annotations", Not(HaveKey(capiv1beta1.PausedAnnotation))),
+					HaveField("Status.Conditions", ContainElement(pausedCondition(corev1.ConditionFalse, consts.ReasonAuthoritativeAPIClusterAPI))),
+				))
+			})
+
+			It("should unpause the CAPI infra machine", func() {
+				Eventually(komega.Object(awsMachine)).Should(
+					HaveField("ObjectMeta.Annotations", Not(HaveKey(capiv1beta1.PausedAnnotation))),
+				)
+			})
+
+			It("should report the MAPI machine as paused", func() {
+				Eventually(komega.Object(mapiMachine)).Should(
+					HaveField("Status.Conditions", ContainElement(pausedCondition(corev1.ConditionTrue, consts.ReasonAuthoritativeAPIClusterAPI))),
+				)
+			})
+		})
+	})
+
+	Context("when Cluster API is authoritative", func() {
+		BeforeEach(func() {
+			setAuthoritativeAPI(machinev1beta1.MachineAuthorityClusterAPI)
+
+			Expect(reconciler.syncPaused(ctx, mapiMachine, capiMachine)).To(Succeed())
+		})
+
+		It("should not pause the CAPI machine", func() {
+			Eventually(komega.Object(capiMachine)).Should(
+				HaveField("ObjectMeta.Annotations", Not(HaveKey(capiv1beta1.PausedAnnotation))),
+			)
+		})
+
+		It("should report the MAPI machine as paused without a pause annotation", func() {
+			Eventually(komega.Object(mapiMachine)).Should(SatisfyAll(
+				HaveField("ObjectMeta.Annotations", Not(HaveKey(capiv1beta1.PausedAnnotation))),
+				HaveField("Status.Conditions", ContainElement(pausedCondition(corev1.ConditionTrue, consts.ReasonAuthoritativeAPIClusterAPI))),
+			))
+		})
+
+		It("should keep the last transition time of the paused condition when its state does not change", func() {
+			Expect(komega.Get(mapiMachine)()).To(Succeed())
+
+			var lastTransitionTime metav1.Time
+
+			for _, condition := range mapiMachine.Status.Conditions {
+				if condition.Type == consts.PausedCondition {
+					lastTransitionTime = condition.LastTransitionTime
+				}
+			}
+
+			Expect(reconciler.syncPaused(ctx, mapiMachine, capiMachine)).To(Succeed())
+
+			Eventually(komega.Object(mapiMachine)).Should(
+				HaveField("Status.Conditions", ContainElement(SatisfyAll(
+					HaveField("Type", BeEquivalentTo(consts.PausedCondition)),
+					HaveField("LastTransitionTime", Equal(lastTransitionTime)),
+				))),
+			)
+		})
+	})
+
+	Context("when the CAPI machine does not exist", func() {
+		BeforeEach(func() {
+			setAuthoritativeAPI(machinev1beta1.MachineAuthorityMachineAPI)
+		})
+
+		It("should only update the paused condition of the MAPI machine", func() {
+			Expect(reconciler.syncPaused(ctx, mapiMachine, nil)).To(Succeed())
+
+			Eventually(komega.Object(mapiMachine)).Should(
+				HaveField("Status.Conditions", ContainElement(pausedCondition(corev1.ConditionFalse, consts.ReasonAuthoritativeAPIMachineAPI))),
+			)
+		})
+	})
+})
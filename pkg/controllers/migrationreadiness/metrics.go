@@ -0,0 +1,60 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package migrationreadiness
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// readinessGauge reports the number of MAPI resources of each kind and migration readiness.
+//
+//nolint:gochecknoglobals
+var readinessGauge = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "mapi_migration_readiness",
+		Help: "Number of Machine API resources by kind and migration readiness (Convertible, Lossy or Blocked).",
+	},
+	[]string{"kind", "readiness"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(readinessGauge)
+}
+
+// recordReadiness reports the number of resources of each kind and readiness in the given summary.
+// Every readiness of every kind is reported, so that a readiness with no resources left reads zero.
+func recordReadiness(summary Summary) {
+	counts := map[string]map[Readiness]int{}
+
+	for _, kind := range []string{"MachineSet", "Machine"} {
+		counts[kind] = map[Readiness]int{ReadinessConvertible: 0, ReadinessLossy: 0, ReadinessBlocked: 0}
+	}
+
+	for _, obj := range summary.Objects {
+		if _, ok := counts[obj.Kind]; !ok {
+			counts[obj.Kind] = map[Readiness]int{}
+		}
+
+		counts[obj.Kind][obj.Readiness]++
+	}
+
+	for kind, readinessCounts := range counts {
+		for readiness, count := range readinessCounts {
+			readinessGauge.WithLabelValues(kind, string(readiness)).Set(float64(count))
+		}
+	}
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package migrationreadiness
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	consts "github.com/openshift/cluster-capi-operator/pkg/controllers"
+	"github.com/openshift/cluster-capi-operator/pkg/conversion"
+	"github.com/openshift/cluster-capi-operator/pkg/conversion/report"
+	conversionutil "github.com/openshift/cluster-capi-operator/pkg/conversion/util"
+	"github.com/openshift/cluster-capi-operator/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1applyconfigs "k8s.io/client-go/applyconfigurations/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	controllerName string = "MigrationReadinessController"
+
+	// ReadinessConfigMapName is the name of the ConfigMap holding the migration readiness summary.
+	ReadinessConfigMapName = consts.MigrationReadinessConfigMapName
+
+	// ReadinessDataKey is the key of the migration readiness summary within the ConfigMap.
+	ReadinessDataKey = "readiness.json"
+
+	// defaultResyncPeriod is how often the readiness is recomputed when no resource changes.
+	defaultResyncPeriod = 10 * time.Minute
+)
+
+// MigrationReadinessReconciler dry-runs the conversion of every MAPI MachineSet and Machine to Cluster API,
+// and publishes whether each of them is convertible, lossy or blocked, in a ConfigMap and a metric.
+type MigrationReadinessReconciler struct {
+	client.Client
+
+	Infra         *configv1.Infrastructure
+	Platform      configv1.PlatformType
+	CAPINamespace string
+	MAPINamespace string
+
+	// ResyncPeriod is how often the readiness is recomputed when no resource changes.
+	ResyncPeriod time.Duration
+
+	// conversion holds the converters registered for the Platform.
+	conversion conversion.Platform
+}
+
+// SetupWithManager sets the MigrationReadinessReconciler controller up with the given manager.
+func (r *MigrationReadinessReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	platformConversion, err := conversion.PlatformFor(r.Platform)
+	if err != nil {
+		return fmt.Errorf("failed to get conversion for platform: %w", err)
+	}
+
+	r.conversion = platformConversion
+
+	// Allow the namespaces to be set externally for test purposes, when not set,
+	// default to the production namespaces.
+	if r.CAPINamespace == "" {
+		r.CAPINamespace = consts.DefaultManagedNamespace
+	}
+
+	if r.MAPINamespace == "" {
+		r.MAPINamespace = consts.DefaultMAPIManagedNamespace
+	}
+
+	if r.ResyncPeriod == 0 {
+		r.ResyncPeriod = defaultResyncPeriod
+	}
+
+	// Every change is reconciled as a single request, as the summary covers all resources.
+	toSummary := handler.EnqueueRequestsFromMapFunc(func(context.Context, client.Object) []reconcile.Request {
+		return []reconcile.Request{{NamespacedName: client.ObjectKey{Namespace: r.CAPINamespace, Name: ReadinessConfigMapName}}}
+	})
+
+	// Status updates do not change the result of the conversion.
+	specChanged := predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}, predicate.LabelChangedPredicate{})
+
+	if err := ctrl.NewControllerManagedBy(mgr).
+		Named(controllerName).
+		Watches(&machinev1beta1.MachineSet{}, toSummary, builder.WithPredicates(util.FilterNamespace(r.MAPINamespace), specChanged)).
+		Watches(&machinev1beta1.Machine{}, toSummary, builder.WithPredicates(util.FilterNamespace(r.MAPINamespace), specChanged)).
+		Complete(r); err != nil {
+		return fmt.Errorf("failed to create controller: %w", err)
+	}
+
+	r.Client = mgr.GetClient()
+
+	return nil
+}
+
+// Reconcile dry-runs the conversion of every MAPI MachineSet and Machine, and publishes the readiness summary.
+func (r *MigrationReadinessReconciler) Reconcile(ctx context.Context, _ reconcile.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	logger.V(1).Info("Reconciling migration readiness")
+	defer logger.V(1).Info("Finished reconciling migration readiness")
+
+	objects, err := r.dryRunConversions(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	summary := newSummary(objects)
+
+	if err := r.applySummary(ctx, summary); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	recordReadiness(summary)
+
+	logger.Info("Updated migration readiness", "convertible", summary.Convertible, "lossy", summary.Lossy, "blocked", summary.Blocked)
+
+	return ctrl.Result{RequeueAfter: r.ResyncPeriod}, nil
+}
+
+// dryRunConversions converts every MAPI MachineSet and Machine, and returns the readiness of each of them.
+func (r *MigrationReadinessReconciler) dryRunConversions(ctx context.Context) ([]ObjectReadiness, error) {
+	machineSets := &machinev1beta1.MachineSetList{}
+	if err := r.List(ctx, machineSets, client.InNamespace(r.MAPINamespace)); err != nil {
+		return nil, fmt.Errorf("failed to list MAPI machine sets: %w", err)
+	}
+
+	machines := &machinev1beta1.MachineList{}
+	if err := r.List(ctx, machines, client.InNamespace(r.MAPINamespace)); err != nil {
+		return nil, fmt.Errorf("failed to list MAPI machines: %w", err)
+	}
+
+	objects := make([]ObjectReadiness, 0, len(machineSets.Items)+len(machines.Items))
+
+	for i := range machineSets.Items {
+		conversionReport, err := r.convertMachineSet(&machineSets.Items[i])
+		objects = append(objects, newObjectReadiness("MachineSet", machineSets.Items[i].Name, conversionReport, err))
+	}
+
+	for i := range machines.Items {
+		_, _, conversionReport, err := r.conversion.FromMAPIMachine(&machines.Items[i], r.Infra).ToMachineAndInfrastructureMachine()
+		objects = append(objects, newObjectReadiness("Machine", machines.Items[i].Name, conversionReport, err))
+	}
+
+	return objects, nil
+}
+
+// convertMachineSet dry-runs the conversion of a MAPI MachineSet, to a MachineDeployment when enabled on the MachineSet.
+func (r *MigrationReadinessReconciler) convertMachineSet(mapiMachineSet *machinev1beta1.MachineSet) (report.Report, error) {
+	converter := r.conversion.FromMAPIMachineSet(mapiMachineSet, r.Infra)
+
+	if conversionutil.IsMachineDeploymentEnabled(mapiMachineSet.Annotations) {
+		_, _, conversionReport, err := converter.ToMachineDeploymentAndMachineTemplate()
+
+		return conversionReport, err //nolint:wrapcheck
+	}
+
+	_, _, conversionReport, err := converter.ToMachineSetAndMachineTemplate()
+
+	return conversionReport, err //nolint:wrapcheck
+}
+
+// applySummary writes the readiness summary to the readiness ConfigMap using a server side apply patch.
+func (r *MigrationReadinessReconciler) applySummary(ctx context.Context, summary Summary) error {
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal migration readiness summary: %w", err)
+	}
+
+	configMapAc := corev1applyconfigs.ConfigMap(ReadinessConfigMapName, r.CAPINamespace).
+		WithData(map[string]string{ReadinessDataKey: string(data)})
+
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: ReadinessConfigMapName, Namespace: r.CAPINamespace}}
+
+	if err := r.Patch(ctx, configMap, util.ApplyConfigPatch(configMapAc), client.ForceOwnership, client.FieldOwner("migration-readiness-controller")); err != nil {
+		return fmt.Errorf("failed to apply migration readiness ConfigMap: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package migrationreadiness
+
+import (
+	"sort"
+
+	"github.com/openshift/cluster-capi-operator/pkg/conversion/report"
+)
+
+// Readiness describes whether a MAPI resource can be migrated to Cluster API.
+type Readiness string
+
+const (
+	// ReadinessConvertible denotes a resource which converts without affecting any field.
+	ReadinessConvertible Readiness = "Convertible"
+
+	// ReadinessLossy denotes a resource which converts, but with fields dropped, altered or defaulted.
+	ReadinessLossy Readiness = "Lossy"
+
+	// ReadinessBlocked denotes a resource which can not be converted.
+	ReadinessBlocked Readiness = "Blocked"
+)
+
+// ObjectReadiness is the migration readiness of a single MAPI resource.
+type ObjectReadiness struct {
+	// Kind is the kind of the resource, MachineSet or Machine.
+	Kind string `json:"kind"`
+
+	// Name is the name of the resource.
+	Name string `json:"name"`
+
+	// Readiness is the migration readiness of the resource.
+	Readiness Readiness `json:"readiness"`
+
+	// Fields lists the fields affected by the conversion of the resource.
+	Fields report.Report `json:"fields,omitempty"`
+
+	// Error is the conversion error of the resource, when it is blocked.
+	Error string `json:"error,omitempty"`
+}
+
+// Summary is the migration readiness of every MAPI resource.
+type Summary struct {
+	// Convertible, Lossy and Blocked count the resources of each readiness.
+	Convertible int `json:"convertible"`
+	Lossy       int `json:"lossy"`
+	Blocked     int `json:"blocked"`
+
+	// Objects is the readiness of every resource, sorted by kind and name.
+	Objects []ObjectReadiness `json:"objects"`
+}
+
+// newObjectReadiness returns the readiness of a resource from the report and error of its dry-run conversion.
+func newObjectReadiness(kind, name string, conversionReport report.Report, conversionErr error) ObjectReadiness {
+	obj := ObjectReadiness{
+		Kind:      kind,
+		Name:      name,
+		Readiness: ReadinessConvertible,
+		Fields:    conversionReport,
+	}
+
+	switch {
+	case conversionErr != nil:
+		obj.Readiness = ReadinessBlocked
+		obj.Error = conversionErr.Error()
+	case conversionReport.HasSeverity(report.SeverityLossy), conversionReport.HasSeverity(report.SeverityDefaulted):
+		obj.Readiness = ReadinessLossy
+	}
+
+	return obj
+}
+
+// newSummary counts the resources of each readiness, and sorts them by kind and name.
+func newSummary(objects []ObjectReadiness) Summary {
+	summary := Summary{Objects: objects}
+
+	sort.Slice(summary.Objects, func(i, j int) bool {
+		if summary.Objects[i].Kind != summary.Objects[j].Kind {
+			return summary.Objects[i].Kind < summary.Objects[j].Kind
+		}
+
+		return summary.Objects[i].Name < summary.Objects[j].Name
+	})
+
+	for _, obj := range objects {
+		switch obj.Readiness {
+		case ReadinessConvertible:
+			summary.Convertible++
+		case ReadinessLossy:
+			summary.Lossy++
+		case ReadinessBlocked:
+			summary.Blocked++
+		}
+	}
+
+	return summary
+}
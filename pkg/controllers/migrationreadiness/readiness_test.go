@@ -0,0 +1,100 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package migrationreadiness
+
+import (
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/cluster-capi-operator/pkg/conversion/report"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+var _ = Describe("Migration readiness", func() {
+	fldPath := field.NewPath("spec", "providerSpec", "value")
+
+	lossyReport := report.New(report.MAPIToCAPI, report.Report{
+		report.Lossy(fldPath.Child("tenancy"), "host", report.ReasonValueIgnored, "tenancy is ignored"),
+	}, nil)
+
+	blockingErrs := field.ErrorList{field.Invalid(fldPath.Child("loadBalancers"), []string{"lb"}, "loadBalancers are not supported")}
+	blockedReport := report.New(report.MAPIToCAPI, nil, blockingErrs)
+
+	DescribeTable("should classify the readiness of a resource from its conversion",
+		func(conversionReport report.Report, conversionErr error, expected Readiness) {
+			obj := newObjectReadiness("MachineSet", "foo", conversionReport, conversionErr)
+
+			Expect(obj.Readiness).To(Equal(expected))
+			Expect(obj.Fields).To(Equal(conversionReport))
+		},
+		Entry("with no affected fields", report.Report{}, nil, ReadinessConvertible),
+		Entry("with lossy fields", lossyReport, nil, ReadinessLossy),
+		Entry("with defaulted fields", report.New(report.MAPIToCAPI, report.Report{
+			report.Defaulted(fldPath.Child("deleteOnTermination"), false, report.ReasonValueOverridden, "deleteOnTermination must be true"),
+		}, nil), nil, ReadinessLossy),
+		Entry("with a conversion error", blockedReport, blockingErrs.ToAggregate(), ReadinessBlocked),
+	)
+
+	It("should report the error of a blocked resource", func() {
+		obj := newObjectReadiness("Machine", "foo", nil, errors.New("conversion failed"))
+
+		Expect(obj.Error).To(Equal("conversion failed"))
+	})
+
+	Context("with a summary", func() {
+		summary := newSummary([]ObjectReadiness{
+			newObjectReadiness("MachineSet", "b", blockedReport, blockingErrs.ToAggregate()),
+			newObjectReadiness("Machine", "c", lossyReport, nil),
+			newObjectReadiness("MachineSet", "a", nil, nil),
+		})
+
+		It("should count the resources of each readiness", func() {
+			Expect(summary.Convertible).To(Equal(1))
+			Expect(summary.Lossy).To(Equal(1))
+			Expect(summary.Blocked).To(Equal(1))
+		})
+
+		It("should sort the resources by kind and name", func() {
+			Expect(summary.Objects).To(HaveExactElements(
+				HaveField("Name", "c"),
+				HaveField("Name", "a"),
+				HaveField("Name", "b"),
+			))
+		})
+
+		It("should report the number of resources of each kind and readiness as a metric", func() {
+			recordReadiness(newSummary([]ObjectReadiness{newObjectReadiness("Machine", "stale", blockedReport, blockingErrs.ToAggregate())}))
+			recordReadiness(summary)
+
+			expected := `
+# HELP mapi_migration_readiness Number of Machine API resources by kind and migration readiness (Convertible, Lossy or Blocked).
+# TYPE mapi_migration_readiness gauge
+mapi_migration_readiness{kind="Machine",readiness="Blocked"} 0
+mapi_migration_readiness{kind="Machine",readiness="Convertible"} 0
+mapi_migration_readiness{kind="Machine",readiness="Lossy"} 1
+mapi_migration_readiness{kind="MachineSet",readiness="Blocked"} 1
+mapi_migration_readiness{kind="MachineSet",readiness="Convertible"} 1
+mapi_migration_readiness{kind="MachineSet",readiness="Lossy"} 0
+`
+			Expect(testutil.CollectAndCompare(readinessGauge, strings.NewReader(expected))).To(Succeed())
+		})
+	})
+})
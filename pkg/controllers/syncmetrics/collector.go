@@ -0,0 +1,142 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package syncmetrics
+
+import (
+	"context"
+	"time"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	consts "github.com/openshift/cluster-capi-operator/pkg/controllers"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// collectTimeout bounds the time spent listing resources on each scrape.
+const collectTimeout = 10 * time.Second
+
+//nolint:gochecknoglobals
+var (
+	objectsDesc = prometheus.NewDesc(
+		"mapi_sync_objects",
+		"Number of MAPI resources, by kind and status.authoritativeAPI.",
+		[]string{"kind", "authoritative_api"}, nil,
+	)
+
+	objectsNotSynchronizedDesc = prometheus.NewDesc(
+		"mapi_sync_objects_not_synchronized",
+		"Number of MAPI resources whose Synchronized condition is not True, by kind.",
+		[]string{"kind"}, nil,
+	)
+)
+
+// objectsCollector counts the MAPI MachineSets and Machines from the cache on each scrape,
+// so that deleted resources are never reported.
+type objectsCollector struct {
+	reader    client.Reader
+	namespace string
+}
+
+// NewObjectsCollector returns a collector counting the MAPI MachineSets and Machines of the given namespace
+// by authoritative API, and the ones which are not synchronized.
+func NewObjectsCollector(reader client.Reader, namespace string) prometheus.Collector {
+	return &objectsCollector{reader: reader, namespace: namespace}
+}
+
+// Describe implements prometheus.Collector.
+func (c *objectsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- objectsDesc
+	ch <- objectsNotSynchronizedDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *objectsCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+	defer cancel()
+
+	machineSets := &machinev1beta1.MachineSetList{}
+	if err := c.reader.List(ctx, machineSets, client.InNamespace(c.namespace)); err != nil {
+		ch <- prometheus.NewInvalidMetric(objectsDesc, err)
+
+		return
+	}
+
+	machines := &machinev1beta1.MachineList{}
+	if err := c.reader.List(ctx, machines, client.InNamespace(c.namespace)); err != nil {
+		ch <- prometheus.NewInvalidMetric(objectsDesc, err)
+
+		return
+	}
+
+	machineSetsByAuthority := map[machinev1beta1.MachineAuthority]int{}
+	machineSetsNotSynchronized := 0
+
+	for _, ms := range machineSets.Items {
+		machineSetsByAuthority[ms.Status.AuthoritativeAPI]++
+
+		if !isSynchronized(ms.Status.Conditions) {
+			machineSetsNotSynchronized++
+		}
+	}
+
+	machinesByAuthority := map[machinev1beta1.MachineAuthority]int{}
+	machinesNotSynchronized := 0
+
+	for _, m := range machines.Items {
+		machinesByAuthority[m.Status.AuthoritativeAPI]++
+
+		if !isSynchronized(m.Status.Conditions) {
+			machinesNotSynchronized++
+		}
+	}
+
+	collectByAuthority(ch, "MachineSet", machineSetsByAuthority)
+	collectByAuthority(ch, "Machine", machinesByAuthority)
+
+	ch <- prometheus.MustNewConstMetric(objectsNotSynchronizedDesc, prometheus.GaugeValue, float64(machineSetsNotSynchronized), "MachineSet")
+	ch <- prometheus.MustNewConstMetric(objectsNotSynchronizedDesc, prometheus.GaugeValue, float64(machinesNotSynchronized), "Machine")
+}
+
+// collectByAuthority sends the number of resources of every authoritative API, including the ones without resources.
+func collectByAuthority(ch chan<- prometheus.Metric, kind string, counts map[machinev1beta1.MachineAuthority]int) {
+	for _, authority := range []machinev1beta1.MachineAuthority{
+		machinev1beta1.MachineAuthorityMachineAPI,
+		machinev1beta1.MachineAuthorityClusterAPI,
+		machinev1beta1.MachineAuthorityMigrating,
+	} {
+		if _, ok := counts[authority]; !ok {
+			counts[authority] = 0
+		}
+	}
+
+	// Resources without a status.authoritativeAPI yet, or with an unexpected value, are reported as is.
+	for authority, count := range counts {
+		ch <- prometheus.MustNewConstMetric(objectsDesc, prometheus.GaugeValue, float64(count), kind, string(authority))
+	}
+}
+
+// isSynchronized returns true when the Synchronized condition is True.
+func isSynchronized(conditions []machinev1beta1.Condition) bool {
+	for _, condition := range conditions {
+		if condition.Type == consts.SynchronizedCondition {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
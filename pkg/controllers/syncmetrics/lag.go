@@ -0,0 +1,104 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package syncmetrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// lagKey identifies a resource tracked by a lagTracker.
+type lagKey struct {
+	kind string
+	name string
+}
+
+// lagTracker records when a resource was first seen with an unsynchronized generation,
+// and observes the elapsed time once the resource is synchronized.
+// The tracking is kept in memory, so the lag of changes made before a restart is measured from the restart.
+type lagTracker struct {
+	mu sync.Mutex
+
+	now      func() time.Time
+	observer *prometheus.HistogramVec
+	pending  map[lagKey]time.Time
+}
+
+// defaultLagTracker feeds the synchronization lag metric.
+//
+//nolint:gochecknoglobals
+var defaultLagTracker = newLagTracker(synchronizationLagSeconds, time.Now)
+
+// newLagTracker returns a lagTracker observing the lag with the given histogram.
+func newLagTracker(observer *prometheus.HistogramVec, now func() time.Time) *lagTracker {
+	return &lagTracker{
+		now:      now,
+		observer: observer,
+		pending:  map[lagKey]time.Time{},
+	}
+}
+
+// ObserveGeneration records that a resource was reconciled with the given generation of the authoritative resource.
+// The synchronization lag of the resource starts when its generation differs from its synchronized generation.
+func ObserveGeneration(kind, name string, generation, synchronizedGeneration int64) {
+	defaultLagTracker.observeGeneration(kind, name, generation, synchronizedGeneration)
+}
+
+// ObserveSynchronized records that a resource was synchronized, and observes its synchronization lag.
+func ObserveSynchronized(kind, name string) {
+	defaultLagTracker.observeSynchronized(kind, name)
+}
+
+// Forget stops tracking the synchronization lag of a deleted resource.
+func Forget(kind, name string) {
+	defaultLagTracker.forget(kind, name)
+}
+
+func (t *lagTracker) observeGeneration(kind, name string, generation, synchronizedGeneration int64) {
+	if generation == synchronizedGeneration {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// The lag is measured from the first unsynchronized generation, so that
+	// successive changes do not hide a resource that never catches up.
+	key := lagKey{kind: kind, name: name}
+	if _, ok := t.pending[key]; !ok {
+		t.pending[key] = t.now()
+	}
+}
+
+func (t *lagTracker) observeSynchronized(kind, name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := lagKey{kind: kind, name: name}
+	if since, ok := t.pending[key]; ok {
+		t.observer.WithLabelValues(kind).Observe(t.now().Sub(since).Seconds())
+		delete(t.pending, key)
+	}
+}
+
+func (t *lagTracker) forget(kind, name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.pending, lagKey{kind: kind, name: name})
+}
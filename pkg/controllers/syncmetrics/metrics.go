@@ -0,0 +1,109 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package syncmetrics
+
+import (
+	"regexp"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/cluster-capi-operator/pkg/conversion/report"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	// ResultLossless denotes a conversion which did not report any field.
+	ResultLossless = "lossless"
+
+	// ResultLossy denotes a conversion which dropped, altered or defaulted at least one field.
+	ResultLossy = "lossy"
+
+	// ResultFailed denotes a conversion which failed.
+	ResultFailed = "failed"
+)
+
+// conversionsTotal counts the conversions made by the sync controllers.
+//
+//nolint:gochecknoglobals
+var conversionsTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "mapi_sync_conversions_total",
+		Help: "Number of conversions made by the sync controllers, by platform, direction and result (lossless, lossy or failed).",
+	},
+	[]string{"platform", "direction", "result"},
+)
+
+// conversionWarningsTotal counts the fields dropped, altered or defaulted by the conversions of the sync controllers.
+// The list indexes and map keys of the fields are normalized, to bound the cardinality of the field label.
+//
+//nolint:gochecknoglobals
+var conversionWarningsTotal = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "mapi_sync_conversion_warnings_total",
+		Help: "Number of fields dropped, altered or defaulted by the conversions of the sync controllers, by platform, direction and field.",
+	},
+	[]string{"platform", "direction", "field"},
+)
+
+// fieldSubscript matches the list indexes and map keys of a field path, such as [0] or [key].
+var fieldSubscript = regexp.MustCompile(`\[[^\]]*\]`)
+
+// synchronizationLagSeconds observes the time between a generation change of the authoritative
+// resource and the synchronized generation of the MAPI resource catching up.
+//
+//nolint:gochecknoglobals
+var synchronizationLagSeconds = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "mapi_sync_synchronization_lag_seconds",
+		Help:    "Time between a generation change of the authoritative resource and its synchronization, by kind.",
+		Buckets: prometheus.ExponentialBuckets(0.5, 2, 12),
+	},
+	[]string{"kind"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(conversionsTotal, conversionWarningsTotal, synchronizationLagSeconds)
+}
+
+// RecordConversion counts a conversion made by a sync controller, and the fields reported by it.
+func RecordConversion(platform configv1.PlatformType, direction report.Direction, conversionReport report.Report, conversionErr error) {
+	conversionsTotal.WithLabelValues(string(platform), string(direction), conversionResult(conversionReport, conversionErr)).Inc()
+
+	for _, entry := range conversionReport {
+		if entry.Severity != report.SeverityError {
+			conversionWarningsTotal.WithLabelValues(string(platform), string(direction), normalizeField(entry.Field)).Inc()
+		}
+	}
+}
+
+// conversionResult returns the result of a conversion from its report and error.
+func conversionResult(conversionReport report.Report, conversionErr error) string {
+	switch {
+	case conversionErr != nil, conversionReport.HasSeverity(report.SeverityError):
+		return ResultFailed
+	case len(conversionReport) > 0:
+		return ResultLossy
+	default:
+		return ResultLossless
+	}
+}
+
+// normalizeField replaces the list indexes and map keys of a field path with [*],
+// so that the fields of every element of a list or map are counted together.
+func normalizeField(fieldPath string) string {
+	return fieldSubscript.ReplaceAllString(fieldPath, "[*]")
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package syncmetrics
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	consts "github.com/openshift/cluster-capi-operator/pkg/controllers"
+	"github.com/openshift/cluster-capi-operator/pkg/conversion/report"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Sync metrics", func() {
+	fldPath := field.NewPath("spec", "providerSpec", "value")
+
+	lossyReport := report.New(report.MAPIToCAPI, report.Report{
+		report.Lossy(fldPath.Child("tenancy"), "host", report.ReasonValueIgnored, "tenancy is ignored"),
+	}, nil)
+
+	DescribeTable("should classify the result of a conversion",
+		func(conversionReport report.Report, conversionErr error, expected string) {
+			Expect(conversionResult(conversionReport, conversionErr)).To(Equal(expected))
+		},
+		Entry("with no reported fields", report.Report{}, nil, ResultLossless),
+		Entry("with lossy fields", lossyReport, nil, ResultLossy),
+		Entry("with a conversion error", nil, errors.New("conversion failed"), ResultFailed),
+		Entry("with error entries", report.New(report.MAPIToCAPI, nil, field.ErrorList{field.Invalid(fldPath, "", "invalid")}), nil, ResultFailed),
+	)
+
+	It("should count conversions and their warnings by field", func() {
+		RecordConversion(configv1.AWSPlatformType, report.MAPIToCAPI, lossyReport, nil)
+
+		Expect(testutil.ToFloat64(conversionsTotal.WithLabelValues("AWS", "MAPIToCAPI", ResultLossy))).To(BeNumerically(">=", 1))
+		Expect(testutil.ToFloat64(conversionWarningsTotal.WithLabelValues("AWS", "MAPIToCAPI", "spec.providerSpec.value.tenancy"))).To(BeNumerically(">=", 1))
+	})
+
+	DescribeTable("should normalize the list indexes and map keys of the warning fields",
+		func(fieldPath, expected string) {
+			Expect(normalizeField(fieldPath)).To(Equal(expected))
+		},
+		Entry("without any index", "spec.providerSpec.value.tenancy", "spec.providerSpec.value.tenancy"),
+		Entry("with a list index", "spec.providerSpec.value.blockDevices[1].ebs.kmsKey", "spec.providerSpec.value.blockDevices[*].ebs.kmsKey"),
+		Entry("with nested list indexes", "spec.networkInterfaces[0].addresses[12]", "spec.networkInterfaces[*].addresses[*]"),
+		Entry("with a map key", "spec.additionalTags[owner]", "spec.additionalTags[*]"),
+	)
+
+	Context("synchronization lag", func() {
+		var (
+			now     time.Time
+			lag     *prometheus.HistogramVec
+			tracker *lagTracker
+		)
+
+		BeforeEach(func() {
+			now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			lag = prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "test_lag_seconds"}, []string{"kind"})
+			tracker = newLagTracker(lag, func() time.Time { return now })
+		})
+
+		It("should observe the time from the first unsynchronized generation", func() {
+			tracker.observeGeneration("MachineSet", "foo", 2, 1)
+			now = now.Add(5 * time.Second)
+			tracker.observeGeneration("MachineSet", "foo", 3, 1)
+			now = now.Add(5 * time.Second)
+			tracker.observeSynchronized("MachineSet", "foo")
+
+			Expect(testutil.CollectAndCount(lag)).To(Equal(1))
+			Expect(testutil.CollectAndCompare(lag, strings.NewReader(`
+# HELP test_lag_seconds 
+# TYPE test_lag_seconds histogram
+test_lag_seconds_bucket{kind="MachineSet",le="0.005"} 0
+test_lag_seconds_bucket{kind="MachineSet",le="0.01"} 0
+test_lag_seconds_bucket{kind="MachineSet",le="0.025"} 0
+test_lag_seconds_bucket{kind="MachineSet",le="0.05"} 0
+test_lag_seconds_bucket{kind="MachineSet",le="0.1"} 0
+test_lag_seconds_bucket{kind="MachineSet",le="0.25"} 0
+test_lag_seconds_bucket{kind="MachineSet",le="0.5"} 0
+test_lag_seconds_bucket{kind="MachineSet",le="1"} 0
+test_lag_seconds_bucket{kind="MachineSet",le="2.5"} 0
+test_lag_seconds_bucket{kind="MachineSet",le="5"} 0
+test_lag_seconds_bucket{kind="MachineSet",le="10"} 1
+test_lag_seconds_bucket{kind="MachineSet",le="+Inf"} 1
+test_lag_seconds_sum{kind="MachineSet"} 10
+test_lag_seconds_count{kind="MachineSet"} 1
+`))).To(Succeed())
+		})
+
+		It("should not observe resources which were already synchronized", func() {
+			tracker.observeGeneration("MachineSet", "foo", 2, 2)
+			tracker.observeSynchronized("MachineSet", "foo")
+
+			Expect(testutil.CollectAndCount(lag)).To(Equal(0))
+		})
+
+		It("should forget deleted resources", func() {
+			tracker.observeGeneration("MachineSet", "foo", 2, 1)
+			tracker.forget("MachineSet", "foo")
+			tracker.observeSynchronized("MachineSet", "foo")
+
+			Expect(testutil.CollectAndCount(lag)).To(Equal(0))
+		})
+	})
+
+	It("should count the resources by authoritative API and the unsynchronized resources", func() {
+		scheme := runtime.NewScheme()
+		Expect(machinev1beta1.AddToScheme(scheme)).To(Succeed())
+
+		synchronized := machinev1beta1.Condition{Type: consts.SynchronizedCondition, Status: corev1.ConditionTrue}
+
+		reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&machinev1beta1.MachineSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "mapi"},
+				Status:     machinev1beta1.MachineSetStatus{AuthoritativeAPI: machinev1beta1.MachineAuthorityMachineAPI, Conditions: []machinev1beta1.Condition{synchronized}},
+			},
+			&machinev1beta1.MachineSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "mapi"},
+				Status:     machinev1beta1.MachineSetStatus{AuthoritativeAPI: machinev1beta1.MachineAuthorityClusterAPI},
+			},
+			&machinev1beta1.Machine{
+				ObjectMeta: metav1.ObjectMeta{Name: "c", Namespace: "mapi"},
+				Status:     machinev1beta1.MachineStatus{AuthoritativeAPI: machinev1beta1.MachineAuthorityMigrating},
+			},
+			&machinev1beta1.Machine{
+				ObjectMeta: metav1.ObjectMeta{Name: "e", Namespace: "mapi"},
+				Status:     machinev1beta1.MachineStatus{AuthoritativeAPI: machinev1beta1.MachineAuthorityMachineAPI, Conditions: []machinev1beta1.Condition{synchronized}},
+			},
+			&machinev1beta1.Machine{
+				ObjectMeta: metav1.ObjectMeta{Name: "d", Namespace: "other"},
+				Status:     machinev1beta1.MachineStatus{AuthoritativeAPI: machinev1beta1.MachineAuthorityMachineAPI},
+			},
+		).Build()
+
+		Expect(testutil.CollectAndCompare(NewObjectsCollector(reader, "mapi"), strings.NewReader(`
+# HELP mapi_sync_objects Number of MAPI resources, by kind and status.authoritativeAPI.
+# TYPE mapi_sync_objects gauge
+mapi_sync_objects{authoritative_api="ClusterAPI",kind="Machine"} 0
+mapi_sync_objects{authoritative_api="ClusterAPI",kind="MachineSet"} 1
+mapi_sync_objects{authoritative_api="MachineAPI",kind="Machine"} 1
+mapi_sync_objects{authoritative_api="MachineAPI",kind="MachineSet"} 1
+mapi_sync_objects{authoritative_api="Migrating",kind="Machine"} 1
+mapi_sync_objects{authoritative_api="Migrating",kind="MachineSet"} 0
+# HELP mapi_sync_objects_not_synchronized Number of MAPI resources whose Synchronized condition is not True, by kind.
+# TYPE mapi_sync_objects_not_synchronized gauge
+mapi_sync_objects_not_synchronized{kind="Machine"} 1
+mapi_sync_objects_not_synchronized{kind="MachineSet"} 1
+`))).To(Succeed())
+	})
+})
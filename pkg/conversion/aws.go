@@ -0,0 +1,114 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package conversion
+
+import (
+	"fmt"
+	"reflect"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/cluster-capi-operator/pkg/conversion/capi2mapi"
+	"github.com/openshift/cluster-capi-operator/pkg/conversion/mapi2capi"
+
+	capav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	capiv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// awsPlatform registers the AWS conversions.
+//
+//nolint:gochecknoglobals
+var awsPlatform = Platform{
+	Type:                    configv1.AWSPlatformType,
+	AddToScheme:             capav1.AddToScheme,
+	NewInfraMachine:         func() client.Object { return &capav1.AWSMachine{} },
+	NewInfraMachineTemplate: func() client.Object { return &capav1.AWSMachineTemplate{} },
+	NewInfraCluster:         func() client.Object { return &capav1.AWSCluster{} },
+	UnsupportedFields:       capi2mapi.AWSUnsupportedFields,
+	FromMAPIMachine:         mapi2capi.FromAWSMachineAndInfra,
+	FromMAPIMachineSet:      mapi2capi.FromAWSMachineSetAndInfra,
+
+	FromCAPIMachine: func(machine *capiv1.Machine, infraMachine, infraCluster client.Object) (capi2mapi.MachineAndInfrastructureMachine, error) {
+		awsMachine, ok := infraMachine.(*capav1.AWSMachine)
+		if !ok {
+			return nil, fmt.Errorf("%w, expected AWSMachine, got %T", errUnexpectedInfraMachineType, infraMachine)
+		}
+
+		awsCluster, err := toAWSCluster(infraCluster)
+		if err != nil {
+			return nil, err
+		}
+
+		return capi2mapi.FromMachineAndAWSMachineAndAWSCluster(machine, awsMachine, awsCluster), nil
+	},
+
+	FromCAPIMachineSet: func(machineSet *capiv1.MachineSet, infraMachineTemplate, infraCluster client.Object) (capi2mapi.MachineSetAndMachineTemplate, error) {
+		awsMachineTemplate, awsCluster, err := toAWSMachineTemplateAndAWSCluster(infraMachineTemplate, infraCluster)
+		if err != nil {
+			return nil, err
+		}
+
+		return capi2mapi.FromMachineSetAndAWSMachineTemplateAndAWSCluster(machineSet, awsMachineTemplate, awsCluster), nil
+	},
+
+	FromCAPIMachineDeployment: func(machineDeployment *capiv1.MachineDeployment, infraMachineTemplate, infraCluster client.Object) (capi2mapi.MachineSetAndMachineTemplate, error) {
+		awsMachineTemplate, awsCluster, err := toAWSMachineTemplateAndAWSCluster(infraMachineTemplate, infraCluster)
+		if err != nil {
+			return nil, err
+		}
+
+		return capi2mapi.FromMachineDeploymentAndAWSMachineTemplateAndAWSCluster(machineDeployment, awsMachineTemplate, awsCluster), nil
+	},
+
+	InfraMachineTemplateIsEqual: func(a, b client.Object) (bool, error) {
+		awsMachineTemplateA, ok := a.(*capav1.AWSMachineTemplate)
+		if !ok {
+			return false, fmt.Errorf("%w, expected AWSMachineTemplate, got %T", errUnexpectedInfraMachineTemplateType, a)
+		}
+
+		awsMachineTemplateB, ok := b.(*capav1.AWSMachineTemplate)
+		if !ok {
+			return false, fmt.Errorf("%w, expected AWSMachineTemplate, got %T", errUnexpectedInfraMachineTemplateType, b)
+		}
+
+		return reflect.DeepEqual(awsMachineTemplateA.Spec, awsMachineTemplateB.Spec), nil
+	},
+}
+
+// toAWSMachineTemplateAndAWSCluster asserts the InfraMachineTemplate and InfraCluster into their AWS types.
+func toAWSMachineTemplateAndAWSCluster(infraMachineTemplate, infraCluster client.Object) (*capav1.AWSMachineTemplate, *capav1.AWSCluster, error) {
+	awsMachineTemplate, ok := infraMachineTemplate.(*capav1.AWSMachineTemplate)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w, expected AWSMachineTemplate, got %T", errUnexpectedInfraMachineTemplateType, infraMachineTemplate)
+	}
+
+	awsCluster, err := toAWSCluster(infraCluster)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return awsMachineTemplate, awsCluster, nil
+}
+
+// toAWSCluster asserts the InfraCluster into an AWSCluster.
+func toAWSCluster(infraCluster client.Object) (*capav1.AWSCluster, error) {
+	awsCluster, ok := infraCluster.(*capav1.AWSCluster)
+	if !ok {
+		return nil, fmt.Errorf("%w, expected AWSCluster, got %T", errUnexpectedInfraClusterType, infraCluster)
+	}
+
+	return awsCluster, nil
+}
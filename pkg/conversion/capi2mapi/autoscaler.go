@@ -0,0 +1,120 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package capi2mapi
+
+import (
+	"strconv"
+
+	"github.com/openshift/cluster-capi-operator/pkg/conversion/report"
+	conversionutil "github.com/openshift/cluster-capi-operator/pkg/conversion/util"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const mebibyte = 1024 * 1024
+
+// convertCAPIMachineSetAnnotationsToMAPI translates the autoscaler node group size and scale-from-zero capacity
+// annotations of a CAPI MachineSet into their MAPI equivalents. All other annotations are copied as is.
+func convertCAPIMachineSetAnnotationsToMAPI(fldPath *field.Path, capiAnnotations map[string]string) (map[string]string, report.Report, field.ErrorList) {
+	if capiAnnotations == nil {
+		return nil, nil, nil
+	}
+
+	var (
+		errs     field.ErrorList
+		warnings report.Report
+	)
+
+	capiToMAPI := map[string]string{}
+	for mapiKey, capiKey := range conversionutil.MAPIToCAPIAutoscalerAnnotations() {
+		capiToMAPI[capiKey] = mapiKey
+	}
+
+	mapiAnnotations := make(map[string]string, len(capiAnnotations))
+
+	for k, v := range capiAnnotations {
+		if mapiKey, ok := capiToMAPI[k]; ok {
+			mapiAnnotations[mapiKey] = v
+			continue
+		}
+
+		switch k {
+		case conversionutil.CAPICPUCapacityAnnotation:
+			cpu, warn, err := convertCPUCapacityToMAPI(fldPath.Key(k), v)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			warnings = append(warnings, warn...)
+			mapiAnnotations[conversionutil.MAPICPUAnnotation] = cpu
+		case conversionutil.CAPIMemoryCapacityAnnotation:
+			memory, warn, err := convertMemoryCapacityToMAPI(fldPath.Key(k), v)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			warnings = append(warnings, warn...)
+			mapiAnnotations[conversionutil.MAPIMemoryAnnotation] = memory
+		case conversionutil.CAPIGPUCountCapacityAnnotation:
+			mapiAnnotations[conversionutil.MAPIGPUAnnotation] = v
+		case conversionutil.CAPIGPUTypeCapacityAnnotation:
+			// MAPI has no way to express the GPU type, the OpenShift autoscaler always assumes NVIDIA GPUs.
+			if v != conversionutil.DefaultGPUType {
+				warnings = append(warnings, report.Lossy(fldPath.Key(k), v, report.ReasonValueIgnored, "gpu types other than "+conversionutil.DefaultGPUType+" can not be represented in MAPI, ignoring"))
+			}
+		default:
+			mapiAnnotations[k] = v
+		}
+	}
+
+	return mapiAnnotations, warnings, errs
+}
+
+// convertCPUCapacityToMAPI converts the CAPI CPU capacity, a resource quantity, into the MAPI number of vCPUs.
+func convertCPUCapacityToMAPI(fldPath *field.Path, v string) (string, report.Report, *field.Error) {
+	cpu, err := resource.ParseQuantity(v)
+	if err != nil {
+		return "", nil, field.Invalid(fldPath, v, "cpu capacity annotation must be a valid resource quantity")
+	}
+
+	var warnings report.Report
+
+	// Rounding down could advertise less than a CPU, which the autoscaler would never fit a Pod on.
+	if cpu.MilliValue()%1000 != 0 {
+		warnings = append(warnings, report.Lossy(fldPath, v, report.ReasonValueRounded, "cpu capacity is not a whole number of CPUs, rounding up"))
+	}
+
+	return strconv.FormatInt(cpu.Value(), 10), warnings, nil
+}
+
+// convertMemoryCapacityToMAPI converts the CAPI memory capacity, a resource quantity, into the MAPI number of MiB.
+func convertMemoryCapacityToMAPI(fldPath *field.Path, v string) (string, report.Report, *field.Error) {
+	memory, err := resource.ParseQuantity(v)
+	if err != nil {
+		return "", nil, field.Invalid(fldPath, v, "memory capacity annotation must be a valid resource quantity")
+	}
+
+	var warnings report.Report
+
+	if memory.Value()%mebibyte != 0 {
+		warnings = append(warnings, report.Lossy(fldPath, v, report.ReasonValueRounded, "memory capacity is not a whole number of MiB, rounding down"))
+	}
+
+	return strconv.FormatInt(memory.Value()/mebibyte, 10), warnings, nil
+}
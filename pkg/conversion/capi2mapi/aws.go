@@ -22,6 +22,8 @@ import (
 	"strings"
 
 	mapiv1 "github.com/openshift/api/machine/v1beta1"
+	"github.com/openshift/cluster-capi-operator/pkg/conversion/report"
+	"github.com/openshift/cluster-capi-operator/pkg/conversion/unsupported"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -34,8 +36,9 @@ import (
 )
 
 var (
-	errCAPIMachineAWSMachineAWSClusterCannotBeNil            = errors.New("provided Machine, AWSMachine and AWSCluster can not be nil")
-	errCAPIMachineSetAWSMachineTemplateAWSClusterCannotBeNil = errors.New("provided MachineSet, AWSMachineTemplate and AWSCluster can not be nil")
+	errCAPIMachineAWSMachineAWSClusterCannotBeNil                   = errors.New("provided Machine, AWSMachine and AWSCluster can not be nil")
+	errCAPIMachineSetAWSMachineTemplateAWSClusterCannotBeNil        = errors.New("provided MachineSet, AWSMachineTemplate and AWSCluster can not be nil")
+	errCAPIMachineDeploymentAWSMachineTemplateAWSClusterCannotBeNil = errors.New("provided MachineDeployment, AWSMachineTemplate and AWSCluster can not be nil")
 )
 
 const (
@@ -58,6 +61,13 @@ type machineSetAndAWSMachineTemplateAndAWSCluster struct {
 	*machineAndAWSMachineAndAWSCluster
 }
 
+// machineDeploymentAndAWSMachineTemplateAndAWSCluster stores the details of a Cluster API MachineDeployment and AWSMachineTemplate and AWSCluster.
+type machineDeploymentAndAWSMachineTemplateAndAWSCluster struct {
+	machineDeployment *capiv1.MachineDeployment
+	template          *capav1.AWSMachineTemplate
+	awsCluster        *capav1.AWSCluster
+}
+
 // FromMachineAndAWSMachineAndAWSCluster wraps a CAPI Machine and CAPA AWSMachine and CAPA AWSCluster into a capi2mapi MachineAndInfrastructureMachine.
 func FromMachineAndAWSMachineAndAWSCluster(m *capiv1.Machine, am *capav1.AWSMachine, ac *capav1.AWSCluster) MachineAndInfrastructureMachine {
 	return &machineAndAWSMachineAndAWSCluster{machine: m, awsMachine: am, awsCluster: ac}
@@ -85,12 +95,21 @@ func FromMachineSetAndAWSMachineTemplateAndAWSCluster(ms *capiv1.MachineSet, mts
 	}
 }
 
+// FromMachineDeploymentAndAWSMachineTemplateAndAWSCluster wraps a CAPI MachineDeployment and CAPA AWSMachineTemplate and CAPA AWSCluster into a capi2mapi MachineSetAndMachineTemplate.
+func FromMachineDeploymentAndAWSMachineTemplateAndAWSCluster(md *capiv1.MachineDeployment, mts *capav1.AWSMachineTemplate, ac *capav1.AWSCluster) MachineSetAndMachineTemplate {
+	return &machineDeploymentAndAWSMachineTemplateAndAWSCluster{
+		machineDeployment: md,
+		template:          mts,
+		awsCluster:        ac,
+	}
+}
+
 // toProviderSpec converts a capi2mapi MachineAndAWSMachineTemplateAndAWSCluster into a MAPI AWSMachineProviderConfig.
 //
 //nolint:funlen
-func (m machineAndAWSMachineAndAWSCluster) toProviderSpec() (*mapiv1.AWSMachineProviderConfig, []string, field.ErrorList) {
+func (m machineAndAWSMachineAndAWSCluster) toProviderSpec() (*mapiv1.AWSMachineProviderConfig, report.Report, field.ErrorList) {
 	var (
-		warnings []string
+		warnings report.Report
 		errors   field.ErrorList
 	)
 
@@ -173,14 +192,14 @@ func (m machineAndAWSMachineAndAWSCluster) toProviderSpec() (*mapiv1.AWSMachineP
 }
 
 // ToMachine converts a capi2mapi MachineAndAWSMachineTemplate into a MAPI Machine.
-func (m machineAndAWSMachineAndAWSCluster) ToMachine() (*mapiv1.Machine, []string, error) {
+func (m machineAndAWSMachineAndAWSCluster) ToMachine() (*mapiv1.Machine, report.Report, error) {
 	if m.machine == nil || m.awsMachine == nil || m.awsCluster == nil {
 		return nil, nil, errCAPIMachineAWSMachineAWSClusterCannotBeNil
 	}
 
 	var (
 		errors   field.ErrorList
-		warnings []string
+		warnings report.Report
 	)
 
 	mapaSpec, warn, err := m.toProviderSpec()
@@ -202,36 +221,40 @@ func (m machineAndAWSMachineAndAWSCluster) ToMachine() (*mapiv1.Machine, []strin
 
 	mapiMachine.Spec.ProviderSpec.Value = awsRawExt
 
+	conversionReport := report.New(report.CAPIToMAPI, warnings, errors)
+
 	if len(errors) > 0 {
-		return nil, warnings, errors.ToAggregate()
+		return nil, conversionReport, errors.ToAggregate()
 	}
 
-	return mapiMachine, warnings, nil
+	return mapiMachine, conversionReport, nil
 }
 
 // ToMachineSet converts a capi2mapi MachineAndAWSMachineTemplate into a MAPI MachineSet.
-func (m machineSetAndAWSMachineTemplateAndAWSCluster) ToMachineSet() (*mapiv1.MachineSet, []string, error) {
+func (m machineSetAndAWSMachineTemplateAndAWSCluster) ToMachineSet() (*mapiv1.MachineSet, report.Report, error) {
 	if m.machineSet == nil || m.template == nil || m.awsCluster == nil || m.machineAndAWSMachineAndAWSCluster == nil {
 		return nil, nil, errCAPIMachineSetAWSMachineTemplateAWSClusterCannotBeNil
 	}
 
-	var (
-		errors   []error
-		warnings []string
-	)
+	var errors []error
 
 	// Run the full ToMachine conversion so that we can check for
 	// any Machine level conversion errors in the spec translation.
-	mapaMachine, warn, err := m.ToMachine()
+	mapaMachine, conversionReport, err := m.ToMachine()
 	if err != nil {
 		errors = append(errors, err)
 	}
 
-	warnings = append(warnings, warn...)
+	mapiMachineSet, warn, errs := fromCAPIMachineSetToMAPIMachineSet(m.machineSet)
+	if len(errs) > 0 {
+		errors = append(errors, errs.ToAggregate())
+	}
 
-	mapiMachineSet, err := fromCAPIMachineSetToMAPIMachineSet(m.machineSet)
-	if err != nil {
-		errors = append(errors, err)
+	conversionReport = append(conversionReport, report.New(report.CAPIToMAPI, warn, errs)...)
+
+	// The converted Machine is nil when its conversion failed, so return the errors before using it.
+	if len(errors) > 0 {
+		return nil, conversionReport, utilerrors.NewAggregate(errors)
 	}
 
 	mapiMachineSet.Spec.Template.Spec = mapaMachine.Spec
@@ -240,11 +263,34 @@ func (m machineSetAndAWSMachineTemplateAndAWSCluster) ToMachineSet() (*mapiv1.Ma
 	mapiMachineSet.Spec.Template.ObjectMeta.Annotations = mapaMachine.ObjectMeta.Annotations
 	mapiMachineSet.Spec.Template.ObjectMeta.Labels = mapaMachine.ObjectMeta.Labels
 
+	return mapiMachineSet, conversionReport, nil
+}
+
+// ToMachineSet converts a capi2mapi MachineDeploymentAndAWSMachineTemplate into a MAPI MachineSet.
+func (m machineDeploymentAndAWSMachineTemplateAndAWSCluster) ToMachineSet() (*mapiv1.MachineSet, report.Report, error) {
+	if m.machineDeployment == nil || m.template == nil || m.awsCluster == nil {
+		return nil, nil, errCAPIMachineDeploymentAWSMachineTemplateAWSClusterCannotBeNil
+	}
+
+	var errors []error
+
+	capiMachineSet, errs := fromCAPIMachineDeploymentToCAPIMachineSet(m.machineDeployment)
+	if len(errs) > 0 {
+		errors = append(errors, errs.ToAggregate())
+	}
+
+	mapiMachineSet, conversionReport, err := FromMachineSetAndAWSMachineTemplateAndAWSCluster(capiMachineSet, m.template, m.awsCluster).ToMachineSet()
+	if err != nil {
+		errors = append(errors, err)
+	}
+
+	conversionReport = append(report.New(report.CAPIToMAPI, nil, errs), conversionReport...)
+
 	if len(errors) > 0 {
-		return nil, warnings, utilerrors.NewAggregate(errors)
+		return nil, conversionReport, utilerrors.NewAggregate(errors)
 	}
 
-	return mapiMachineSet, warnings, nil
+	return mapiMachineSet, conversionReport, nil
 }
 
 // Conversion helpers.
@@ -265,10 +311,10 @@ func RawExtensionFromProviderSpec(spec *mapiv1.AWSMachineProviderConfig) (*runti
 	}, nil
 }
 
-func convertAWSMetadataOptionsToMAPI(fldPath *field.Path, capiMetadataOpts *capav1.InstanceMetadataOptions) (mapiv1.MetadataServiceOptions, []string, field.ErrorList) {
+func convertAWSMetadataOptionsToMAPI(fldPath *field.Path, capiMetadataOpts *capav1.InstanceMetadataOptions) (mapiv1.MetadataServiceOptions, report.Report, field.ErrorList) { //nolint:unparam
 	var (
 		errors   field.ErrorList
-		warnings []string
+		warnings report.Report
 	)
 
 	if capiMetadataOpts == nil {
@@ -447,68 +493,39 @@ func convertAWSPlacementGroupPartition(in int64) *int32 {
 	return ptr.To(int32(in))
 }
 
+// AWSUnsupportedFields lists the fields of the AWSMachineSpec that we are currently, or indefinitely not supporting.
+// The conversion returns an error for every present field, and the installer applies ValidatingAdmissionPolicies
+// generated from the same list to prevent their usage.
+//
+//nolint:gochecknoglobals
+var AWSUnsupportedFields = unsupported.Rules{
+	Spec:                         capav1.AWSMachineSpec{},
+	Group:                        capav1.GroupVersion.Group,
+	Version:                      capav1.GroupVersion.Version,
+	InfraMachineResource:         "awsmachines",
+	InfraMachineTemplateResource: "awsmachinetemplates",
+	Fields: []unsupported.Field{
+		// OCPCLOUD-2711: The following fields are not required for our use case.
+		{Path: []string{"ami", "eksLookupType"}, ErrorPath: []string{"ami", "eksOptimizedLookupType"}, Message: "eksOptimizedLookupType is not supported"},
+		{Path: []string{"imageLookupFormat"}, Message: "imageLookupFormat is not supported"},
+		{Path: []string{"imageLookupOrg"}, Message: "imageLookupOrg is not supported"},
+		{Path: []string{"imageLookupBaseOS"}, Message: "imageLookupBaseOS is not supported"},
+		// TODO(OCPCLOUD-2712): Needs more investigation, we are converting additional security groups to MAPI SGs, this overrides the built-ins,
+		// need to explore at the behavioural level. Until then the field is not denied on admission.
+		{Path: []string{"securityGroupOverrides"}, Message: "securityGroupOverrides are not supported", SkipAdmission: true},
+		{Path: []string{"networkInterfaces"}, Message: "networkInterfaces are not supported"},
+		{Path: []string{"uncompressedUserData"}, Message: "uncompressedUserData is not supported"},
+		{Path: []string{"cloudInit"}, Message: "cloudInit is not supported"},
+		{Path: []string{"privateDnsName"}, ErrorPath: []string{"privateDNSName"}, Message: "privateDNSName is not supported"},
+		// Ignition proxy and TLS are not configurable in MAPI.
+		{Path: []string{"ignition", "proxy"}, Message: "ignition proxy is not supported"},
+		{Path: []string{"ignition", "tls"}, Message: "ignition tls is not supported"},
+	},
+}
+
 // handleUnsupportedAWSMachineFields returns an error for every present field in the AWSMachineSpec that
 // we are currently, or indefinitely not supporting.
-// TODO: These are protected by VAPs so should never actually cause an error here.
+// These are protected by VAPs so should never actually cause an error here.
 func handleUnsupportedAWSMachineFields(fldPath *field.Path, spec capav1.AWSMachineSpec) field.ErrorList {
-	errs := field.ErrorList{}
-
-	if spec.AMI.EKSOptimizedLookupType != nil {
-		// TODO(OCPCLOUD-2711): Not required for our use case, add VAP to prevent usage.
-		errs = append(errs, field.Invalid(fldPath.Child("ami", "eksOptimizedLookupType"), spec.AMI.EKSOptimizedLookupType, "eksOptimizedLookupType is not supported"))
-	}
-
-	if spec.ImageLookupFormat != "" {
-		// TODO(OCPCLOUD-2711): Not required for our use case, add VAP to prevent usage.
-		errs = append(errs, field.Invalid(fldPath.Child("imageLookupFormat"), spec.ImageLookupFormat, "imageLookupFormat is not supported"))
-	}
-
-	if spec.ImageLookupOrg != "" {
-		// TODO(OCPCLOUD-2711): Not required for our use case, add VAP to prevent usage.
-		errs = append(errs, field.Invalid(fldPath.Child("imageLookupOrg"), spec.ImageLookupOrg, "imageLookupOrg is not supported"))
-	}
-
-	if spec.ImageLookupBaseOS != "" {
-		// TODO(OCPCLOUD-2711): Not required for our use case, add VAP to prevent usage.
-		errs = append(errs, field.Invalid(fldPath.Child("imageLookupBaseOS"), spec.ImageLookupBaseOS, "imageLookupBaseOS is not supported"))
-	}
-
-	if len(spec.SecurityGroupOverrides) > 0 {
-		// TODO(OCPCLOUD-2712): Needs more investigation, we are converting additional security groups to MAPI SGs, this overrides the built-ins, need to explore at the behavioural level.
-		errs = append(errs, field.Invalid(fldPath.Child("securityGroupOverrides"), spec.SecurityGroupOverrides, "securityGroupOverrides are not supported"))
-	}
-
-	if len(spec.NetworkInterfaces) > 0 {
-		// TODO(OCPCLOUD-2711): Not required for our use case, add VAP to prevent usage.
-		errs = append(errs, field.Invalid(fldPath.Child("networkInterfaces"), spec.NetworkInterfaces, "networkInterfaces are not supported"))
-	}
-
-	if spec.UncompressedUserData != nil {
-		// TODO(OCPCLOUD-2711): Not required for our use case, add VAP to prevent usage.
-		errs = append(errs, field.Invalid(fldPath.Child("uncompressedUserData"), spec.UncompressedUserData, "uncompressedUserData is not supported"))
-	}
-
-	if (spec.CloudInit != capav1.CloudInit{}) {
-		// TODO(OCPCLOUD-2711): Not required for our use case, add VAP to prevent usage.
-		errs = append(errs, field.Invalid(fldPath.Child("cloudInit"), spec.CloudInit, "cloudInit is not supported"))
-	}
-
-	if spec.PrivateDNSName != nil {
-		// TODO(OCPCLOUD-2711): Not required for our use case, add VAP to prevent usage.
-		errs = append(errs, field.Invalid(fldPath.Child("privateDNSName"), spec.PrivateDNSName, "privateDNSName is not supported"))
-	}
-
-	if spec.Ignition != nil {
-		if spec.Ignition.Proxy != nil {
-			// TODO(OCPCLOUD-2711): Ignition proxy is not configurable in MAPI. Not required for our use case, add VAP to prevent usage.
-			errs = append(errs, field.Invalid(fldPath.Child("ignition", "proxy"), spec.Ignition.Proxy, "ignition proxy is not supported"))
-		}
-
-		if spec.Ignition.TLS != nil {
-			// TODO(OCPCLOUD-2711): Ignition TLS is not configurable in MAPI. Not required for our use case, add VAP to prevent usage.
-			errs = append(errs, field.Invalid(fldPath.Child("ignition", "tls"), spec.Ignition.TLS, "ignition tls is not supported"))
-		}
-	}
-
-	return errs
+	return AWSUnsupportedFields.Validate(fldPath, spec)
 }
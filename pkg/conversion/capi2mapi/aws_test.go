@@ -57,7 +57,7 @@ var _ = Describe("capi2mapi AWS conversion", func() {
 			).ToMachine()
 			Expect(err).To(matchers.ConsistOfMatchErrorSubstrings(in.expectedErrors),
 				"should match expected errors while converting AWS CAPI resources to MAPI Machine")
-			Expect(warns).To(matchers.ConsistOfSubstrings(in.expectedWarnings),
+			Expect(warns.Warnings()).To(matchers.ConsistOfSubstrings(in.expectedWarnings),
 				"should match expected warnings while converting AWS CAPI resources to MAPI Machine")
 		},
 
@@ -241,7 +241,7 @@ var _ = Describe("capi2mapi AWS conversion", func() {
 			).ToMachineSet()
 			Expect(err).To(matchers.ConsistOfMatchErrorSubstrings(in.expectedErrors),
 				"should match expected errors while converting AWS CAPI resources to MAPI MachineSet")
-			Expect(warns).To(matchers.ConsistOfSubstrings(in.expectedWarnings),
+			Expect(warns.Warnings()).To(matchers.ConsistOfSubstrings(in.expectedWarnings),
 				"should match expected warnings while converting AWS CAPI resources to MAPI MachineSet")
 		},
 
@@ -0,0 +1,101 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package capi2mapi_test
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/cluster-capi-operator/pkg/conversion"
+	conversiontest "github.com/openshift/cluster-capi-operator/pkg/conversion/test/fuzz"
+
+	"k8s.io/apimachinery/pkg/api/apitesting/fuzzer"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+const (
+	capiNamespace = "openshift-cluster-api"
+)
+
+var _ = Describe("Platform Fuzz (capi2mapi)", func() {
+	infra := &configv1.Infrastructure{
+		Spec: configv1.InfrastructureSpec{},
+		Status: configv1.InfrastructureStatus{
+			InfrastructureName: "sample-cluster-name",
+		},
+	}
+
+	for _, platform := range conversion.Platforms() {
+		Context(string(platform.Type), func() {
+			in, ok := conversiontest.InputsFor(platform.Type)
+			if !ok {
+				It("should have fuzz inputs for the registered platform", func() {
+					Fail("no fuzz inputs for platform " + string(platform.Type))
+				})
+
+				return
+			}
+
+			infraMachineGVK, err := apiutil.GVKForObject(platform.NewInfraMachine(), scheme)
+			if err != nil {
+				panic(fmt.Sprintf("failed to get the InfraMachine kind of platform %s: %v", platform.Type, err))
+			}
+
+			infraMachineTemplateGVK, err := apiutil.GVKForObject(platform.NewInfraMachineTemplate(), scheme)
+			if err != nil {
+				panic(fmt.Sprintf("failed to get the InfraMachineTemplate kind of platform %s: %v", platform.Type, err))
+			}
+
+			Context("Machine Conversion", func() {
+				fuzzerFuncs := append([]fuzzer.FuzzerFuncs{
+					conversiontest.ObjectMetaFuzzerFuncs(capiNamespace),
+					conversiontest.CAPIMachineFuzzerFuncs(in.ProviderIDFuzzer, infraMachineGVK.Kind, infraMachineGVK.GroupVersion().String(), infra.Status.InfrastructureName),
+				}, in.InfraMachineFuzzerFuncs...)
+
+				conversiontest.CAPI2MAPIMachineRoundTripFuzzTest(
+					scheme,
+					infra,
+					in.InfraCluster,
+					platform.NewInfraMachine(),
+					platform.FromMAPIMachine,
+					conversiontest.CAPI2MAPIMachineConverterFromPlatform(platform),
+					fuzzerFuncs...,
+				)
+			})
+
+			Context("MachineSet Conversion", func() {
+				fuzzerFuncs := append([]fuzzer.FuzzerFuncs{
+					conversiontest.ObjectMetaFuzzerFuncs(capiNamespace),
+					conversiontest.CAPIMachineFuzzerFuncs(in.ProviderIDFuzzer, infraMachineTemplateGVK.Kind, infraMachineTemplateGVK.GroupVersion().String(), infra.Status.InfrastructureName),
+					conversiontest.CAPIMachineSetFuzzerFuncs(infraMachineTemplateGVK.Kind, infraMachineTemplateGVK.GroupVersion().String(), infra.Status.InfrastructureName),
+				}, in.InfraMachineTemplateFuzzerFuncs...)
+
+				conversiontest.CAPI2MAPIMachineSetRoundTripFuzzTest(
+					scheme,
+					infra,
+					in.InfraCluster,
+					platform.NewInfraMachineTemplate(),
+					platform.FromMAPIMachineSet,
+					conversiontest.CAPI2MAPIMachineSetConverterFromPlatform(platform),
+					fuzzerFuncs...,
+				)
+			})
+		})
+	}
+})
@@ -15,14 +15,17 @@ limitations under the License.
 */
 package capi2mapi
 
-import mapiv1 "github.com/openshift/api/machine/v1beta1"
+import (
+	mapiv1 "github.com/openshift/api/machine/v1beta1"
+	"github.com/openshift/cluster-capi-operator/pkg/conversion/report"
+)
 
 // MachineAndInfrastructureMachine represents the conversion between a CAPI Machine and InfrastructureMachine to a MAPI Machine.
 type MachineAndInfrastructureMachine interface {
-	ToMachine() (*mapiv1.Machine, []string, error)
+	ToMachine() (*mapiv1.Machine, report.Report, error)
 }
 
 // MachineSetAndMachineTemplate represents the conversion between a CAPI MachineSet and MachineTemplate to a MAPI MachineSet.
 type MachineSetAndMachineTemplate interface {
-	ToMachineSet() (*mapiv1.MachineSet, []string, error)
+	ToMachineSet() (*mapiv1.MachineSet, report.Report, error)
 }
@@ -48,7 +48,7 @@ var _ = Describe("capi2mapi Machine conversion", func() {
 			).ToMachine()
 			Expect(err).To(matchers.ConsistOfMatchErrorSubstrings(in.expectedErrors),
 				"should match expected errors while converting CAPI resources to MAPI Machine")
-			Expect(warns).To(matchers.ConsistOfSubstrings(in.expectedWarnings),
+			Expect(warns.Warnings()).To(matchers.ConsistOfSubstrings(in.expectedWarnings),
 				"should match expected warnings while converting CAPI resources to MAPI Machine")
 		},
 
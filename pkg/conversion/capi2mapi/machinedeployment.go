@@ -0,0 +1,108 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package capi2mapi
+
+import (
+	conversionutil "github.com/openshift/cluster-capi-operator/pkg/conversion/util"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	capiv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// fromCAPIMachineDeploymentToCAPIMachineSet takes a CAPI MachineDeployment and flattens it into a CAPI MachineSet
+// so that it can be converted by the MachineSet conversion logic.
+// The rolling update strategy is stored in annotations, as MAPI MachineSets have no equivalent fields.
+func fromCAPIMachineDeploymentToCAPIMachineSet(capiMachineDeployment *capiv1.MachineDeployment) (*capiv1.MachineSet, field.ErrorList) {
+	errs := field.ErrorList{}
+
+	annotations := map[string]string{}
+	for k, v := range capiMachineDeployment.Annotations {
+		annotations[k] = v
+	}
+
+	annotations[conversionutil.MachineDeploymentAnnotation] = "true"
+
+	capiMachineSet := &capiv1.MachineSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            capiMachineDeployment.Name,
+			Namespace:       capiMachineDeployment.Namespace,
+			Labels:          capiMachineDeployment.Labels,
+			Annotations:     annotations,
+			OwnerReferences: capiMachineDeployment.OwnerReferences,
+		},
+		Spec: capiv1.MachineSetSpec{
+			ClusterName:     capiMachineDeployment.Spec.ClusterName,
+			Replicas:        capiMachineDeployment.Spec.Replicas,
+			Selector:        capiMachineDeployment.Spec.Selector,
+			Template:        capiMachineDeployment.Spec.Template,
+			MinReadySeconds: ptr.Deref(capiMachineDeployment.Spec.MinReadySeconds, 0),
+		},
+	}
+
+	deletePolicy, strategyErrs := convertCAPIMachineDeploymentStrategyToMAPI(field.NewPath("spec", "strategy"), capiMachineDeployment.Spec.Strategy, annotations)
+	errs = append(errs, strategyErrs...)
+
+	capiMachineSet.Spec.DeletePolicy = deletePolicy
+
+	// Unused fields - Below this line are fields not used from the CAPI MachineDeployment.
+
+	if capiMachineDeployment.Spec.RolloutAfter != nil {
+		errs = append(errs, field.Invalid(field.NewPath("spec", "rolloutAfter"), capiMachineDeployment.Spec.RolloutAfter, "rolloutAfter is not supported"))
+	}
+
+	if capiMachineDeployment.Spec.Paused {
+		errs = append(errs, field.Invalid(field.NewPath("spec", "paused"), capiMachineDeployment.Spec.Paused, "paused is not supported"))
+	}
+
+	// capiMachineDeployment.Spec.RevisionHistoryLimit - Ignore, this only affects the MachineSets owned by the MachineDeployment.
+	// capiMachineDeployment.Spec.ProgressDeadlineSeconds - Ignore, this is deprecated and not implemented in CAPI.
+
+	return capiMachineSet, errs
+}
+
+// convertCAPIMachineDeploymentStrategyToMAPI stores the rolling update parameters of a CAPI MachineDeployment strategy
+// in the given annotations and returns the delete policy.
+func convertCAPIMachineDeploymentStrategyToMAPI(fldPath *field.Path, strategy *capiv1.MachineDeploymentStrategy, annotations map[string]string) (string, field.ErrorList) {
+	errs := field.ErrorList{}
+
+	if strategy == nil {
+		return "", errs
+	}
+
+	if strategy.Type != "" && strategy.Type != capiv1.RollingUpdateMachineDeploymentStrategyType {
+		errs = append(errs, field.Invalid(fldPath.Child("type"), strategy.Type, "only the RollingUpdate strategy is supported"))
+	}
+
+	if strategy.Remediation != nil {
+		errs = append(errs, field.Invalid(fldPath.Child("remediation"), strategy.Remediation, "remediation is not supported"))
+	}
+
+	if strategy.RollingUpdate == nil {
+		return "", errs
+	}
+
+	if strategy.RollingUpdate.MaxSurge != nil {
+		annotations[conversionutil.MachineDeploymentMaxSurgeAnnotation] = strategy.RollingUpdate.MaxSurge.String()
+	}
+
+	if strategy.RollingUpdate.MaxUnavailable != nil {
+		annotations[conversionutil.MachineDeploymentMaxUnavailableAnnotation] = strategy.RollingUpdate.MaxUnavailable.String()
+	}
+
+	return ptr.Deref(strategy.RollingUpdate.DeletePolicy, ""), errs
+}
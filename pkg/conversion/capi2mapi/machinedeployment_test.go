@@ -0,0 +1,113 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package capi2mapi
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	capabuilder "github.com/openshift/cluster-api-actuator-pkg/testutils/resourcebuilder/cluster-api/infrastructure/v1beta2"
+	"github.com/openshift/cluster-capi-operator/pkg/conversion/test/matchers"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	capiv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+var _ = Describe("capi2mapi MachineDeployment conversion", func() {
+	machineDeploymentWithStrategy := func(strategy *capiv1.MachineDeploymentStrategy) *capiv1.MachineDeployment {
+		return &capiv1.MachineDeployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "worker",
+				Annotations: map[string]string{"foo": "bar"},
+			},
+			Spec: capiv1.MachineDeploymentSpec{
+				ClusterName: "cluster",
+				Replicas:    ptr.To(int32(3)),
+				Strategy:    strategy,
+			},
+		}
+	}
+
+	type capi2MAPIMachineDeploymentConversionInput struct {
+		machineDeployment *capiv1.MachineDeployment
+		expectedErrors    []string
+		expectedWarnings  []string
+	}
+
+	var _ = DescribeTable("capi2mapi convert CAPI MachineDeployment/InfraMachineTemplate/InfraCluster to MAPI MachineSet",
+		func(in capi2MAPIMachineDeploymentConversionInput) {
+			_, warns, err := FromMachineDeploymentAndAWSMachineTemplateAndAWSCluster(
+				in.machineDeployment,
+				capabuilder.AWSMachineTemplate().Build(),
+				capabuilder.AWSCluster().Build(),
+			).ToMachineSet()
+			Expect(err).To(matchers.ConsistOfMatchErrorSubstrings(in.expectedErrors),
+				"should match expected errors while converting CAPI resources to MAPI MachineSet")
+			Expect(warns.Warnings()).To(matchers.ConsistOfSubstrings(in.expectedWarnings),
+				"should match expected warnings while converting CAPI resources to MAPI MachineSet")
+		},
+
+		// Base Case.
+		Entry("With a Base configuration", capi2MAPIMachineDeploymentConversionInput{
+			machineDeployment: machineDeploymentWithStrategy(nil),
+			expectedErrors:    []string{},
+			expectedWarnings:  []string{},
+		}),
+		Entry("With an unsupported OnDelete strategy", capi2MAPIMachineDeploymentConversionInput{
+			machineDeployment: machineDeploymentWithStrategy(&capiv1.MachineDeploymentStrategy{
+				Type: capiv1.OnDeleteMachineDeploymentStrategyType,
+			}),
+			expectedErrors:   []string{"spec.strategy.type: Invalid value: \"OnDelete\": only the RollingUpdate strategy is supported"},
+			expectedWarnings: []string{},
+		}),
+		Entry("With unsupported paused set", capi2MAPIMachineDeploymentConversionInput{
+			machineDeployment: func() *capiv1.MachineDeployment {
+				md := machineDeploymentWithStrategy(nil)
+				md.Spec.Paused = true
+
+				return md
+			}(),
+			expectedErrors:   []string{"spec.paused: Invalid value: true: paused is not supported"},
+			expectedWarnings: []string{},
+		}),
+	)
+
+	It("should store the rolling update strategy in the MAPI MachineSet annotations", func() {
+		mapiMachineSet, _, err := FromMachineDeploymentAndAWSMachineTemplateAndAWSCluster(
+			machineDeploymentWithStrategy(&capiv1.MachineDeploymentStrategy{
+				Type: capiv1.RollingUpdateMachineDeploymentStrategyType,
+				RollingUpdate: &capiv1.MachineRollingUpdateDeployment{
+					MaxSurge:       ptr.To(intstr.FromString("25%")),
+					MaxUnavailable: ptr.To(intstr.FromInt32(0)),
+					DeletePolicy:   ptr.To("Newest"),
+				},
+			}),
+			capabuilder.AWSMachineTemplate().Build(),
+			capabuilder.AWSCluster().Build(),
+		).ToMachineSet()
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(mapiMachineSet.Annotations).To(Equal(map[string]string{
+			"machine.openshift.io/cluster-api-machine-deployment":                 "true",
+			"machine.openshift.io/cluster-api-machine-deployment-max-surge":       "25%",
+			"machine.openshift.io/cluster-api-machine-deployment-max-unavailable": "0",
+			"foo": "bar",
+		}))
+		Expect(mapiMachineSet.Spec.DeletePolicy).To(Equal("Newest"))
+		Expect(mapiMachineSet.Spec.Replicas).To(Equal(ptr.To(int32(3))))
+	})
+})
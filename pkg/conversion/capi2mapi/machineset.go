@@ -19,6 +19,7 @@ import (
 	"strings"
 
 	mapiv1 "github.com/openshift/api/machine/v1beta1"
+	"github.com/openshift/cluster-capi-operator/pkg/conversion/report"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
@@ -26,15 +27,19 @@ import (
 )
 
 // fromCAPIMachineSetToMAPIMachineSet takes a CAPI MachineSet and returns a converted MAPI MachineSet.
-func fromCAPIMachineSetToMAPIMachineSet(capiMachineSet *capiv1.MachineSet) (*mapiv1.MachineSet, error) {
+func fromCAPIMachineSetToMAPIMachineSet(capiMachineSet *capiv1.MachineSet) (*mapiv1.MachineSet, report.Report, field.ErrorList) {
 	errs := field.ErrorList{}
 
+	// Autoscaler node group size and scale-from-zero annotations use different keys in MAPI.
+	mapiAnnotations, warnings, annotationErrs := convertCAPIMachineSetAnnotationsToMAPI(field.NewPath("metadata", "annotations"), capiMachineSet.Annotations)
+	errs = append(errs, annotationErrs...)
+
 	mapiMachineSet := &mapiv1.MachineSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        capiMachineSet.Name,
 			Namespace:   capiMachineSet.Namespace,
 			Labels:      capiMachineSet.Labels,
-			Annotations: capiMachineSet.Annotations,
+			Annotations: mapiAnnotations,
 			// OwnerReferences: There shouldn't be any OwnerReferences on a MachineSet.
 		},
 		Spec: mapiv1.MachineSetSpec{
@@ -79,8 +84,8 @@ func fromCAPIMachineSetToMAPIMachineSet(capiMachineSet *capiv1.MachineSet) (*map
 
 	if len(errs) > 0 {
 		// Return the mapiMachine so that the logic continues and collects all possible conversion errors.
-		return mapiMachineSet, errs.ToAggregate()
+		return mapiMachineSet, warnings, errs
 	}
 
-	return mapiMachineSet, nil
+	return mapiMachineSet, warnings, nil
 }
@@ -20,6 +20,7 @@ import (
 	. "github.com/onsi/gomega"
 	capibuilder "github.com/openshift/cluster-api-actuator-pkg/testutils/resourcebuilder/cluster-api/core/v1beta1"
 	capabuilder "github.com/openshift/cluster-api-actuator-pkg/testutils/resourcebuilder/cluster-api/infrastructure/v1beta2"
+	"github.com/openshift/cluster-capi-operator/pkg/conversion/report"
 	"github.com/openshift/cluster-capi-operator/pkg/conversion/test/matchers"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -45,7 +46,7 @@ var _ = Describe("capi2mapi MachineSet conversion", func() {
 			).ToMachineSet()
 			Expect(err).To(matchers.ConsistOfMatchErrorSubstrings(in.expectedErrors),
 				"should match expected errors while converting CAPI resources to MAPI MachineSet")
-			Expect(warns).To(matchers.ConsistOfSubstrings(in.expectedWarnings),
+			Expect(warns.Warnings()).To(matchers.ConsistOfSubstrings(in.expectedWarnings),
 				"should match expected warnings while converting CAPI resources to MAPI MachineSet")
 		},
 
@@ -60,5 +61,101 @@ var _ = Describe("capi2mapi MachineSet conversion", func() {
 			expectedErrors:    []string{"metadata.ownerReferences: Invalid value: []v1.OwnerReference{v1.OwnerReference{APIVersion:\"\", Kind:\"\", Name:\"a\", UID:\"\", Controller:(*bool)(nil), BlockOwnerDeletion:(*bool)(nil)}}: ownerReferences are not supported"},
 			expectedWarnings:  []string{},
 		}),
+		Entry("With an invalid memory capacity annotation", capi2MAPIMachinesetConversionInput{
+			machineSetBuilder: capiMachineSetBase.WithAnnotations(map[string]string{
+				"capacity.cluster-autoscaler.kubernetes.io/memory": "lots",
+			}),
+			expectedErrors:   []string{"metadata.annotations[capacity.cluster-autoscaler.kubernetes.io/memory]: Invalid value: \"lots\": memory capacity annotation must be a valid resource quantity"},
+			expectedWarnings: []string{},
+		}),
+		Entry("With an invalid cpu capacity annotation", capi2MAPIMachinesetConversionInput{
+			machineSetBuilder: capiMachineSetBase.WithAnnotations(map[string]string{
+				"capacity.cluster-autoscaler.kubernetes.io/cpu": "lots",
+			}),
+			expectedErrors:   []string{"metadata.annotations[capacity.cluster-autoscaler.kubernetes.io/cpu]: Invalid value: \"lots\": cpu capacity annotation must be a valid resource quantity"},
+			expectedWarnings: []string{},
+		}),
+		Entry("With a fractional cpu capacity annotation", capi2MAPIMachinesetConversionInput{
+			machineSetBuilder: capiMachineSetBase.WithAnnotations(map[string]string{
+				"capacity.cluster-autoscaler.kubernetes.io/cpu": "500m",
+			}),
+			expectedErrors: []string{},
+			expectedWarnings: []string{
+				"metadata.annotations[capacity.cluster-autoscaler.kubernetes.io/cpu]: Invalid value: \"500m\": cpu capacity is not a whole number of CPUs, rounding up",
+			},
+		}),
+		Entry("With lossy memory and gpu-type capacity annotations", capi2MAPIMachinesetConversionInput{
+			machineSetBuilder: capiMachineSetBase.WithAnnotations(map[string]string{
+				"capacity.cluster-autoscaler.kubernetes.io/memory":   "16G",
+				"capacity.cluster-autoscaler.kubernetes.io/gpu-type": "amd.com/gpu",
+			}),
+			expectedErrors: []string{},
+			expectedWarnings: []string{
+				"metadata.annotations[capacity.cluster-autoscaler.kubernetes.io/memory]: Invalid value: \"16G\": memory capacity is not a whole number of MiB, rounding down",
+				"metadata.annotations[capacity.cluster-autoscaler.kubernetes.io/gpu-type]: Invalid value: \"amd.com/gpu\": gpu types other than nvidia.com/gpu can not be represented in MAPI, ignoring",
+			},
+		}),
 	)
+
+	It("should translate the autoscaler annotations to their MAPI equivalents", func() {
+		mapiMachineSet, warns, err := FromMachineSetAndAWSMachineTemplateAndAWSCluster(
+			capiMachineSetBase.WithAnnotations(map[string]string{
+				"cluster.x-k8s.io/cluster-api-autoscaler-node-group-min-size": "0",
+				"cluster.x-k8s.io/cluster-api-autoscaler-node-group-max-size": "3",
+				"capacity.cluster-autoscaler.kubernetes.io/cpu":               "8",
+				"capacity.cluster-autoscaler.kubernetes.io/memory":            "32Gi",
+				"capacity.cluster-autoscaler.kubernetes.io/gpu-count":         "2",
+				"capacity.cluster-autoscaler.kubernetes.io/gpu-type":          "nvidia.com/gpu",
+				"capacity.cluster-autoscaler.kubernetes.io/maxPods":           "110",
+				"foo": "bar",
+			}).Build(),
+			capabuilder.AWSMachineTemplate().Build(),
+			capabuilder.AWSCluster().Build(),
+		).ToMachineSet()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(warns).To(BeEmpty())
+
+		Expect(mapiMachineSet.Annotations).To(Equal(map[string]string{
+			"machine.openshift.io/cluster-api-autoscaler-node-group-min-size": "0",
+			"machine.openshift.io/cluster-api-autoscaler-node-group-max-size": "3",
+			"machine.openshift.io/vCPU":                                       "8",
+			"machine.openshift.io/memoryMb":                                   "32768",
+			"machine.openshift.io/GPU":                                        "2",
+			"machine.openshift.io/maxPods":                                    "110",
+			"foo":                                                             "bar",
+		}))
+	})
+
+	It("should report the fields affected by the conversion", func() {
+		_, conversionReport, err := FromMachineSetAndAWSMachineTemplateAndAWSCluster(
+			capiMachineSetBase.WithAnnotations(map[string]string{
+				"capacity.cluster-autoscaler.kubernetes.io/memory":   "16G",
+				"capacity.cluster-autoscaler.kubernetes.io/gpu-type": "amd.com/gpu",
+			}).WithOwnerReferences([]metav1.OwnerReference{{Name: "a"}}).Build(),
+			capabuilder.AWSMachineTemplate().Build(),
+			capabuilder.AWSCluster().Build(),
+		).ToMachineSet()
+		Expect(err).To(HaveOccurred())
+
+		Expect(conversionReport).To(ContainElements(
+			SatisfyAll(
+				HaveField("Field", "metadata.annotations[capacity.cluster-autoscaler.kubernetes.io/memory]"),
+				HaveField("Direction", report.CAPIToMAPI),
+				HaveField("Severity", report.SeverityLossy),
+				HaveField("Reason", report.ReasonValueRounded),
+			),
+			SatisfyAll(
+				HaveField("Field", "metadata.annotations[capacity.cluster-autoscaler.kubernetes.io/gpu-type]"),
+				HaveField("Direction", report.CAPIToMAPI),
+				HaveField("Severity", report.SeverityLossy),
+				HaveField("Reason", report.ReasonValueIgnored),
+			),
+			SatisfyAll(
+				HaveField("Field", "metadata.ownerReferences"),
+				HaveField("Direction", report.CAPIToMAPI),
+				HaveField("Severity", report.SeverityError),
+				HaveField("Reason", report.ReasonInvalidValue),
+			),
+		))
+	})
 })
@@ -22,10 +22,11 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	"github.com/openshift/cluster-capi-operator/pkg/conversion"
+
 	"k8s.io/apimachinery/pkg/runtime"
 	kubescheme "k8s.io/client-go/kubernetes/scheme"
 
-	capav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
 	capiv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
@@ -39,8 +40,10 @@ func init() {
 		panic(fmt.Sprintf("failed to add cluster API scheme: %v", err))
 	}
 
-	if err := capav1.AddToScheme(scheme); err != nil {
-		panic(fmt.Sprintf("failed to add aws scheme: %v", err))
+	for _, platform := range conversion.Platforms() {
+		if err := platform.AddToScheme(scheme); err != nil {
+			panic(fmt.Sprintf("failed to add %s scheme: %v", platform.Type, err))
+		}
 	}
 }
 
@@ -0,0 +1,82 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package mapi2capi
+
+import (
+	"strconv"
+
+	conversionutil "github.com/openshift/cluster-capi-operator/pkg/conversion/util"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const mebibyte = 1024 * 1024
+
+// convertMAPIMachineSetAnnotationsToCAPI translates the MachineAutoscaler and scale-from-zero annotations
+// of a MAPI MachineSet into their CAPI equivalents. All other annotations are copied as is.
+//
+// The MAPI scale-from-zero annotations are populated by the MAPI MachineSet controllers based on the instance type,
+// carrying them over allows the autoscaler to keep scaling from zero once the CAPI MachineSet is authoritative.
+func convertMAPIMachineSetAnnotationsToCAPI(fldPath *field.Path, mapiAnnotations map[string]string) (map[string]string, field.ErrorList) {
+	if mapiAnnotations == nil {
+		return nil, nil
+	}
+
+	var errs field.ErrorList
+
+	capiAnnotations := make(map[string]string, len(mapiAnnotations))
+	verbatimKeys := conversionutil.MAPIToCAPIAutoscalerAnnotations()
+
+	for k, v := range mapiAnnotations {
+		if capiKey, ok := verbatimKeys[k]; ok {
+			capiAnnotations[capiKey] = v
+			continue
+		}
+
+		switch k {
+		case conversionutil.MAPICPUAnnotation:
+			// MAPI expresses the CPU capacity as a number of vCPUs, which is a valid CAPI resource quantity.
+			if _, err := strconv.ParseInt(v, 10, 64); err != nil {
+				errs = append(errs, field.Invalid(fldPath.Key(k), v, "vCPU annotation must be an integer number of CPUs"))
+				continue
+			}
+
+			capiAnnotations[conversionutil.CAPICPUCapacityAnnotation] = v
+		case conversionutil.MAPIMemoryAnnotation:
+			// MAPI expresses memory as a number of MiB, CAPI expects a resource quantity.
+			// The quantity is canonicalised, e.g. 32768 MiB is 32Gi, so that it converts back to the same MiB.
+			memoryMb, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				errs = append(errs, field.Invalid(fldPath.Key(k), v, "memory annotation must be an integer number of MiB"))
+				continue
+			}
+
+			capiAnnotations[conversionutil.CAPIMemoryCapacityAnnotation] = resource.NewQuantity(memoryMb*mebibyte, resource.BinarySI).String()
+		case conversionutil.MAPIGPUAnnotation:
+			capiAnnotations[conversionutil.CAPIGPUCountCapacityAnnotation] = v
+
+			// MAPI does not carry the GPU type, the OpenShift autoscaler always assumes NVIDIA GPUs.
+			if v != "0" && v != "" {
+				capiAnnotations[conversionutil.CAPIGPUTypeCapacityAnnotation] = conversionutil.DefaultGPUType
+			}
+		default:
+			capiAnnotations[k] = v
+		}
+	}
+
+	return capiAnnotations, errs
+}
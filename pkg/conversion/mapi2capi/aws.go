@@ -24,10 +24,10 @@ import (
 
 	configv1 "github.com/openshift/api/config/v1"
 	mapiv1 "github.com/openshift/api/machine/v1beta1"
+	"github.com/openshift/cluster-capi-operator/pkg/conversion/report"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/utils/ptr"
 	capav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
@@ -37,7 +37,8 @@ import (
 )
 
 var (
-	errUnexpectedObjectTypeForMachine = errors.New("unexpected type for capaMachineObj")
+	errUnexpectedObjectTypeForMachine         = errors.New("unexpected type for capaMachineObj")
+	errUnexpectedObjectTypeForMachineTemplate = errors.New("unexpected type for capaMachineTemplateObj")
 )
 
 // awsMachineAndInfra stores the details of a Machine API AWSMachine and Infra.
@@ -74,20 +75,21 @@ func FromAWSMachineSetAndInfra(m *mapiv1.MachineSet, i *configv1.Infrastructure)
 
 // ToMachineAndInfrastructureMachine is used to generate a CAPI Machine and the corresponding InfrastructureMachine
 // from the stored MAPI Machine and Infrastructure objects.
-func (m *awsMachineAndInfra) ToMachineAndInfrastructureMachine() (*capiv1.Machine, client.Object, []string, error) {
+func (m *awsMachineAndInfra) ToMachineAndInfrastructureMachine() (*capiv1.Machine, client.Object, report.Report, error) {
 	capiMachine, capaMachine, warnings, errs := m.toMachineAndInfrastructureMachine()
+	conversionReport := report.New(report.MAPIToCAPI, warnings, errs)
 
 	if len(errs) > 0 {
-		return nil, nil, warnings, errs.ToAggregate()
+		return nil, nil, conversionReport, errs.ToAggregate()
 	}
 
-	return capiMachine, capaMachine, warnings, nil
+	return capiMachine, capaMachine, conversionReport, nil
 }
 
-func (m *awsMachineAndInfra) toMachineAndInfrastructureMachine() (*capiv1.Machine, client.Object, []string, field.ErrorList) {
+func (m *awsMachineAndInfra) toMachineAndInfrastructureMachine() (*capiv1.Machine, client.Object, report.Report, field.ErrorList) {
 	var (
 		errs     field.ErrorList
-		warnings []string
+		warnings report.Report
 	)
 
 	awsProviderConfig, err := awsProviderSpecFromRawExtension(m.machine.Spec.ProviderSpec.Value)
@@ -144,31 +146,39 @@ func (m *awsMachineAndInfra) toMachineAndInfrastructureMachine() (*capiv1.Machin
 }
 
 // ToMachineSetAndMachineTemplate converts a mapi2capi AWSMachineSetAndInfra into a CAPI MachineSet and CAPA AWSMachineTemplate.
-func (m *awsMachineSetAndInfra) ToMachineSetAndMachineTemplate() (*capiv1.MachineSet, client.Object, []string, error) {
+func (m *awsMachineSetAndInfra) ToMachineSetAndMachineTemplate() (*capiv1.MachineSet, client.Object, report.Report, error) {
 	var (
-		errs     []error
-		warnings []string
+		errs     field.ErrorList
+		warnings report.Report
 	)
 
-	capiMachine, capaMachineObj, warn, err := m.toMachineAndInfrastructureMachine()
-	if err != nil {
-		errs = append(errs, err.ToAggregate().Errors()...)
+	capiMachine, capaMachineObj, warn, machineErrs := m.toMachineAndInfrastructureMachine()
+	if machineErrs != nil {
+		errs = append(errs, machineErrs...)
 	}
 
 	warnings = append(warnings, warn...)
 
 	capaMachine, ok := capaMachineObj.(*capav1.AWSMachine)
 	if !ok {
-		panic(fmt.Errorf("%w: %T", errUnexpectedObjectTypeForMachine, capaMachineObj))
+		// The AWSMachine is only missing when the providerSpec could not be converted.
+		if len(errs) > 0 {
+			return nil, nil, report.New(report.MAPIToCAPI, warnings, errs), errs.ToAggregate()
+		}
+
+		return nil, nil, warnings, fmt.Errorf("%w: %T", errUnexpectedObjectTypeForMachine, capaMachineObj)
 	}
 
 	capaMachineTemplate := awsMachineToAWSMachineTemplate(capaMachine, m.machineSet.Name, capiNamespace)
 
 	capiMachineSet, machineSetErrs := fromMAPIMachineSetToCAPIMachineSet(m.machineSet)
 	if machineSetErrs != nil {
-		errs = append(errs, machineSetErrs.Errors()...)
+		errs = append(errs, machineSetErrs...)
 	}
 
+	// Scale-from-zero capacity the MAPI MachineSet controller has not annotated yet is derived from the instance type.
+	capiMachineSet.Annotations = setAWSCapacityAnnotations(capiMachineSet.Annotations, capaMachine.Spec.InstanceType)
+
 	capiMachineSet.Spec.Template.Spec = capiMachine.Spec
 
 	// We have to merge these two maps so that labels and annotations added to the template objectmeta are persisted
@@ -187,23 +197,53 @@ func (m *awsMachineSetAndInfra) ToMachineSetAndMachineTemplate() (*capiv1.Machin
 		capiMachineSet.Spec.ClusterName = m.infrastructure.Status.InfrastructureName
 	}
 
+	conversionReport := report.New(report.MAPIToCAPI, warnings, errs)
+
 	if len(errs) > 0 {
-		return nil, nil, warnings, utilerrors.NewAggregate(errs)
+		return nil, nil, conversionReport, errs.ToAggregate()
 	}
 
-	return capiMachineSet, capaMachineTemplate, warnings, nil
+	return capiMachineSet, capaMachineTemplate, conversionReport, nil
+}
+
+// ToMachineDeploymentAndMachineTemplate converts a mapi2capi AWSMachineSetAndInfra into a CAPI MachineDeployment and CAPA AWSMachineTemplate.
+func (m *awsMachineSetAndInfra) ToMachineDeploymentAndMachineTemplate() (*capiv1.MachineDeployment, client.Object, report.Report, error) {
+	capiMachineSet, capaMachineTemplateObj, conversionReport, err := m.ToMachineSetAndMachineTemplate()
+	if err != nil {
+		return nil, nil, conversionReport, err
+	}
+
+	capaMachineTemplate, ok := capaMachineTemplateObj.(*capav1.AWSMachineTemplate)
+	if !ok {
+		return nil, nil, conversionReport, fmt.Errorf("%w: %T", errUnexpectedObjectTypeForMachineTemplate, capaMachineTemplateObj)
+	}
+
+	capiMachineDeployment, errs := fromCAPIMachineSetToCAPIMachineDeployment(capiMachineSet)
+	if len(errs) > 0 {
+		return nil, nil, append(conversionReport, report.New(report.MAPIToCAPI, nil, errs)...), errs.ToAggregate()
+	}
+
+	templateName, err := infraMachineTemplateNameWithHash(capaMachineTemplate.Name, capaMachineTemplate.Spec)
+	if err != nil {
+		return nil, nil, conversionReport, err
+	}
+
+	capaMachineTemplate.Name = templateName
+	capiMachineDeployment.Spec.Template.Spec.InfrastructureRef.Name = templateName
+
+	return capiMachineDeployment, capaMachineTemplate, conversionReport, nil
 }
 
 // ToMachineTemplateSpec implements the ProviderSpec conversion interface for the AWS provider,
 // it converts AWSProviderSpec to AWSMachineTemplateSpec.
 //
 //nolint:funlen
-func (m *awsMachineAndInfra) toAWSMachine(providerSpec mapiv1.AWSMachineProviderConfig) (*capav1.AWSMachine, []string, field.ErrorList) {
+func (m *awsMachineAndInfra) toAWSMachine(providerSpec mapiv1.AWSMachineProviderConfig) (*capav1.AWSMachine, report.Report, field.ErrorList) {
 	fldPath := field.NewPath("spec", "providerSpec", "value")
 
 	var (
 		errs     field.ErrorList
-		warnings []string
+		warnings report.Report
 	)
 
 	rootVolume, nonRootVolumes, warn, blockErrs := convertAWSBlockDeviceMappingSpecToCAPI(fldPath.Child("blockDevices"), providerSpec.BlockDevices)
@@ -421,11 +461,11 @@ func convertAWSSecurityGroupstoCAPI(sgs []mapiv1.AWSResourceReference) []capav1.
 	return capiSGs
 }
 
-func convertAWSBlockDeviceMappingSpecToCAPI(fldPath *field.Path, mapiBlockDeviceMapping []mapiv1.BlockDeviceMappingSpec) (*capav1.Volume, []capav1.Volume, []string, field.ErrorList) {
+func convertAWSBlockDeviceMappingSpecToCAPI(fldPath *field.Path, mapiBlockDeviceMapping []mapiv1.BlockDeviceMappingSpec) (*capav1.Volume, []capav1.Volume, report.Report, field.ErrorList) {
 	rootVolume := &capav1.Volume{}
 	nonRootVolumes := []capav1.Volume{}
 	errs := field.ErrorList{}
-	warnings := []string{}
+	warnings := report.Report{}
 
 	for i, mapping := range mapiBlockDeviceMapping {
 		if mapping.NoDevice != nil {
@@ -441,7 +481,7 @@ func convertAWSBlockDeviceMappingSpecToCAPI(fldPath *field.Path, mapiBlockDevice
 		if mapping.EBS == nil {
 			// MAPA ignores any disk that is missing the EBS configuration.
 			// See https://github.com/openshift/machine-api-provider-aws/blob/a7b3d12db988bd2bebbabd6c2e80147511b949e7/pkg/actuators/machine/instances.go#L287-L289.
-			warnings = append(warnings, field.Invalid(fldPath.Index(i).Child("ebs"), mapping.EBS, "missing ebs configuration for block device").Error())
+			warnings = append(warnings, report.Lossy(fldPath.Index(i).Child("ebs"), mapping.EBS, report.ReasonValueIgnored, "missing ebs configuration for block device"))
 			continue
 		}
 
@@ -465,9 +505,9 @@ func convertAWSBlockDeviceMappingSpecToCAPI(fldPath *field.Path, mapiBlockDevice
 	return rootVolume, nonRootVolumes, warnings, errs
 }
 
-func blockDeviceMappingSpecToVolume(fldPath *field.Path, bdm mapiv1.BlockDeviceMappingSpec, rootVolume bool) (capav1.Volume, []string, field.ErrorList) {
+func blockDeviceMappingSpecToVolume(fldPath *field.Path, bdm mapiv1.BlockDeviceMappingSpec, rootVolume bool) (capav1.Volume, report.Report, field.ErrorList) {
 	errs := field.ErrorList{}
-	warnings := []string{}
+	warnings := report.Report{}
 
 	if bdm.EBS == nil {
 		return capav1.Volume{}, warnings, field.ErrorList{field.Invalid(fldPath.Child("ebs"), bdm.EBS, "missing ebs configuration for block device")}
@@ -482,7 +522,7 @@ func blockDeviceMappingSpecToVolume(fldPath *field.Path, bdm mapiv1.BlockDeviceM
 	}
 
 	if rootVolume && !ptr.Deref(bdm.EBS.DeleteOnTermination, true) {
-		warnings = append(warnings, field.Invalid(fldPath.Child("ebs", "deleteOnTermination"), bdm.EBS.DeleteOnTermination, "root volume must be deleted on termination, ignoring invalid value false").Error())
+		warnings = append(warnings, report.Defaulted(fldPath.Child("ebs", "deleteOnTermination"), bdm.EBS.DeleteOnTermination, report.ReasonValueOverridden, "root volume must be deleted on termination, ignoring invalid value false"))
 	} else if !rootVolume && !ptr.Deref(bdm.EBS.DeleteOnTermination, true) {
 		// TODO(OCPCLOUD-2717): We should support a non-true value for non-root volumes for feature parity.
 		errs = append(errs, field.Invalid(fldPath.Child("ebs", "deleteOnTermination"), bdm.EBS.DeleteOnTermination, "non-root volumes must be deleted on termination, unsupported value false"))
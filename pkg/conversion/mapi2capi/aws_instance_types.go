@@ -0,0 +1,118 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package mapi2capi
+
+import (
+	"strconv"
+
+	conversionutil "github.com/openshift/cluster-capi-operator/pkg/conversion/util"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// awsInstanceTypeCapacity is the capacity of an AWS instance type, as used by the autoscaler to scale from zero.
+type awsInstanceTypeCapacity struct {
+	vCPU     int64
+	memoryMb int64
+	gpu      int64
+}
+
+// awsInstanceTypes returns the capacity of the AWS instance types commonly used by OpenShift MachineSets.
+// Instance types missing from the table rely on the scale-from-zero annotations set by the MAPI MachineSet controller,
+// and get no capacity annotation from the conversion.
+//
+// The table is maintained by hand from the EC2 DescribeInstanceTypes API. Entries are added or refreshed from:
+//
+//	aws ec2 describe-instance-types --instance-types <type>... \
+//	  --query 'InstanceTypes[].[InstanceType,VCpuInfo.DefaultVCpus,MemoryInfo.SizeInMiB,GpuInfo.Gpus[0].Count]' --output text
+//
+// The capacity of an instance type does not change once it is released, so the table only grows with new instance types.
+func awsInstanceTypes() map[string]awsInstanceTypeCapacity {
+	return map[string]awsInstanceTypeCapacity{
+		"m5.large":      {vCPU: 2, memoryMb: 8192},
+		"m5.xlarge":     {vCPU: 4, memoryMb: 16384},
+		"m5.2xlarge":    {vCPU: 8, memoryMb: 32768},
+		"m5.4xlarge":    {vCPU: 16, memoryMb: 65536},
+		"m5.8xlarge":    {vCPU: 32, memoryMb: 131072},
+		"m5.12xlarge":   {vCPU: 48, memoryMb: 196608},
+		"m5.16xlarge":   {vCPU: 64, memoryMb: 262144},
+		"m5.24xlarge":   {vCPU: 96, memoryMb: 393216},
+		"m6i.large":     {vCPU: 2, memoryMb: 8192},
+		"m6i.xlarge":    {vCPU: 4, memoryMb: 16384},
+		"m6i.2xlarge":   {vCPU: 8, memoryMb: 32768},
+		"m6i.4xlarge":   {vCPU: 16, memoryMb: 65536},
+		"m6i.8xlarge":   {vCPU: 32, memoryMb: 131072},
+		"m6i.12xlarge":  {vCPU: 48, memoryMb: 196608},
+		"m6i.16xlarge":  {vCPU: 64, memoryMb: 262144},
+		"m6i.24xlarge":  {vCPU: 96, memoryMb: 393216},
+		"m6i.32xlarge":  {vCPU: 128, memoryMb: 524288},
+		"c5.large":      {vCPU: 2, memoryMb: 4096},
+		"c5.xlarge":     {vCPU: 4, memoryMb: 8192},
+		"c5.2xlarge":    {vCPU: 8, memoryMb: 16384},
+		"c5.4xlarge":    {vCPU: 16, memoryMb: 32768},
+		"c5.9xlarge":    {vCPU: 36, memoryMb: 73728},
+		"c5.12xlarge":   {vCPU: 48, memoryMb: 98304},
+		"c5.18xlarge":   {vCPU: 72, memoryMb: 147456},
+		"c5.24xlarge":   {vCPU: 96, memoryMb: 196608},
+		"r5.large":      {vCPU: 2, memoryMb: 16384},
+		"r5.xlarge":     {vCPU: 4, memoryMb: 32768},
+		"r5.2xlarge":    {vCPU: 8, memoryMb: 65536},
+		"r5.4xlarge":    {vCPU: 16, memoryMb: 131072},
+		"r5.8xlarge":    {vCPU: 32, memoryMb: 262144},
+		"r5.12xlarge":   {vCPU: 48, memoryMb: 393216},
+		"r5.16xlarge":   {vCPU: 64, memoryMb: 524288},
+		"r5.24xlarge":   {vCPU: 96, memoryMb: 786432},
+		"g4dn.xlarge":   {vCPU: 4, memoryMb: 16384, gpu: 1},
+		"g4dn.2xlarge":  {vCPU: 8, memoryMb: 32768, gpu: 1},
+		"g4dn.4xlarge":  {vCPU: 16, memoryMb: 65536, gpu: 1},
+		"g4dn.8xlarge":  {vCPU: 32, memoryMb: 131072, gpu: 1},
+		"g4dn.12xlarge": {vCPU: 48, memoryMb: 196608, gpu: 4},
+		"g4dn.16xlarge": {vCPU: 64, memoryMb: 262144, gpu: 1},
+		"p3.2xlarge":    {vCPU: 8, memoryMb: 62464, gpu: 1},
+		"p3.8xlarge":    {vCPU: 32, memoryMb: 249856, gpu: 4},
+		"p3.16xlarge":   {vCPU: 64, memoryMb: 499712, gpu: 8},
+	}
+}
+
+// setAWSCapacityAnnotations populates the CAPI scale-from-zero capacity annotations from the instance type,
+// so the autoscaler keeps scaling the MachineSet from zero once it is authoritative.
+// Capacity annotations already carried over from the MAPI MachineSet take precedence.
+func setAWSCapacityAnnotations(annotations map[string]string, instanceType string) map[string]string {
+	capacity, ok := awsInstanceTypes()[instanceType]
+	if !ok {
+		return annotations
+	}
+
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	setDefault := func(key, value string) {
+		if _, ok := annotations[key]; !ok {
+			annotations[key] = value
+		}
+	}
+
+	setDefault(conversionutil.CAPICPUCapacityAnnotation, strconv.FormatInt(capacity.vCPU, 10))
+	setDefault(conversionutil.CAPIMemoryCapacityAnnotation, resource.NewQuantity(capacity.memoryMb*mebibyte, resource.BinarySI).String())
+
+	if capacity.gpu > 0 {
+		setDefault(conversionutil.CAPIGPUCountCapacityAnnotation, strconv.FormatInt(capacity.gpu, 10))
+		setDefault(conversionutil.CAPIGPUTypeCapacityAnnotation, conversionutil.DefaultGPUType)
+	}
+
+	return annotations
+}
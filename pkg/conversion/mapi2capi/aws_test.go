@@ -82,7 +82,7 @@ var _ = Describe("mapi2capi AWS conversion", func() {
 		func(in awsMAPI2CAPIConversionInput) {
 			_, _, warns, err := FromAWSMachineAndInfra(in.machineBuilder.Build(), in.infra).ToMachineAndInfrastructureMachine()
 			Expect(err).To(matchers.ConsistOfMatchErrorSubstrings(in.expectedErrors), "should match expected errors while converting an AWS MAPI Machine to CAPI")
-			Expect(warns).To(matchers.ConsistOfSubstrings(in.expectedWarnings), "should match expected warnings while converting an AWS MAPI Machine to CAPI")
+			Expect(warns.Warnings()).To(matchers.ConsistOfSubstrings(in.expectedWarnings), "should match expected warnings while converting an AWS MAPI Machine to CAPI")
 		},
 
 		// Base Case.
@@ -326,7 +326,7 @@ var _ = Describe("mapi2capi AWS conversion", func() {
 		func(in awsMAPI2CAPIMachinesetConversionInput) {
 			_, _, warns, err := FromAWSMachineSetAndInfra(in.machineSetBuilder.Build(), in.infra).ToMachineSetAndMachineTemplate()
 			Expect(err).To(matchers.ConsistOfMatchErrorSubstrings(in.expectedErrors), "should match expected errors while converting an AWS MAPI MachineSet to CAPI")
-			Expect(warns).To(matchers.ConsistOfSubstrings(in.expectedWarnings), "should match expected warnings while converting an AWS MAPI MachineSet to CAPI")
+			Expect(warns.Warnings()).To(matchers.ConsistOfSubstrings(in.expectedWarnings), "should match expected warnings while converting an AWS MAPI MachineSet to CAPI")
 		},
 
 		Entry("With a Base configuration", awsMAPI2CAPIMachinesetConversionInput{
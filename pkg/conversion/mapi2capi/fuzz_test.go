@@ -0,0 +1,85 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package mapi2capi_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/cluster-capi-operator/pkg/conversion"
+	conversiontest "github.com/openshift/cluster-capi-operator/pkg/conversion/test/fuzz"
+
+	"k8s.io/apimachinery/pkg/api/apitesting/fuzzer"
+)
+
+const (
+	mapiNamespace = "openshift-machine-api"
+)
+
+var _ = Describe("Platform Fuzz (mapi2capi)", func() {
+	infra := &configv1.Infrastructure{
+		Spec: configv1.InfrastructureSpec{},
+		Status: configv1.InfrastructureStatus{
+			InfrastructureName: "sample-cluster-name",
+		},
+	}
+
+	for _, platform := range conversion.Platforms() {
+		Context(string(platform.Type), func() {
+			in, ok := conversiontest.InputsFor(platform.Type)
+			if !ok {
+				It("should have fuzz inputs for the registered platform", func() {
+					Fail("no fuzz inputs for platform " + string(platform.Type))
+				})
+
+				return
+			}
+
+			Context("Machine Conversion", func() {
+				fuzzerFuncs := append([]fuzzer.FuzzerFuncs{
+					conversiontest.ObjectMetaFuzzerFuncs(mapiNamespace),
+					conversiontest.MAPIMachineFuzzerFuncs(in.ProviderSpec, in.ProviderIDFuzzer),
+				}, in.ProviderSpecFuzzerFuncs...)
+
+				conversiontest.MAPI2CAPIMachineRoundTripFuzzTest(
+					scheme,
+					infra,
+					in.InfraCluster,
+					platform.FromMAPIMachine,
+					conversiontest.CAPI2MAPIMachineConverterFromPlatform(platform),
+					fuzzerFuncs...,
+				)
+			})
+
+			Context("MachineSet Conversion", func() {
+				fuzzerFuncs := append([]fuzzer.FuzzerFuncs{
+					conversiontest.ObjectMetaFuzzerFuncs(mapiNamespace),
+					conversiontest.MAPIMachineFuzzerFuncs(in.ProviderSpec, in.ProviderIDFuzzer),
+					conversiontest.MAPIMachineSetFuzzerFuncs(),
+				}, in.ProviderSpecFuzzerFuncs...)
+
+				conversiontest.MAPI2CAPIMachineSetRoundTripFuzzTest(
+					scheme,
+					infra,
+					in.InfraCluster,
+					platform.FromMAPIMachineSet,
+					conversiontest.CAPI2MAPIMachineSetConverterFromPlatform(platform),
+					fuzzerFuncs...,
+				)
+			})
+		})
+	}
+})
@@ -16,16 +16,19 @@ limitations under the License.
 package mapi2capi
 
 import (
+	"github.com/openshift/cluster-capi-operator/pkg/conversion/report"
+
 	capiv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // Machine represents a type holding MAPI Machine.
 type Machine interface {
-	ToMachineAndInfrastructureMachine() (*capiv1.Machine, client.Object, []string, error)
+	ToMachineAndInfrastructureMachine() (*capiv1.Machine, client.Object, report.Report, error)
 }
 
 // MachineSet represents a type holding MAPI MachineSet.
 type MachineSet interface {
-	ToMachineSetAndMachineTemplate() (*capiv1.MachineSet, client.Object, []string, error)
+	ToMachineSetAndMachineTemplate() (*capiv1.MachineSet, client.Object, report.Report, error)
+	ToMachineDeploymentAndMachineTemplate() (*capiv1.MachineDeployment, client.Object, report.Report, error)
 }
@@ -48,7 +48,7 @@ var _ = Describe("mapi2capi Machine conversion", func() {
 			).ToMachineAndInfrastructureMachine()
 			Expect(err).To(matchers.ConsistOfMatchErrorSubstrings(in.expectedErrors),
 				"should match expected errors while converting MAPI Machine to CAPI Machine")
-			Expect(warns).To(matchers.ConsistOfSubstrings(in.expectedWarnings),
+			Expect(warns.Warnings()).To(matchers.ConsistOfSubstrings(in.expectedWarnings),
 				"should match expected warnings while converting MAPI Machine to CAPI Machine")
 		},
 
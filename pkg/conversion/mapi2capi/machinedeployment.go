@@ -0,0 +1,134 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package mapi2capi
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+
+	conversionutil "github.com/openshift/cluster-capi-operator/pkg/conversion/util"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+	capiv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// fromCAPIMachineSetToCAPIMachineDeployment takes a CAPI MachineSet converted from a MAPI MachineSet
+// and returns the equivalent CAPI MachineDeployment with a RollingUpdate strategy.
+// Old MachineSets are deleted once scaled down, so that their InfraMachineTemplates can be garbage collected.
+func fromCAPIMachineSetToCAPIMachineDeployment(capiMachineSet *capiv1.MachineSet) (*capiv1.MachineDeployment, field.ErrorList) {
+	annotations, rollingUpdate, errs := convertMachineDeploymentAnnotationsToCAPI(capiMachineSet)
+
+	capiMachineDeployment := &capiv1.MachineDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        capiMachineSet.Name,
+			Namespace:   capiMachineSet.Namespace,
+			Labels:      capiMachineSet.Labels,
+			Annotations: annotations,
+		},
+		Spec: capiv1.MachineDeploymentSpec{
+			ClusterName: capiMachineSet.Spec.ClusterName,
+			Replicas:    capiMachineSet.Spec.Replicas,
+			Selector:    capiMachineSet.Spec.Selector,
+			Template:    capiMachineSet.Spec.Template,
+			Strategy: &capiv1.MachineDeploymentStrategy{
+				Type:          capiv1.RollingUpdateMachineDeploymentStrategyType,
+				RollingUpdate: rollingUpdate,
+			},
+			MinReadySeconds:      ptr.To(capiMachineSet.Spec.MinReadySeconds),
+			RevisionHistoryLimit: ptr.To[int32](0),
+		},
+	}
+
+	return capiMachineDeployment, errs
+}
+
+// convertMachineDeploymentAnnotationsToCAPI splits the MachineDeployment annotations of a CAPI MachineSet
+// into its RollingUpdate strategy and the annotations which are copied over verbatim.
+func convertMachineDeploymentAnnotationsToCAPI(capiMachineSet *capiv1.MachineSet) (map[string]string, *capiv1.MachineRollingUpdateDeployment, field.ErrorList) {
+	var errs field.ErrorList
+
+	fldPath := field.NewPath("metadata", "annotations")
+
+	rollingUpdate := &capiv1.MachineRollingUpdateDeployment{}
+
+	if capiMachineSet.Spec.DeletePolicy != "" {
+		rollingUpdate.DeletePolicy = ptr.To(capiMachineSet.Spec.DeletePolicy)
+	}
+
+	annotations := map[string]string{}
+
+	for k, v := range capiMachineSet.Annotations {
+		switch k {
+		case conversionutil.MachineDeploymentAnnotation:
+			// The opt-in annotation is implied by the MachineDeployment existing.
+		case conversionutil.MachineDeploymentMaxSurgeAnnotation:
+			maxSurge, err := convertIntOrPercentToCAPI(fldPath.Key(k), v)
+			if err != nil {
+				errs = append(errs, err)
+			}
+
+			rollingUpdate.MaxSurge = maxSurge
+		case conversionutil.MachineDeploymentMaxUnavailableAnnotation:
+			maxUnavailable, err := convertIntOrPercentToCAPI(fldPath.Key(k), v)
+			if err != nil {
+				errs = append(errs, err)
+			}
+
+			rollingUpdate.MaxUnavailable = maxUnavailable
+		default:
+			annotations[k] = v
+		}
+	}
+
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+
+	return annotations, rollingUpdate, errs
+}
+
+// convertIntOrPercentToCAPI parses a maxSurge or maxUnavailable annotation value into an IntOrString.
+func convertIntOrPercentToCAPI(fldPath *field.Path, value string) (*intstr.IntOrString, *field.Error) {
+	intOrPercent := intstr.Parse(value)
+
+	scaled, err := intstr.GetScaledValueFromIntOrPercent(&intOrPercent, 100, true)
+	if err != nil || scaled < 0 {
+		return nil, field.Invalid(fldPath, value, "must be a non-negative integer or percentage")
+	}
+
+	return &intOrPercent, nil
+}
+
+// infraMachineTemplateNameWithHash returns the name of an InfraMachineTemplate suffixed with a hash of its spec.
+// InfraMachineTemplates are immutable, so MachineDeployments roll out a change in the
+// providerSpec by referencing a new InfraMachineTemplate.
+func infraMachineTemplateNameWithHash(name string, spec interface{}) (string, error) {
+	specBytes, err := json.Marshal(spec)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal infrastructure machine template spec: %w", err)
+	}
+
+	hasher := fnv.New32a()
+	// The fnv hasher never returns an error on write.
+	_, _ = hasher.Write(specBytes)
+
+	return fmt.Sprintf("%s-%s", name, rand.SafeEncodeString(fmt.Sprint(hasher.Sum32()))), nil
+}
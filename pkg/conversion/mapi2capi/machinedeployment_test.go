@@ -0,0 +1,146 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package mapi2capi
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	configbuilder "github.com/openshift/cluster-api-actuator-pkg/testutils/resourcebuilder/config/v1"
+	machinebuilder "github.com/openshift/cluster-api-actuator-pkg/testutils/resourcebuilder/machine/v1beta1"
+
+	"github.com/openshift/cluster-capi-operator/pkg/conversion/test/matchers"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	capiv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+var _ = Describe("mapi2capi MachineDeployment conversion", func() {
+	var (
+		awsBaseProviderSpec = machinebuilder.AWSProviderSpec().WithLoadBalancers(nil).WithRegion("eu-west-2")
+		mapiMachineSetBase  = machinebuilder.MachineSet().WithName("worker").WithProviderSpecBuilder(awsBaseProviderSpec)
+		infraBase           = configbuilder.Infrastructure().AsAWS("test", "eu-west-2")
+	)
+
+	type mapi2CAPIMachineDeploymentConversionInput struct {
+		machineSetBuilder machinebuilder.MachineSetBuilder
+		expectedErrors    []string
+		expectedWarnings  []string
+	}
+
+	var _ = DescribeTable("mapi2capi convert MAPI MachineSet to CAPI MachineDeployment",
+		func(in mapi2CAPIMachineDeploymentConversionInput) {
+			_, _, warns, err := FromAWSMachineSetAndInfra(
+				in.machineSetBuilder.Build(),
+				infraBase.Build(),
+			).ToMachineDeploymentAndMachineTemplate()
+			Expect(err).To(matchers.ConsistOfMatchErrorSubstrings(in.expectedErrors),
+				"should match expected errors while converting MAPI MachineSet to CAPI MachineDeployment")
+			Expect(warns.Warnings()).To(matchers.ConsistOfSubstrings(in.expectedWarnings),
+				"should match expected warnings while converting MAPI MachineSet to CAPI MachineDeployment")
+		},
+
+		// Base Case
+		Entry("With a Base configuration", mapi2CAPIMachineDeploymentConversionInput{
+			machineSetBuilder: mapiMachineSetBase,
+			expectedErrors:    []string{},
+			expectedWarnings:  []string{},
+		}),
+
+		Entry("With valid rolling update annotations", mapi2CAPIMachineDeploymentConversionInput{
+			machineSetBuilder: mapiMachineSetBase.WithAnnotations(map[string]string{
+				"machine.openshift.io/cluster-api-machine-deployment":                 "true",
+				"machine.openshift.io/cluster-api-machine-deployment-max-surge":       "25%",
+				"machine.openshift.io/cluster-api-machine-deployment-max-unavailable": "0",
+			}),
+			expectedErrors:   []string{},
+			expectedWarnings: []string{},
+		}),
+
+		Entry("With an invalid max surge annotation", mapi2CAPIMachineDeploymentConversionInput{
+			machineSetBuilder: mapiMachineSetBase.WithAnnotations(map[string]string{
+				"machine.openshift.io/cluster-api-machine-deployment-max-surge": "lots",
+			}),
+			expectedErrors:   []string{"metadata.annotations[machine.openshift.io/cluster-api-machine-deployment-max-surge]: Invalid value: \"lots\": must be a non-negative integer or percentage"},
+			expectedWarnings: []string{},
+		}),
+
+		Entry("With a negative max unavailable annotation", mapi2CAPIMachineDeploymentConversionInput{
+			machineSetBuilder: mapiMachineSetBase.WithAnnotations(map[string]string{
+				"machine.openshift.io/cluster-api-machine-deployment-max-unavailable": "-1",
+			}),
+			expectedErrors:   []string{"metadata.annotations[machine.openshift.io/cluster-api-machine-deployment-max-unavailable]: Invalid value: \"-1\": must be a non-negative integer or percentage"},
+			expectedWarnings: []string{},
+		}),
+	)
+
+	It("should configure a rolling update strategy from the annotations", func() {
+		capiMachineDeployment, _, _, err := FromAWSMachineSetAndInfra(
+			mapiMachineSetBase.WithAnnotations(map[string]string{
+				"machine.openshift.io/cluster-api-machine-deployment":                 "true",
+				"machine.openshift.io/cluster-api-machine-deployment-max-surge":       "25%",
+				"machine.openshift.io/cluster-api-machine-deployment-max-unavailable": "1",
+				"foo": "bar",
+			}).WithDeletePolicy("Oldest").Build(),
+			infraBase.Build(),
+		).ToMachineDeploymentAndMachineTemplate()
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(capiMachineDeployment.Annotations).To(Equal(map[string]string{
+			"capacity.cluster-autoscaler.kubernetes.io/cpu":    "4",
+			"capacity.cluster-autoscaler.kubernetes.io/memory": "16Gi",
+			"foo": "bar",
+		}))
+		Expect(capiMachineDeployment.Spec.Strategy).To(Equal(&capiv1.MachineDeploymentStrategy{
+			Type: capiv1.RollingUpdateMachineDeploymentStrategyType,
+			RollingUpdate: &capiv1.MachineRollingUpdateDeployment{
+				MaxSurge:       &intstr.IntOrString{Type: intstr.String, StrVal: "25%"},
+				MaxUnavailable: &intstr.IntOrString{Type: intstr.Int, IntVal: 1},
+				DeletePolicy:   ptr.To("Oldest"),
+			},
+		}))
+	})
+
+	It("should reference an infrastructure machine template named after its spec", func() {
+		capiMachineDeployment, capaMachineTemplate, _, err := FromAWSMachineSetAndInfra(
+			mapiMachineSetBase.Build(),
+			infraBase.Build(),
+		).ToMachineDeploymentAndMachineTemplate()
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(capaMachineTemplate.GetName()).To(HavePrefix("worker-"))
+		Expect(capiMachineDeployment.Spec.Template.Spec.InfrastructureRef.Name).To(Equal(capaMachineTemplate.GetName()))
+
+		_, changedMachineTemplate, _, err := FromAWSMachineSetAndInfra(
+			mapiMachineSetBase.WithProviderSpecBuilder(awsBaseProviderSpec.WithInstanceType("m6i.4xlarge")).Build(),
+			infraBase.Build(),
+		).ToMachineDeploymentAndMachineTemplate()
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(changedMachineTemplate.GetName()).ToNot(Equal(capaMachineTemplate.GetName()),
+			"a change in the providerSpec should result in a new infrastructure machine template")
+	})
+
+	It("should not keep old machine sets around, so their infrastructure machine templates can be deleted", func() {
+		capiMachineDeployment, _, _, err := FromAWSMachineSetAndInfra(
+			mapiMachineSetBase.Build(),
+			infraBase.Build(),
+		).ToMachineDeploymentAndMachineTemplate()
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(capiMachineDeployment).To(HaveField("Spec.RevisionHistoryLimit", HaveValue(BeEquivalentTo(0))))
+	})
+})
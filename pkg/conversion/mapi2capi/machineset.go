@@ -19,21 +19,24 @@ import (
 	mapiv1 "github.com/openshift/api/machine/v1beta1"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	capiv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
 // fromMAPIMachineSetToCAPIMachineSet takes a MAPI MachineSet and returns a converted CAPI MachineSet.
-func fromMAPIMachineSetToCAPIMachineSet(mapiMachineSet *mapiv1.MachineSet) (*capiv1.MachineSet, utilerrors.Aggregate) {
+func fromMAPIMachineSetToCAPIMachineSet(mapiMachineSet *mapiv1.MachineSet) (*capiv1.MachineSet, field.ErrorList) {
 	var errs field.ErrorList
 
+	// MachineAutoscaler and scale-from-zero annotations use different keys in CAPI.
+	capiAnnotations, annotationErrs := convertMAPIMachineSetAnnotationsToCAPI(field.NewPath("metadata", "annotations"), mapiMachineSet.Annotations)
+	errs = append(errs, annotationErrs...)
+
 	capiMachineSet := &capiv1.MachineSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        mapiMachineSet.Name,
 			Namespace:   mapiMachineSet.Namespace,
 			Labels:      mapiMachineSet.Labels,
-			Annotations: mapiMachineSet.Annotations,
+			Annotations: capiAnnotations,
 			// OwnerReferences - There shouldn't be any ownerreferences on a MachineSet.
 		},
 		Spec: capiv1.MachineSetSpec{
@@ -63,5 +66,5 @@ func fromMAPIMachineSetToCAPIMachineSet(mapiMachineSet *mapiv1.MachineSet) (*cap
 
 	// AuthoritativeAPI - Ignore, this is part of the conversion mechanism.
 
-	return capiMachineSet, errs.ToAggregate()
+	return capiMachineSet, errs
 }
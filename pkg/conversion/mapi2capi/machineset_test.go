@@ -50,7 +50,7 @@ var _ = Describe("mapi2capi MachineSet conversion", func() {
 			).ToMachineSetAndMachineTemplate()
 			Expect(err).To(matchers.ConsistOfMatchErrorSubstrings(in.expectedErrors),
 				"should match expected errors while converting MAPI MachineSet to CAPI MachineSet")
-			Expect(warns).To(matchers.ConsistOfSubstrings(in.expectedWarnings),
+			Expect(warns.Warnings()).To(matchers.ConsistOfSubstrings(in.expectedWarnings),
 				"should match expected warnings while converting MAPI MachineSet to CAPI MachineSet")
 		},
 
@@ -109,5 +109,96 @@ var _ = Describe("mapi2capi MachineSet conversion", func() {
 			expectedErrors:   []string{"spec.metadata.ownerReferences: Invalid value: []v1.OwnerReference{v1.OwnerReference{APIVersion:\"v1\", Kind:\"Pod\", Name:\"test-pod\", UID:\"test-uid\", Controller:(*bool)(nil), BlockOwnerDeletion:(*bool)(nil)}}: ownerReferences are not supported"},
 			expectedWarnings: []string{},
 		}),
+
+		Entry("With an invalid memory scale-from-zero annotation", mapi2CAPIMachinesetConversionInput{
+			infraBuilder: infraBase,
+			machineSetBuilder: mapiMachineSetBase.WithAnnotations(map[string]string{
+				"machine.openshift.io/memoryMb": "16Gi",
+			}),
+			expectedErrors:   []string{"metadata.annotations[machine.openshift.io/memoryMb]: Invalid value: \"16Gi\": memory annotation must be an integer number of MiB"},
+			expectedWarnings: []string{},
+		}),
+
+		Entry("With an invalid vCPU scale-from-zero annotation", mapi2CAPIMachinesetConversionInput{
+			infraBuilder: infraBase,
+			machineSetBuilder: mapiMachineSetBase.WithAnnotations(map[string]string{
+				"machine.openshift.io/vCPU": "500m",
+			}),
+			expectedErrors:   []string{"metadata.annotations[machine.openshift.io/vCPU]: Invalid value: \"500m\": vCPU annotation must be an integer number of CPUs"},
+			expectedWarnings: []string{},
+		}),
 	)
+
+	It("should translate the autoscaler annotations to their CAPI equivalents", func() {
+		capiMachineSet, _, warns, err := FromAWSMachineSetAndInfra(
+			mapiMachineSetBase.WithAnnotations(map[string]string{
+				"machine.openshift.io/cluster-api-autoscaler-node-group-min-size": "1",
+				"machine.openshift.io/cluster-api-autoscaler-node-group-max-size": "5",
+				"machine.openshift.io/vCPU":                                       "4",
+				"machine.openshift.io/memoryMb":                                   "16384",
+				"machine.openshift.io/GPU":                                        "1",
+				"machine.openshift.io/maxPods":                                    "250",
+				"foo":                                                             "bar",
+			}).Build(),
+			infraBase.Build(),
+		).ToMachineSetAndMachineTemplate()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(warns).To(BeEmpty())
+
+		Expect(capiMachineSet.Annotations).To(Equal(map[string]string{
+			"cluster.x-k8s.io/cluster-api-autoscaler-node-group-min-size": "1",
+			"cluster.x-k8s.io/cluster-api-autoscaler-node-group-max-size": "5",
+			"capacity.cluster-autoscaler.kubernetes.io/cpu":               "4",
+			"capacity.cluster-autoscaler.kubernetes.io/memory":            "16Gi",
+			"capacity.cluster-autoscaler.kubernetes.io/gpu-count":         "1",
+			"capacity.cluster-autoscaler.kubernetes.io/gpu-type":          "nvidia.com/gpu",
+			"capacity.cluster-autoscaler.kubernetes.io/maxPods":           "250",
+			"foo": "bar",
+		}))
+	})
+
+	It("should populate the scale-from-zero capacity annotations from the instance type", func() {
+		capiMachineSet, _, warns, err := FromAWSMachineSetAndInfra(
+			machinebuilder.MachineSet().WithProviderSpecBuilder(awsBaseProviderSpec.WithInstanceType("g4dn.xlarge")).Build(),
+			infraBase.Build(),
+		).ToMachineSetAndMachineTemplate()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(warns).To(BeEmpty())
+
+		Expect(capiMachineSet.Annotations).To(Equal(map[string]string{
+			"capacity.cluster-autoscaler.kubernetes.io/cpu":       "4",
+			"capacity.cluster-autoscaler.kubernetes.io/memory":    "16Gi",
+			"capacity.cluster-autoscaler.kubernetes.io/gpu-count": "1",
+			"capacity.cluster-autoscaler.kubernetes.io/gpu-type":  "nvidia.com/gpu",
+		}))
+	})
+
+	It("should prefer the MAPI scale-from-zero annotations over the instance type", func() {
+		capiMachineSet, _, _, err := FromAWSMachineSetAndInfra(
+			mapiMachineSetBase.WithAnnotations(map[string]string{"machine.openshift.io/memoryMb": "15360"}).Build(),
+			infraBase.Build(),
+		).ToMachineSetAndMachineTemplate()
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(capiMachineSet.Annotations).To(HaveKeyWithValue("capacity.cluster-autoscaler.kubernetes.io/memory", "15Gi"))
+		Expect(capiMachineSet.Annotations).To(HaveKeyWithValue("capacity.cluster-autoscaler.kubernetes.io/cpu", "4"))
+	})
+
+	It("should not populate the scale-from-zero capacity annotations of an unknown instance type", func() {
+		capiMachineSet, _, _, err := FromAWSMachineSetAndInfra(
+			machinebuilder.MachineSet().WithProviderSpecBuilder(awsBaseProviderSpec.WithInstanceType("unknown.xlarge")).Build(),
+			infraBase.Build(),
+		).ToMachineSetAndMachineTemplate()
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(capiMachineSet.Annotations).To(BeEmpty())
+	})
+
+	It("should only list the instance types with a known capacity", func() {
+		for instanceType, capacity := range awsInstanceTypes() {
+			Expect(capacity.vCPU).To(BeNumerically(">", 0), instanceType)
+			Expect(capacity.memoryMb).To(BeNumerically(">", 0), instanceType)
+			Expect(capacity.gpu).To(BeNumerically(">=", 0), instanceType)
+		}
+	})
 })
@@ -0,0 +1,422 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package offline
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	configv1 "github.com/openshift/api/config/v1"
+	mapiv1 "github.com/openshift/api/machine/v1beta1"
+	"github.com/openshift/cluster-capi-operator/pkg/conversion"
+	"github.com/openshift/cluster-capi-operator/pkg/conversion/report"
+	conversionutil "github.com/openshift/cluster-capi-operator/pkg/conversion/util"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	capiv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	// errUnsupportedKind is returned for a resource which can not be converted.
+	errUnsupportedKind = errors.New("unsupported kind")
+
+	// errNoInfrastructure is returned when the input holds no Infrastructure.
+	errNoInfrastructure = errors.New("no Infrastructure found")
+
+	// errReferenceNotFound is returned when a resource referenced by a CAPI resource is not in the input.
+	errReferenceNotFound = errors.New("referenced resource not found in input")
+)
+
+// Result is the result of the conversion of a single resource.
+type Result struct {
+	// Resource identifies the converted resource, as kind namespace/name.
+	Resource string
+
+	// Objects are the resources the resource was converted to.
+	Objects []client.Object
+
+	// Report lists the fields affected by the conversion.
+	Report report.Report
+
+	// Err is set when the resource could not be converted.
+	Err error
+}
+
+// Converter converts MAPI resources to CAPI resources, and CAPI resources to MAPI resources,
+// without a cluster. It uses the conversions registered for the platform of the Infrastructure.
+type Converter struct {
+	infra         *configv1.Infrastructure
+	platform      conversion.Platform
+	scheme        *runtime.Scheme
+	capiNamespace string
+	mapiNamespace string
+}
+
+// NewConverter returns a Converter for the given Infrastructure.
+// The converted resources are placed in the given CAPI and MAPI namespaces.
+func NewConverter(infra *configv1.Infrastructure, capiNamespace, mapiNamespace string) (*Converter, error) {
+	platformType := infra.Status.Platform //nolint:staticcheck
+	if infra.Status.PlatformStatus != nil {
+		platformType = infra.Status.PlatformStatus.Type
+	}
+
+	platform, err := conversion.PlatformFor(platformType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversion for platform: %w", err)
+	}
+
+	scheme := runtime.NewScheme()
+
+	for _, addToScheme := range []func(*runtime.Scheme) error{mapiv1.AddToScheme, capiv1.AddToScheme, platform.AddToScheme} {
+		if err := addToScheme(scheme); err != nil {
+			return nil, fmt.Errorf("failed to add types to scheme: %w", err)
+		}
+	}
+
+	return &Converter{
+		infra:         infra,
+		platform:      platform,
+		scheme:        scheme,
+		capiNamespace: capiNamespace,
+		mapiNamespace: mapiNamespace,
+	}, nil
+}
+
+// ReadInfrastructure reads the first Infrastructure from YAML or JSON manifests.
+func ReadInfrastructure(r io.Reader) (*configv1.Infrastructure, error) {
+	objs, err := readManifests(r)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, u := range objs {
+		if u.GroupVersionKind() != configv1.GroupVersion.WithKind("Infrastructure") {
+			continue
+		}
+
+		infra := &configv1.Infrastructure{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, infra); err != nil {
+			return nil, fmt.Errorf("failed to decode Infrastructure: %w", err)
+		}
+
+		return infra, nil
+	}
+
+	return nil, errNoInfrastructure
+}
+
+// Convert reads YAML or JSON manifests and converts every MAPI Machine and MachineSet to CAPI,
+// and every CAPI Machine, MachineSet and MachineDeployment to MAPI.
+// The InfraMachines, InfraMachineTemplates and InfraClusters referenced by the CAPI resources must be part of the manifests.
+// An error is returned when the manifests can not be read, conversion failures are returned in the results.
+func (c *Converter) Convert(r io.Reader) ([]Result, error) {
+	objs, err := c.decode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []Result{}
+
+	for _, obj := range objs {
+		result := Result{Resource: resourceName(obj)}
+
+		switch o := obj.(type) {
+		case *mapiv1.Machine:
+			result.Objects, result.Report, result.Err = c.convertMAPIMachine(o)
+		case *mapiv1.MachineSet:
+			result.Objects, result.Report, result.Err = c.convertMAPIMachineSet(o)
+		case *capiv1.Machine:
+			result.Objects, result.Report, result.Err = c.convertCAPIMachine(o, objs)
+		case *capiv1.MachineSet:
+			result.Objects, result.Report, result.Err = c.convertCAPIMachineSet(o, objs)
+		case *capiv1.MachineDeployment:
+			result.Objects, result.Report, result.Err = c.convertCAPIMachineDeployment(o, objs)
+		default:
+			// Infrastructure resources are only used as references of the CAPI resources.
+			continue
+		}
+
+		if result.Err == nil {
+			if err := c.setGroupVersionKinds(result.Objects); err != nil {
+				return nil, err
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// WriteYAML writes the objects of the successful results as a multi-document YAML stream.
+func WriteYAML(w io.Writer, results []Result) error {
+	for _, result := range results {
+		for _, obj := range result.Objects {
+			out, err := yaml.Marshal(obj)
+			if err != nil {
+				return fmt.Errorf("failed to marshal %s: %w", result.Resource, err)
+			}
+
+			if _, err := fmt.Fprintf(w, "---\n%s", out); err != nil {
+				return fmt.Errorf("failed to write %s: %w", result.Resource, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// convertMAPIMachine converts a MAPI Machine to a CAPI Machine and InfraMachine.
+func (c *Converter) convertMAPIMachine(mapiMachine *mapiv1.Machine) ([]client.Object, report.Report, error) {
+	capiMachine, infraMachine, conversionReport, err := c.platform.FromMAPIMachine(mapiMachine, c.infra).ToMachineAndInfrastructureMachine()
+	if err != nil {
+		return nil, conversionReport, fmt.Errorf("failed to convert MAPI machine to CAPI machine: %w", err)
+	}
+
+	capiMachine.SetNamespace(c.capiNamespace)
+	capiMachine.Spec.InfrastructureRef.Namespace = c.capiNamespace
+	infraMachine.SetNamespace(c.capiNamespace)
+
+	return []client.Object{capiMachine, infraMachine}, conversionReport, nil
+}
+
+// convertMAPIMachineSet converts a MAPI MachineSet to a CAPI MachineSet, or MachineDeployment when enabled
+// on the MachineSet, and an InfraMachineTemplate.
+func (c *Converter) convertMAPIMachineSet(mapiMachineSet *mapiv1.MachineSet) ([]client.Object, report.Report, error) {
+	converter := c.platform.FromMAPIMachineSet(mapiMachineSet, c.infra)
+
+	if conversionutil.IsMachineDeploymentEnabled(mapiMachineSet.Annotations) {
+		capiMachineDeployment, infraMachineTemplate, conversionReport, err := converter.ToMachineDeploymentAndMachineTemplate()
+		if err != nil {
+			return nil, conversionReport, fmt.Errorf("failed to convert MAPI machine set to CAPI machine deployment: %w", err)
+		}
+
+		capiMachineDeployment.SetNamespace(c.capiNamespace)
+		capiMachineDeployment.Spec.Template.Spec.InfrastructureRef.Namespace = c.capiNamespace
+		infraMachineTemplate.SetNamespace(c.capiNamespace)
+
+		return []client.Object{capiMachineDeployment, infraMachineTemplate}, conversionReport, nil
+	}
+
+	capiMachineSet, infraMachineTemplate, conversionReport, err := converter.ToMachineSetAndMachineTemplate()
+	if err != nil {
+		return nil, conversionReport, fmt.Errorf("failed to convert MAPI machine set to CAPI machine set: %w", err)
+	}
+
+	capiMachineSet.SetNamespace(c.capiNamespace)
+	capiMachineSet.Spec.Template.Spec.InfrastructureRef.Namespace = c.capiNamespace
+	infraMachineTemplate.SetNamespace(c.capiNamespace)
+
+	return []client.Object{capiMachineSet, infraMachineTemplate}, conversionReport, nil
+}
+
+// convertCAPIMachine converts a CAPI Machine, with its InfraMachine and InfraCluster, to a MAPI Machine.
+func (c *Converter) convertCAPIMachine(capiMachine *capiv1.Machine, objs []client.Object) ([]client.Object, report.Report, error) {
+	infraMachine, infraCluster, err := c.findReferences(capiMachine.Namespace, capiMachine.Spec.ClusterName, capiMachine.Spec.InfrastructureRef, objs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	converter, err := c.platform.FromCAPIMachine(capiMachine, infraMachine, infraCluster)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to convert CAPI machine to MAPI machine: %w", err)
+	}
+
+	mapiMachine, conversionReport, err := converter.ToMachine()
+	if err != nil {
+		return nil, conversionReport, fmt.Errorf("failed to convert CAPI machine to MAPI machine: %w", err)
+	}
+
+	mapiMachine.SetNamespace(c.mapiNamespace)
+
+	return []client.Object{mapiMachine}, conversionReport, nil
+}
+
+// convertCAPIMachineSet converts a CAPI MachineSet, with its InfraMachineTemplate and InfraCluster, to a MAPI MachineSet.
+func (c *Converter) convertCAPIMachineSet(capiMachineSet *capiv1.MachineSet, objs []client.Object) ([]client.Object, report.Report, error) {
+	infraMachineTemplate, infraCluster, err := c.findReferences(capiMachineSet.Namespace, capiMachineSet.Spec.ClusterName, capiMachineSet.Spec.Template.Spec.InfrastructureRef, objs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	converter, err := c.platform.FromCAPIMachineSet(capiMachineSet, infraMachineTemplate, infraCluster)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to convert CAPI machine set to MAPI machine set: %w", err)
+	}
+
+	return c.toMAPIMachineSet(converter.ToMachineSet())
+}
+
+// convertCAPIMachineDeployment converts a CAPI MachineDeployment, with its InfraMachineTemplate and InfraCluster, to a MAPI MachineSet.
+func (c *Converter) convertCAPIMachineDeployment(capiMachineDeployment *capiv1.MachineDeployment, objs []client.Object) ([]client.Object, report.Report, error) {
+	infraMachineTemplate, infraCluster, err := c.findReferences(capiMachineDeployment.Namespace, capiMachineDeployment.Spec.ClusterName,
+		capiMachineDeployment.Spec.Template.Spec.InfrastructureRef, objs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	converter, err := c.platform.FromCAPIMachineDeployment(capiMachineDeployment, infraMachineTemplate, infraCluster)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to convert CAPI machine deployment to MAPI machine set: %w", err)
+	}
+
+	return c.toMAPIMachineSet(converter.ToMachineSet())
+}
+
+// toMAPIMachineSet places the MAPI MachineSet converted from CAPI in the MAPI namespace.
+func (c *Converter) toMAPIMachineSet(mapiMachineSet *mapiv1.MachineSet, conversionReport report.Report, err error) ([]client.Object, report.Report, error) {
+	if err != nil {
+		return nil, conversionReport, fmt.Errorf("failed to convert to MAPI machine set: %w", err)
+	}
+
+	mapiMachineSet.SetNamespace(c.mapiNamespace)
+
+	return []client.Object{mapiMachineSet}, conversionReport, nil
+}
+
+// findReferences returns the infrastructure resource referenced by a CAPI resource,
+// and the InfraCluster of the cluster of the resource, from the decoded manifests.
+func (c *Converter) findReferences(namespace, clusterName string, infraRef corev1.ObjectReference, objs []client.Object) (client.Object, client.Object, error) {
+	infraRefNamespace := infraRef.Namespace
+	if infraRefNamespace == "" {
+		infraRefNamespace = namespace
+	}
+
+	infraObj, err := c.find(objs, infraRef.Kind, infraRefNamespace, infraRef.Name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	infraClusterGVK, err := apiutil.GVKForObject(c.platform.NewInfraCluster(), c.scheme)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get InfraCluster kind: %w", err)
+	}
+
+	infraCluster, err := c.find(objs, infraClusterGVK.Kind, namespace, clusterName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return infraObj, infraCluster, nil
+}
+
+// find returns the decoded resource with the given kind, namespace and name.
+func (c *Converter) find(objs []client.Object, kind, namespace, name string) (client.Object, error) {
+	for _, obj := range objs {
+		if obj.GetObjectKind().GroupVersionKind().Kind == kind && obj.GetNamespace() == namespace && obj.GetName() == name {
+			return obj, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s %s/%s", errReferenceNotFound, kind, namespace, name)
+}
+
+// decode reads the manifests into typed objects. Resources of kinds which are not part of
+// the MAPI, CAPI and platform infrastructure APIs are ignored, so whole directories of manifests can be read.
+func (c *Converter) decode(r io.Reader) ([]client.Object, error) {
+	manifests, err := readManifests(r)
+	if err != nil {
+		return nil, err
+	}
+
+	objs := []client.Object{}
+
+	for _, u := range manifests {
+		gvk := u.GroupVersionKind()
+
+		if !c.scheme.Recognizes(gvk) {
+			continue
+		}
+
+		obj, err := c.scheme.New(gvk)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s: %w", gvk.String(), err)
+		}
+
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, obj); err != nil {
+			return nil, fmt.Errorf("failed to decode %s %s: %w", gvk.Kind, getNamespacedName(u), err)
+		}
+
+		clientObj, ok := obj.(client.Object)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s is not an object", errUnsupportedKind, gvk.String())
+		}
+
+		clientObj.GetObjectKind().SetGroupVersionKind(gvk)
+		objs = append(objs, clientObj)
+	}
+
+	return objs, nil
+}
+
+// setGroupVersionKinds sets the apiVersion and kind of the converted objects, so that they are printed.
+func (c *Converter) setGroupVersionKinds(objs []client.Object) error {
+	for _, obj := range objs {
+		gvk, err := apiutil.GVKForObject(obj, c.scheme)
+		if err != nil {
+			return fmt.Errorf("failed to get kind of %T: %w", obj, err)
+		}
+
+		obj.GetObjectKind().SetGroupVersionKind(gvk)
+	}
+
+	return nil
+}
+
+// readManifests reads every document of a YAML or JSON stream into unstructured objects, skipping empty documents.
+func readManifests(r io.Reader) ([]*unstructured.Unstructured, error) {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(r, 4096) //nolint:mnd
+
+	objs := []*unstructured.Unstructured{}
+
+	for {
+		u := &unstructured.Unstructured{}
+
+		if err := decoder.Decode(&u.Object); errors.Is(err, io.EOF) {
+			return objs, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read manifests: %w", err)
+		}
+
+		if len(u.Object) == 0 {
+			continue
+		}
+
+		if u.GroupVersionKind() == (schema.GroupVersionKind{}) {
+			return nil, fmt.Errorf("%w: manifest has no apiVersion and kind", errUnsupportedKind)
+		}
+
+		objs = append(objs, u)
+	}
+}
+
+// resourceName returns the kind, namespace and name of an object.
+func resourceName(obj client.Object) string {
+	return fmt.Sprintf("%s %s", obj.GetObjectKind().GroupVersionKind().Kind, client.ObjectKeyFromObject(obj).String())
+}
+
+// getNamespacedName returns the namespace and name of an unstructured object.
+func getNamespacedName(u *unstructured.Unstructured) string {
+	return client.ObjectKeyFromObject(u).String()
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package offline_test
+
+import (
+	"bytes"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	configv1 "github.com/openshift/api/config/v1"
+	mapiv1 "github.com/openshift/api/machine/v1beta1"
+	capabuilder "github.com/openshift/cluster-api-actuator-pkg/testutils/resourcebuilder/cluster-api/infrastructure/v1beta2"
+	configbuilder "github.com/openshift/cluster-api-actuator-pkg/testutils/resourcebuilder/config/v1"
+	machinebuilder "github.com/openshift/cluster-api-actuator-pkg/testutils/resourcebuilder/machine/v1beta1"
+
+	"github.com/openshift/cluster-capi-operator/pkg/conversion/offline"
+	"github.com/openshift/cluster-capi-operator/pkg/conversion/report"
+
+	capav1 "sigs.k8s.io/cluster-api-provider-aws/v2/api/v1beta2"
+	capiv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+var _ = Describe("Offline conversion", func() {
+	const (
+		capiNamespace = "openshift-cluster-api"
+		mapiNamespace = "openshift-machine-api"
+	)
+
+	var converter *offline.Converter
+
+	toYAML := func(objs ...client.Object) string {
+		docs := []string{}
+
+		for _, obj := range objs {
+			out, err := yaml.Marshal(obj)
+			Expect(err).ToNot(HaveOccurred())
+
+			docs = append(docs, string(out))
+		}
+
+		return strings.Join(docs, "---\n")
+	}
+
+	convert := func(manifests string) []offline.Result {
+		results, err := converter.Convert(strings.NewReader(manifests))
+		Expect(err).ToNot(HaveOccurred())
+
+		return results
+	}
+
+	mapiMachineSet := func() *mapiv1.MachineSet {
+		machineSet := machinebuilder.MachineSet().
+			WithName("foo").
+			WithNamespace(mapiNamespace).
+			WithProviderSpecBuilder(machinebuilder.AWSProviderSpec().WithLoadBalancers(nil)).
+			Build()
+		machineSet.SetGroupVersionKind(mapiv1.GroupVersion.WithKind("MachineSet"))
+
+		return machineSet
+	}
+
+	BeforeEach(func() {
+		infra := configbuilder.Infrastructure().AsAWS("cluster", "us-east-1").WithInfrastructureName("cluster-foo").Build()
+		infra.SetGroupVersionKind(configv1.GroupVersion.WithKind("Infrastructure"))
+
+		readInfra, err := offline.ReadInfrastructure(strings.NewReader(toYAML(infra)))
+		Expect(err).ToNot(HaveOccurred())
+
+		converter, err = offline.NewConverter(readInfra, capiNamespace, mapiNamespace)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should fail to read an Infrastructure from manifests without one", func() {
+		_, err := offline.ReadInfrastructure(strings.NewReader(toYAML(mapiMachineSet())))
+		Expect(err).To(MatchError(ContainSubstring("no Infrastructure found")))
+	})
+
+	It("should fail to create a converter for an unsupported platform", func() {
+		_, err := offline.NewConverter(configbuilder.Infrastructure().AsGCP("cluster", "us-east1").Build(), capiNamespace, mapiNamespace)
+		Expect(err).To(MatchError(ContainSubstring("platform not supported")))
+	})
+
+	It("should convert a MAPI MachineSet to a CAPI MachineSet and InfraMachineTemplate", func() {
+		results := convert(toYAML(mapiMachineSet()))
+
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Resource).To(Equal("MachineSet openshift-machine-api/foo"))
+		Expect(results[0].Err).ToNot(HaveOccurred())
+		Expect(results[0].Objects).To(ConsistOf(
+			SatisfyAll(BeAssignableToTypeOf(&capiv1.MachineSet{}), HaveField("ObjectMeta.Namespace", capiNamespace), HaveField("TypeMeta.Kind", "MachineSet")),
+			SatisfyAll(BeAssignableToTypeOf(&capav1.AWSMachineTemplate{}), HaveField("ObjectMeta.Namespace", capiNamespace), HaveField("TypeMeta.Kind", "AWSMachineTemplate")),
+		))
+	})
+
+	It("should report the errors of a MAPI MachineSet which can not be converted", func() {
+		machineSet := machinebuilder.MachineSet().
+			WithName("foo").
+			WithNamespace(mapiNamespace).
+			WithProviderSpecBuilder(machinebuilder.AWSProviderSpec()).
+			Build()
+		machineSet.SetGroupVersionKind(mapiv1.GroupVersion.WithKind("MachineSet"))
+
+		results := convert(toYAML(machineSet))
+
+		Expect(results).To(HaveLen(1))
+		Expect(results[0].Err).To(MatchError(ContainSubstring("loadBalancers are not supported")))
+		Expect(results[0].Objects).To(BeEmpty())
+	})
+
+	It("should convert the CAPI resources back to a MAPI MachineSet", func() {
+		results := convert(toYAML(mapiMachineSet()))
+		Expect(results).To(HaveLen(1))
+
+		capiManifests := &bytes.Buffer{}
+		Expect(offline.WriteYAML(capiManifests, results)).To(Succeed())
+
+		awsCluster := capabuilder.AWSCluster().WithName("cluster-foo").WithNamespace(capiNamespace).Build()
+		awsCluster.SetGroupVersionKind(capav1.GroupVersion.WithKind("AWSCluster"))
+
+		mapiResults := convert(capiManifests.String() + "---\n" + toYAML(awsCluster))
+
+		Expect(mapiResults).To(HaveLen(1))
+		Expect(mapiResults[0].Err).ToNot(HaveOccurred())
+		Expect(mapiResults[0].Objects).To(ConsistOf(
+			SatisfyAll(BeAssignableToTypeOf(&mapiv1.MachineSet{}), HaveField("ObjectMeta.Name", "foo"), HaveField("ObjectMeta.Namespace", mapiNamespace)),
+		))
+	})
+
+	It("should report the errors of a CAPI MachineSet whose InfraMachineTemplate can not be converted", func() {
+		results := convert(toYAML(mapiMachineSet()))
+		Expect(results).To(HaveLen(1))
+
+		capiObjects := []client.Object{}
+
+		for _, obj := range results[0].Objects {
+			if template, ok := obj.(*capav1.AWSMachineTemplate); ok {
+				template.Spec.Template.Spec.ImageLookupOrg = "123456789012"
+			}
+
+			capiObjects = append(capiObjects, obj)
+		}
+
+		awsCluster := capabuilder.AWSCluster().WithName("cluster-foo").WithNamespace(capiNamespace).Build()
+		awsCluster.SetGroupVersionKind(capav1.GroupVersion.WithKind("AWSCluster"))
+
+		mapiResults := convert(toYAML(append(capiObjects, awsCluster)...))
+
+		Expect(mapiResults).To(HaveLen(1))
+		Expect(mapiResults[0].Err).To(MatchError(ContainSubstring("imageLookupOrg is not supported")))
+		Expect(mapiResults[0].Report).To(ContainElement(SatisfyAll(
+			HaveField("Severity", report.SeverityError),
+			HaveField("Message", ContainSubstring("imageLookupOrg is not supported")),
+		)))
+		Expect(mapiResults[0].Objects).To(BeEmpty())
+	})
+
+	It("should report a missing InfraCluster", func() {
+		results := convert(toYAML(mapiMachineSet()))
+
+		capiManifests := &bytes.Buffer{}
+		Expect(offline.WriteYAML(capiManifests, results)).To(Succeed())
+
+		mapiResults := convert(capiManifests.String())
+
+		Expect(mapiResults).To(HaveLen(1))
+		Expect(mapiResults[0].Err).To(MatchError(ContainSubstring("referenced resource not found in input: AWSCluster openshift-cluster-api/cluster-foo")))
+	})
+
+	It("should ignore resources of other kinds", func() {
+		infra := configbuilder.Infrastructure().AsAWS("cluster", "us-east-1").Build()
+		infra.SetGroupVersionKind(configv1.GroupVersion.WithKind("Infrastructure"))
+
+		Expect(convert(toYAML(infra))).To(BeEmpty())
+	})
+})
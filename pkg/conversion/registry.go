@@ -0,0 +1,127 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package conversion
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	configv1 "github.com/openshift/api/config/v1"
+	mapiv1 "github.com/openshift/api/machine/v1beta1"
+	"github.com/openshift/cluster-capi-operator/pkg/conversion/capi2mapi"
+	"github.com/openshift/cluster-capi-operator/pkg/conversion/mapi2capi"
+	"github.com/openshift/cluster-capi-operator/pkg/conversion/unsupported"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	capiv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	// errPlatformNotSupported is returned when no conversion is registered for a platform.
+	errPlatformNotSupported = errors.New("platform not supported")
+
+	// errUnexpectedInfraMachineType is returned when an InfraMachine is not of the type registered for the platform.
+	errUnexpectedInfraMachineType = errors.New("unexpected InfraMachine type")
+
+	// errUnexpectedInfraMachineTemplateType is returned when an InfraMachineTemplate is not of the type registered for the platform.
+	errUnexpectedInfraMachineTemplateType = errors.New("unexpected InfraMachineTemplate type")
+
+	// errUnexpectedInfraClusterType is returned when an InfraCluster is not of the type registered for the platform.
+	errUnexpectedInfraClusterType = errors.New("unexpected InfraCluster type")
+)
+
+// Platform describes the CAPI infrastructure types and the conversion functions of a single platform.
+type Platform struct {
+	// Type is the OpenShift platform type the conversion applies to.
+	Type configv1.PlatformType
+
+	// AddToScheme adds the CAPI infrastructure types of the platform to a scheme.
+	AddToScheme func(*runtime.Scheme) error
+
+	// NewInfraMachine returns an empty InfraMachine of the platform.
+	NewInfraMachine func() client.Object
+
+	// NewInfraMachineTemplate returns an empty InfraMachineTemplate of the platform.
+	NewInfraMachineTemplate func() client.Object
+
+	// NewInfraCluster returns an empty InfraCluster of the platform.
+	NewInfraCluster func() client.Object
+
+	// UnsupportedFields lists the InfraMachine spec fields that can not be converted to MAPI.
+	UnsupportedFields unsupported.Rules
+
+	// FromMAPIMachine wraps a MAPI Machine into a mapi2capi converter.
+	FromMAPIMachine func(*mapiv1.Machine, *configv1.Infrastructure) mapi2capi.Machine
+
+	// FromMAPIMachineSet wraps a MAPI MachineSet into a mapi2capi converter.
+	FromMAPIMachineSet func(*mapiv1.MachineSet, *configv1.Infrastructure) mapi2capi.MachineSet
+
+	// FromCAPIMachine wraps a CAPI Machine, InfraMachine and InfraCluster into a capi2mapi converter.
+	// An error is returned when the infrastructure objects are not of the types registered for the platform.
+	FromCAPIMachine func(*capiv1.Machine, client.Object, client.Object) (capi2mapi.MachineAndInfrastructureMachine, error)
+
+	// FromCAPIMachineSet wraps a CAPI MachineSet, InfraMachineTemplate and InfraCluster into a capi2mapi converter.
+	// An error is returned when the infrastructure objects are not of the types registered for the platform.
+	FromCAPIMachineSet func(*capiv1.MachineSet, client.Object, client.Object) (capi2mapi.MachineSetAndMachineTemplate, error)
+
+	// FromCAPIMachineDeployment wraps a CAPI MachineDeployment, InfraMachineTemplate and InfraCluster into a capi2mapi converter.
+	// An error is returned when the infrastructure objects are not of the types registered for the platform.
+	FromCAPIMachineDeployment func(*capiv1.MachineDeployment, client.Object, client.Object) (capi2mapi.MachineSetAndMachineTemplate, error)
+
+	// InfraMachineTemplateIsEqual determines whether two InfraMachineTemplates of the platform are equal.
+	InfraMachineTemplateIsEqual func(client.Object, client.Object) (bool, error)
+}
+
+// platforms holds the conversions of every supported platform.
+// To support a new platform, add its Platform here.
+//
+//nolint:gochecknoglobals
+var platforms = map[configv1.PlatformType]Platform{
+	configv1.AWSPlatformType: awsPlatform,
+}
+
+// PlatformFor returns the registered Platform for the given platform type.
+func PlatformFor(platformType configv1.PlatformType) (Platform, error) {
+	platform, ok := platforms[platformType]
+	if !ok {
+		return Platform{}, fmt.Errorf("%w: %s", errPlatformNotSupported, platformType)
+	}
+
+	return platform, nil
+}
+
+// Platforms returns every registered Platform, sorted by platform type.
+func Platforms() []Platform {
+	out := make([]Platform, 0, len(platforms))
+	for _, platform := range platforms {
+		out = append(out, platform)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Type < out[j].Type
+	})
+
+	return out
+}
+
+// IsPlatformSupported determines whether a conversion is registered for the given platform type.
+func IsPlatformSupported(platformType configv1.PlatformType) bool {
+	_, ok := platforms[platformType]
+
+	return ok
+}
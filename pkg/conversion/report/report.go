@@ -0,0 +1,210 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// Direction is the direction of a conversion.
+type Direction string
+
+const (
+	// MAPIToCAPI denotes a conversion from Machine API resources to Cluster API resources.
+	MAPIToCAPI Direction = "MAPIToCAPI"
+
+	// CAPIToMAPI denotes a conversion from Cluster API resources to Machine API resources.
+	CAPIToMAPI Direction = "CAPIToMAPI"
+)
+
+// Severity describes how a field was affected by a conversion.
+type Severity string
+
+const (
+	// SeverityError denotes a field that could not be converted. The conversion fails when any entry has this severity.
+	SeverityError Severity = "Error"
+
+	// SeverityLossy denotes a field whose value was dropped or altered by the conversion.
+	SeverityLossy Severity = "Lossy"
+
+	// SeverityDefaulted denotes a field whose value was replaced with a default by the conversion.
+	SeverityDefaulted Severity = "Defaulted"
+)
+
+// Reason is a machine-readable explanation of why a field was reported.
+type Reason string
+
+const (
+	// ReasonInvalidValue denotes a field whose value can not be represented in the target API.
+	ReasonInvalidValue Reason = "InvalidValue"
+
+	// ReasonRequiredValue denotes a field that is required by the target API but is missing.
+	ReasonRequiredValue Reason = "RequiredValue"
+
+	// ReasonUnsupportedValue denotes a field whose value is not supported by the target API.
+	ReasonUnsupportedValue Reason = "UnsupportedValue"
+
+	// ReasonValueIgnored denotes a field whose value was ignored by the conversion.
+	ReasonValueIgnored Reason = "ValueIgnored"
+
+	// ReasonValueRounded denotes a field whose value was rounded to the precision of the target API.
+	ReasonValueRounded Reason = "ValueRounded"
+
+	// ReasonValueOverridden denotes a field whose value was overridden by the conversion.
+	ReasonValueOverridden Reason = "ValueOverridden"
+)
+
+// Entry describes a single field affected by a conversion.
+type Entry struct {
+	// Field is the path of the field within the source resource.
+	Field string `json:"field"`
+
+	// Direction is the direction of the conversion that reported the field.
+	Direction Direction `json:"direction"`
+
+	// Severity describes how the field was affected by the conversion.
+	Severity Severity `json:"severity"`
+
+	// Reason is a machine-readable explanation of why the field was reported.
+	Reason Reason `json:"reason"`
+
+	// Message is a human readable description of the entry.
+	Message string `json:"message"`
+}
+
+// String returns the human readable message of the entry.
+func (e Entry) String() string {
+	return e.Message
+}
+
+// Report is the list of fields affected by a conversion.
+type Report []Entry
+
+// New returns a Report for a conversion in the given direction.
+// It combines the lossy and defaulted entries collected during the conversion with an error entry for each field error.
+func New(direction Direction, entries Report, errs field.ErrorList) Report {
+	out := make(Report, 0, len(entries)+len(errs))
+
+	for _, e := range entries {
+		e.Direction = direction
+		out = append(out, e)
+	}
+
+	for _, err := range errs {
+		out = append(out, Entry{
+			Field:     err.Field,
+			Direction: direction,
+			Severity:  SeverityError,
+			Reason:    reasonForFieldError(err),
+			Message:   err.Error(),
+		})
+	}
+
+	return out
+}
+
+// Lossy returns an entry for a field whose value was dropped or altered by the conversion.
+func Lossy(fldPath *field.Path, value interface{}, reason Reason, detail string) Entry {
+	return newEntry(fldPath, value, SeverityLossy, reason, detail)
+}
+
+// Defaulted returns an entry for a field whose value was replaced with a default by the conversion.
+func Defaulted(fldPath *field.Path, value interface{}, reason Reason, detail string) Entry {
+	return newEntry(fldPath, value, SeverityDefaulted, reason, detail)
+}
+
+// Warnings returns the messages of the entries that did not fail the conversion.
+func (r Report) Warnings() []string {
+	warnings := []string{}
+
+	for _, e := range r {
+		if e.Severity != SeverityError {
+			warnings = append(warnings, e.Message)
+		}
+	}
+
+	return warnings
+}
+
+// HasSeverity determines whether any entry of the Report has the given severity.
+func (r Report) HasSeverity(severity Severity) bool {
+	for _, e := range r {
+		if e.Severity == severity {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MarshalTruncated returns the JSON encoding of the leading entries of the Report that fit within maxLength bytes,
+// and the number of entries that were left out. Entries are never split, so the result may hold no entries at all.
+func (r Report) MarshalTruncated(maxLength int) ([]byte, int, error) {
+	buf := bytes.NewBufferString("[")
+
+	for i, e := range r {
+		entry, err := json.Marshal(e)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to marshal conversion report entry: %w", err)
+		}
+
+		// Account for the separator and the closing bracket.
+		if buf.Len()+len(entry)+2 > maxLength {
+			buf.WriteString("]")
+
+			return buf.Bytes(), len(r) - i, nil
+		}
+
+		if i > 0 {
+			buf.WriteString(",")
+		}
+
+		buf.Write(entry)
+	}
+
+	buf.WriteString("]")
+
+	return buf.Bytes(), 0, nil
+}
+
+// newEntry returns an entry without a direction, the direction is set once the entry is added to a Report.
+func newEntry(fldPath *field.Path, value interface{}, severity Severity, reason Reason, detail string) Entry {
+	return Entry{
+		Field:    fldPath.String(),
+		Severity: severity,
+		Reason:   reason,
+		Message:  field.Invalid(fldPath, value, detail).Error(),
+	}
+}
+
+// reasonForFieldError maps the type of a field error to a Reason.
+func reasonForFieldError(err *field.Error) Reason {
+	switch err.Type {
+	case field.ErrorTypeRequired:
+		return ReasonRequiredValue
+	case field.ErrorTypeNotSupported, field.ErrorTypeForbidden:
+		return ReasonUnsupportedValue
+	case field.ErrorTypeInvalid, field.ErrorTypeTypeInvalid, field.ErrorTypeNotFound, field.ErrorTypeDuplicate,
+		field.ErrorTypeTooLong, field.ErrorTypeTooMany, field.ErrorTypeInternal:
+		return ReasonInvalidValue
+	default:
+		return ReasonInvalidValue
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package report_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/cluster-capi-operator/pkg/conversion/report"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+var _ = Describe("Conversion Report", func() {
+	fldPath := field.NewPath("spec", "providerSpec", "value")
+
+	conversionReport := report.New(report.MAPIToCAPI,
+		report.Report{
+			report.Lossy(fldPath.Child("tenancy"), "host", report.ReasonValueIgnored, "tenancy is ignored"),
+			report.Defaulted(fldPath.Child("deleteOnTermination"), false, report.ReasonValueOverridden, "deleteOnTermination must be true"),
+		},
+		field.ErrorList{
+			field.Invalid(fldPath.Child("loadBalancers"), []string{"lb"}, "loadBalancers are not supported"),
+			field.Required(fldPath.Child("volumeSize"), "volumeSize is required"),
+			field.Forbidden(fldPath.Child("deviceIndex"), "deviceIndex is forbidden"),
+		},
+	)
+
+	It("should set the direction on every entry", func() {
+		Expect(conversionReport).To(HaveEach(HaveField("Direction", report.MAPIToCAPI)))
+	})
+
+	It("should report the field path, severity and reason of every entry", func() {
+		Expect(conversionReport).To(Equal(report.Report{
+			{
+				Field:     "spec.providerSpec.value.tenancy",
+				Direction: report.MAPIToCAPI,
+				Severity:  report.SeverityLossy,
+				Reason:    report.ReasonValueIgnored,
+				Message:   `spec.providerSpec.value.tenancy: Invalid value: "host": tenancy is ignored`,
+			},
+			{
+				Field:     "spec.providerSpec.value.deleteOnTermination",
+				Direction: report.MAPIToCAPI,
+				Severity:  report.SeverityDefaulted,
+				Reason:    report.ReasonValueOverridden,
+				Message:   "spec.providerSpec.value.deleteOnTermination: Invalid value: false: deleteOnTermination must be true",
+			},
+			{
+				Field:     "spec.providerSpec.value.loadBalancers",
+				Direction: report.MAPIToCAPI,
+				Severity:  report.SeverityError,
+				Reason:    report.ReasonInvalidValue,
+				Message:   `spec.providerSpec.value.loadBalancers: Invalid value: []string{"lb"}: loadBalancers are not supported`,
+			},
+			{
+				Field:     "spec.providerSpec.value.volumeSize",
+				Direction: report.MAPIToCAPI,
+				Severity:  report.SeverityError,
+				Reason:    report.ReasonRequiredValue,
+				Message:   "spec.providerSpec.value.volumeSize: Required value: volumeSize is required",
+			},
+			{
+				Field:     "spec.providerSpec.value.deviceIndex",
+				Direction: report.MAPIToCAPI,
+				Severity:  report.SeverityError,
+				Reason:    report.ReasonUnsupportedValue,
+				Message:   "spec.providerSpec.value.deviceIndex: Forbidden: deviceIndex is forbidden",
+			},
+		}))
+	})
+
+	It("should only return the messages of entries that did not fail the conversion as warnings", func() {
+		Expect(conversionReport.Warnings()).To(ConsistOf(
+			ContainSubstring("tenancy is ignored"),
+			ContainSubstring("deleteOnTermination must be true"),
+		))
+	})
+
+	It("should determine which severities are present", func() {
+		Expect(conversionReport.HasSeverity(report.SeverityError)).To(BeTrue())
+		Expect(conversionReport.HasSeverity(report.SeverityLossy)).To(BeTrue())
+		Expect(report.Report{}.HasSeverity(report.SeverityError)).To(BeFalse())
+	})
+
+	It("should marshal to a JSON list", func() {
+		data, err := json.Marshal(conversionReport[:1])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(Equal(`[{"field":"spec.providerSpec.value.tenancy","direction":"MAPIToCAPI","severity":"Lossy","reason":"ValueIgnored","message":"spec.providerSpec.value.tenancy: Invalid value: \"host\": tenancy is ignored"}]`))
+	})
+	It("should marshal the whole report when it fits within the maximum length", func() {
+		full, err := json.Marshal(conversionReport)
+		Expect(err).ToNot(HaveOccurred())
+
+		data, omitted, err := conversionReport.MarshalTruncated(len(full))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(omitted).To(BeZero())
+		Expect(data).To(Equal(full))
+	})
+
+	It("should only marshal the leading entries that fit within the maximum length", func() {
+		leading, err := json.Marshal(conversionReport[:2])
+		Expect(err).ToNot(HaveOccurred())
+
+		data, omitted, err := conversionReport.MarshalTruncated(len(leading) + 10)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(omitted).To(Equal(3))
+		Expect(data).To(Equal(leading))
+	})
+
+	It("should marshal an empty list when no entry fits within the maximum length", func() {
+		data, omitted, err := conversionReport.MarshalTruncated(10)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(omitted).To(Equal(len(conversionReport)))
+		Expect(string(data)).To(Equal("[]"))
+	})
+})
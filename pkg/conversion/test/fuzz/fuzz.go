@@ -29,6 +29,7 @@ import (
 	configv1 "github.com/openshift/api/config/v1"
 	mapiv1 "github.com/openshift/api/machine/v1beta1"
 	"github.com/openshift/cluster-api-actuator-pkg/testutils"
+	"github.com/openshift/cluster-capi-operator/pkg/conversion"
 	"github.com/openshift/cluster-capi-operator/pkg/conversion/capi2mapi"
 	"github.com/openshift/cluster-capi-operator/pkg/conversion/mapi2capi"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -61,6 +62,28 @@ type MAPI2CAPIMachineConverterConstructor func(*mapiv1.Machine, *configv1.Infras
 // MAPI2CAPIMachineSetConverterConstructor is a function that constructs a MAPI to CAPI MachineSet converter.
 type MAPI2CAPIMachineSetConverterConstructor func(*mapiv1.MachineSet, *configv1.Infrastructure) mapi2capi.MachineSet
 
+// CAPI2MAPIMachineConverterFromPlatform adapts the CAPI to MAPI Machine converter registered for a platform
+// into a CAPI2MAPIMachineConverterConstructor, failing the test when the infrastructure objects are of the wrong type.
+func CAPI2MAPIMachineConverterFromPlatform(platform conversion.Platform) CAPI2MAPIMachineConverterConstructor {
+	return func(machine *capiv1.Machine, infraMachine client.Object, infraCluster client.Object) capi2mapi.MachineAndInfrastructureMachine {
+		converter, err := platform.FromCAPIMachine(machine, infraMachine, infraCluster)
+		Expect(err).ToNot(HaveOccurred(), "should be able to construct the CAPI to MAPI Machine converter")
+
+		return converter
+	}
+}
+
+// CAPI2MAPIMachineSetConverterFromPlatform adapts the CAPI to MAPI MachineSet converter registered for a platform
+// into a CAPI2MAPIMachineSetConverterConstructor, failing the test when the infrastructure objects are of the wrong type.
+func CAPI2MAPIMachineSetConverterFromPlatform(platform conversion.Platform) CAPI2MAPIMachineSetConverterConstructor {
+	return func(machineSet *capiv1.MachineSet, infraMachineTemplate client.Object, infraCluster client.Object) capi2mapi.MachineSetAndMachineTemplate {
+		converter, err := platform.FromCAPIMachineSet(machineSet, infraMachineTemplate, infraCluster)
+		Expect(err).ToNot(HaveOccurred(), "should be able to construct the CAPI to MAPI MachineSet converter")
+
+		return converter
+	}
+}
+
 // StringFuzzer is a function that returns a random string.
 type StringFuzzer func(fuzz.Continue) string
 
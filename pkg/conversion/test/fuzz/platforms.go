@@ -0,0 +1,60 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package fuzz
+
+import (
+	fuzz "github.com/google/gofuzz"
+
+	configv1 "github.com/openshift/api/config/v1"
+
+	"k8s.io/apimachinery/pkg/api/apitesting/fuzzer"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PlatformInputs holds the platform specific inputs to the round trip fuzz tests of the conversions.
+// The fuzzer funcs constrain the fuzzed objects to the values the conversions support.
+type PlatformInputs struct {
+	// InfraCluster is the InfraCluster the fuzzed objects are converted with.
+	InfraCluster client.Object
+
+	// ProviderSpec is an empty MAPI providerSpec of the platform.
+	ProviderSpec runtime.Object
+
+	// ProviderIDFuzzer returns a random providerID of the platform.
+	ProviderIDFuzzer func(fuzz.Continue) string
+
+	// ProviderSpecFuzzerFuncs are the fuzzer funcs for the MAPI providerSpec.
+	ProviderSpecFuzzerFuncs []fuzzer.FuzzerFuncs
+
+	// InfraMachineFuzzerFuncs are the fuzzer funcs for the InfraMachine.
+	InfraMachineFuzzerFuncs []fuzzer.FuzzerFuncs
+
+	// InfraMachineTemplateFuzzerFuncs are the fuzzer funcs for the InfraMachineTemplate.
+	InfraMachineTemplateFuzzerFuncs []fuzzer.FuzzerFuncs
+}
+
+// InputsFor returns the fuzz test inputs of a platform registered in the conversion package.
+// It returns false when the platform has no fuzz test inputs yet.
+// To fuzz the conversions of a new platform, add its PlatformInputs here.
+func InputsFor(platformType configv1.PlatformType) (PlatformInputs, bool) {
+	switch platformType {
+	case configv1.AWSPlatformType:
+		return awsInputs(), true
+	default:
+		return PlatformInputs{}, false
+	}
+}
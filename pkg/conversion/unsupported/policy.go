@@ -0,0 +1,236 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package unsupported
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+// policyNamePrefix prefixes the names of the generated policies and bindings.
+const policyNamePrefix = "openshift-cluster-api-unsupported-"
+
+// ValidatingAdmissionPolicies returns the ValidatingAdmissionPolicies, and their bindings, denying the unsupported
+// fields on the InfraMachines and InfraMachineTemplates of the given namespace.
+// Fields with SkipAdmission set are left out of the policies.
+func (r Rules) ValidatingAdmissionPolicies(namespace string) ([]*admissionregistrationv1beta1.ValidatingAdmissionPolicy, []*admissionregistrationv1beta1.ValidatingAdmissionPolicyBinding, error) {
+	targets := []struct {
+		resource string
+		specPath []string
+	}{
+		{resource: r.InfraMachineResource, specPath: []string{"spec"}},
+		{resource: r.InfraMachineTemplateResource, specPath: []string{"spec", "template", "spec"}},
+	}
+
+	policies := []*admissionregistrationv1beta1.ValidatingAdmissionPolicy{}
+	bindings := []*admissionregistrationv1beta1.ValidatingAdmissionPolicyBinding{}
+
+	for _, target := range targets {
+		validations, err := r.validations(target.specPath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to generate validations for %s: %w", target.resource, err)
+		}
+
+		name := policyNamePrefix + target.resource
+
+		policies = append(policies, &admissionregistrationv1beta1.ValidatingAdmissionPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: admissionregistrationv1beta1.ValidatingAdmissionPolicySpec{
+				FailurePolicy: ptr.To(admissionregistrationv1beta1.Fail),
+				MatchConstraints: &admissionregistrationv1beta1.MatchResources{
+					ResourceRules: []admissionregistrationv1beta1.NamedRuleWithOperations{{
+						RuleWithOperations: admissionregistrationv1beta1.RuleWithOperations{
+							Operations: []admissionregistrationv1beta1.OperationType{admissionregistrationv1beta1.Create, admissionregistrationv1beta1.Update},
+							Rule: admissionregistrationv1beta1.Rule{
+								APIGroups:   []string{r.Group},
+								APIVersions: []string{r.Version},
+								Resources:   []string{target.resource},
+							},
+						},
+					}},
+				},
+				Validations: validations,
+			},
+		})
+
+		bindings = append(bindings, &admissionregistrationv1beta1.ValidatingAdmissionPolicyBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: admissionregistrationv1beta1.ValidatingAdmissionPolicyBindingSpec{
+				PolicyName:        name,
+				ValidationActions: []admissionregistrationv1beta1.ValidationAction{admissionregistrationv1beta1.Deny},
+				MatchResources: &admissionregistrationv1beta1.MatchResources{
+					NamespaceSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{corev1.LabelMetadataName: namespace},
+					},
+				},
+			},
+		})
+	}
+
+	return policies, bindings, nil
+}
+
+// validations returns a validation for every unsupported field, for an InfraMachine spec found at specPath in the object.
+func (r Rules) validations(specPath []string) ([]admissionregistrationv1beta1.Validation, error) {
+	validations := []admissionregistrationv1beta1.Validation{}
+
+	for _, f := range r.Fields {
+		if f.SkipAdmission {
+			continue
+		}
+
+		expression, err := r.celExpression(specPath, f.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		validations = append(validations, admissionregistrationv1beta1.Validation{
+			Expression: expression,
+			Message:    fmt.Sprintf("%s: %s", strings.Join(append(append([]string{}, specPath...), f.Path...), "."), f.Message),
+			Reason:     ptr.To(metav1.StatusReasonInvalid),
+		})
+	}
+
+	return validations, nil
+}
+
+// celExpression returns a CEL expression that is true when the field at path is not set,
+// following the same rules as the conversion checks.
+func (r Rules) celExpression(specPath, path []string) (string, error) {
+	t := reflect.TypeOf(r.Spec)
+
+	selector := "object." + strings.Join(specPath, ".")
+	conditions := []string{}
+
+	for i, name := range path {
+		index, err := fieldIndex(t, name)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", strings.Join(path[:i+1], "."), err)
+		}
+
+		if t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+
+		t = t.Field(index).Type
+		selector += "." + name
+		conditions = append(conditions, fmt.Sprintf("has(%s)", selector))
+	}
+
+	switch t.Kind() { //nolint:exhaustive
+	case reflect.Pointer:
+	case reflect.String, reflect.Slice, reflect.Map:
+		conditions = append(conditions, fmt.Sprintf("size(%s) > 0", selector))
+	case reflect.Struct, reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		nonZero, err := celNonZero(selector, t)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", strings.Join(path, "."), err)
+		}
+
+		conditions = append(conditions, "("+nonZero+")")
+	default:
+		return "", fmt.Errorf("%s: %w %s", strings.Join(path, "."), errUnsupportedFieldKind, t.Kind())
+	}
+
+	return fmt.Sprintf("!(%s)", strings.Join(conditions, " && ")), nil
+}
+
+// celNonZero returns a CEL expression that is true when the value at selector, which must be present,
+// is not the Go zero value of t once decoded. An empty expression means that being present is enough,
+// as for pointers, slices and maps which are only nil when omitted.
+// Structs are not the zero value when any of their fields is not, so an explicit false, 0 or ""
+// does not make a struct set, matching reflect.Value.IsZero.
+func celNonZero(selector string, t reflect.Type) (string, error) {
+	switch t.Kind() { //nolint:exhaustive
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface:
+		return "", nil
+	case reflect.String:
+		return fmt.Sprintf("%s != \"\"", selector), nil
+	case reflect.Bool:
+		return fmt.Sprintf("%s == true", selector), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return fmt.Sprintf("%s != 0", selector), nil
+	case reflect.Float32, reflect.Float64:
+		return fmt.Sprintf("%s != 0.0", selector), nil
+	case reflect.Struct:
+		return celStructNonZero(selector, t)
+	default:
+		return "", fmt.Errorf("%w %s", errUnsupportedFieldKind, t.Kind())
+	}
+}
+
+// celStructNonZero returns a CEL expression that is true when any serialized field of the struct at selector
+// is present and not the zero value. Inline fields are checked against the same selector.
+func celStructNonZero(selector string, t reflect.Type) (string, error) {
+	fields := []string{}
+
+	for i := range t.NumField() {
+		structField := t.Field(i)
+
+		name, _, _ := strings.Cut(structField.Tag.Get("json"), ",")
+		if !structField.IsExported() || name == "-" {
+			continue
+		}
+
+		if structField.Anonymous && name == "" {
+			embedded := structField.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+
+			nonZero, err := celStructNonZero(selector, embedded)
+			if err != nil {
+				return "", err
+			}
+
+			fields = append(fields, nonZero)
+
+			continue
+		}
+
+		if name == "" {
+			name = structField.Name
+		}
+
+		fieldSelector := selector + "." + name
+
+		nonZero, err := celNonZero(fieldSelector, structField.Type)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", name, err)
+		}
+
+		if nonZero == "" {
+			fields = append(fields, fmt.Sprintf("has(%s)", fieldSelector))
+		} else {
+			fields = append(fields, fmt.Sprintf("has(%s) && %s", fieldSelector, nonZero))
+		}
+	}
+
+	if len(fields) == 0 {
+		// The struct has no serialized fields, such as time.Time, and can not be checked in CEL.
+		return "", fmt.Errorf("%w %s without serialized fields", errUnsupportedFieldKind, t)
+	}
+
+	return "(" + strings.Join(fields, ") || (") + ")", nil
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package unsupported
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+var (
+	// errFieldNotFound is returned when the path of a field does not match the InfraMachine spec.
+	errFieldNotFound = errors.New("field not found")
+
+	// errUnsupportedFieldKind is returned when the kind of a field can not be checked.
+	errUnsupportedFieldKind = errors.New("unsupported field kind")
+)
+
+// Field is a field of an InfraMachine spec that can not be converted to Machine API.
+type Field struct {
+	// Path is the path of the field within the InfraMachine spec, as JSON field names.
+	Path []string
+
+	// ErrorPath is the path reported by the conversion error, when it differs from Path.
+	ErrorPath []string
+
+	// Message explains that the field is not supported.
+	Message string
+
+	// SkipAdmission is true when the field is only rejected by the conversion,
+	// and not denied by the ValidatingAdmissionPolicy.
+	SkipAdmission bool
+}
+
+// Rules is the declarative list of unsupported fields of the InfraMachine spec of a platform.
+// The same list drives the conversion checks and the ValidatingAdmissionPolicies.
+type Rules struct {
+	// Spec is an empty InfraMachine spec, used to resolve the Go types of the fields.
+	Spec any
+
+	// Group and Version are the API group and version of the InfraMachine.
+	Group   string
+	Version string
+
+	// InfraMachineResource and InfraMachineTemplateResource are the plural resource names
+	// of the InfraMachine and InfraMachineTemplate.
+	InfraMachineResource         string
+	InfraMachineTemplateResource string
+
+	// Fields are the unsupported fields.
+	Fields []Field
+}
+
+// Validate returns an error for every unsupported field set in the given InfraMachine spec.
+// The spec must be of the same type as the Spec of the rules.
+func (r Rules) Validate(fldPath *field.Path, spec any) field.ErrorList {
+	errs := field.ErrorList{}
+
+	for _, f := range r.Fields {
+		errorPath := f.Path
+		if len(f.ErrorPath) > 0 {
+			errorPath = f.ErrorPath
+		}
+
+		value, isSet, err := lookup(reflect.ValueOf(spec), f.Path)
+		if err != nil {
+			errs = append(errs, field.InternalError(fldPath.Child(errorPath[0], errorPath[1:]...), err))
+			continue
+		}
+
+		if isSet {
+			errs = append(errs, field.Invalid(fldPath.Child(errorPath[0], errorPath[1:]...), value, f.Message))
+		}
+	}
+
+	return errs
+}
+
+// lookup returns the value of the field at path within v, and whether it is set.
+// A pointer is set when it is not nil, strings, slices and maps are set when they are not empty,
+// and structs, booleans and numbers are set when they are not the zero value.
+func lookup(v reflect.Value, path []string) (any, bool, error) {
+	for i, name := range path {
+		if v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return nil, false, nil
+			}
+
+			v = v.Elem()
+		}
+
+		index, err := fieldIndex(v.Type(), name)
+		if err != nil {
+			return nil, false, fmt.Errorf("%s: %w", strings.Join(path[:i+1], "."), err)
+		}
+
+		v = v.Field(index)
+	}
+
+	switch v.Kind() { //nolint:exhaustive
+	case reflect.Pointer:
+		return v.Interface(), !v.IsNil(), nil
+	case reflect.String, reflect.Slice, reflect.Map:
+		return v.Interface(), v.Len() > 0, nil
+	case reflect.Struct, reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return v.Interface(), !v.IsZero(), nil
+	default:
+		return nil, false, fmt.Errorf("%s: %w %s", strings.Join(path, "."), errUnsupportedFieldKind, v.Kind())
+	}
+}
+
+// fieldIndex returns the index of the struct field with the given JSON name.
+func fieldIndex(t reflect.Type, name string) (int, error) {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return 0, fmt.Errorf("%w: %s is not a struct", errFieldNotFound, t)
+	}
+
+	for i := range t.NumField() {
+		jsonName, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if jsonName == name {
+			return i, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: %s has no field %q", errFieldNotFound, t, name)
+}
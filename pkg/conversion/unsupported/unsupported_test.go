@@ -0,0 +1,179 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package unsupported_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/cluster-capi-operator/pkg/conversion/capi2mapi"
+	"github.com/openshift/cluster-capi-operator/pkg/conversion/unsupported"
+
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+)
+
+type testNested struct {
+	Proxy *string `json:"proxy,omitempty"`
+}
+
+type testOptions struct {
+	Enabled bool   `json:"enabled,omitempty"`
+	Size    int32  `json:"size,omitempty"`
+	Mode    string `json:"mode,omitempty"`
+}
+
+type testSpec struct {
+	Name    string            `json:"name,omitempty"`
+	List    []string          `json:"list,omitempty"`
+	Tags    map[string]string `json:"tags,omitempty"`
+	Nested  *testNested       `json:"nested,omitempty"`
+	Options testOptions       `json:"options,omitempty"`
+	Count   int32             `json:"count,omitempty"`
+	Handler func()            `json:"handler,omitempty"`
+}
+
+var _ = Describe("Unsupported Fields", func() {
+	rules := unsupported.Rules{
+		Spec:                         testSpec{},
+		Group:                        "infrastructure.cluster.x-k8s.io",
+		Version:                      "v1beta2",
+		InfraMachineResource:         "testmachines",
+		InfraMachineTemplateResource: "testmachinetemplates",
+		Fields: []unsupported.Field{
+			{Path: []string{"name"}, Message: "name is not supported"},
+			{Path: []string{"list"}, Message: "list is not supported"},
+			{Path: []string{"tags"}, Message: "tags are not supported", SkipAdmission: true},
+			{Path: []string{"nested", "proxy"}, Message: "nested proxy is not supported"},
+			{Path: []string{"options"}, Message: "options are not supported"},
+			{Path: []string{"count"}, ErrorPath: []string{"replicaCount"}, Message: "count is not supported"},
+		},
+	}
+
+	fldPath := field.NewPath("spec")
+
+	DescribeTable("should report the unsupported fields which are set",
+		func(spec testSpec, expectedErrors []string) {
+			errs := rules.Validate(fldPath, spec)
+
+			errStrings := []string{}
+			for _, err := range errs {
+				errStrings = append(errStrings, err.Error())
+			}
+
+			Expect(errStrings).To(ConsistOf(expectedErrors))
+		},
+		Entry("with no fields set", testSpec{}, []string{}),
+		Entry("with empty values", testSpec{Name: "", List: []string{}, Nested: &testNested{}}, []string{}),
+		Entry("with a string", testSpec{Name: "foo"}, []string{`spec.name: Invalid value: "foo": name is not supported`}),
+		Entry("with a list", testSpec{List: []string{"foo"}}, []string{`spec.list: Invalid value: []string{"foo"}: list is not supported`}),
+		Entry("with a map", testSpec{Tags: map[string]string{"foo": "bar"}}, []string{`spec.tags: Invalid value: map[string]string{"foo":"bar"}: tags are not supported`}),
+		Entry("with a nested pointer", testSpec{Nested: &testNested{Proxy: ptr.To("")}}, []string{`spec.nested.proxy: Invalid value: "": nested proxy is not supported`}),
+		Entry("with a struct", testSpec{Options: testOptions{Enabled: true}},
+			[]string{`spec.options: Invalid value: unsupported_test.testOptions{Enabled:true, Size:0, Mode:""}: options are not supported`}),
+		Entry("with a struct of zero values", testSpec{Options: testOptions{Enabled: false, Size: 0, Mode: ""}}, []string{}),
+		Entry("with a number, reported at the error path", testSpec{Count: 3}, []string{`spec.replicaCount: Invalid value: 3: count is not supported`}),
+	)
+
+	It("should report an internal error for an unknown field", func() {
+		errs := unsupported.Rules{Spec: testSpec{}, Fields: []unsupported.Field{{Path: []string{"unknown"}}}}.Validate(fldPath, testSpec{})
+
+		Expect(errs).To(ConsistOf(HaveField("Type", field.ErrorTypeInternal)))
+	})
+
+	Context("when generating the admission policies", func() {
+		var (
+			policies []*admissionregistrationv1beta1.ValidatingAdmissionPolicy
+			bindings []*admissionregistrationv1beta1.ValidatingAdmissionPolicyBinding
+		)
+
+		BeforeEach(func() {
+			var err error
+			policies, bindings, err = rules.ValidatingAdmissionPolicies("openshift-cluster-api")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should generate a policy for the InfraMachines and InfraMachineTemplates", func() {
+			Expect(policies).To(ConsistOf(
+				HaveField("Spec.MatchConstraints.ResourceRules", ConsistOf(HaveField("Resources", ConsistOf("testmachines")))),
+				HaveField("Spec.MatchConstraints.ResourceRules", ConsistOf(HaveField("Resources", ConsistOf("testmachinetemplates")))),
+			))
+		})
+
+		It("should bind every policy to the namespace", func() {
+			Expect(bindings).To(HaveLen(2))
+
+			for i, binding := range bindings {
+				Expect(binding.Spec.PolicyName).To(Equal(policies[i].Name))
+				Expect(binding.Spec.ValidationActions).To(ConsistOf(admissionregistrationv1beta1.Deny))
+				Expect(binding.Spec.MatchResources.NamespaceSelector.MatchLabels).To(HaveKeyWithValue("kubernetes.io/metadata.name", "openshift-cluster-api"))
+			}
+		})
+
+		It("should generate the validations of the InfraMachine spec", func() {
+			Expect(policies[0].Spec.Validations).To(Equal([]admissionregistrationv1beta1.Validation{
+				{
+					Expression: "!(has(object.spec.name) && size(object.spec.name) > 0)",
+					Message:    "spec.name: name is not supported",
+					Reason:     ptr.To(metav1.StatusReasonInvalid),
+				},
+				{
+					Expression: "!(has(object.spec.list) && size(object.spec.list) > 0)",
+					Message:    "spec.list: list is not supported",
+					Reason:     ptr.To(metav1.StatusReasonInvalid),
+				},
+				{
+					Expression: "!(has(object.spec.nested) && has(object.spec.nested.proxy))",
+					Message:    "spec.nested.proxy: nested proxy is not supported",
+					Reason:     ptr.To(metav1.StatusReasonInvalid),
+				},
+				{
+					Expression: "!(has(object.spec.options) && ((has(object.spec.options.enabled) && object.spec.options.enabled == true) || " +
+						"(has(object.spec.options.size) && object.spec.options.size != 0) || " +
+						"(has(object.spec.options.mode) && object.spec.options.mode != \"\")))",
+					Message: "spec.options: options are not supported",
+					Reason:  ptr.To(metav1.StatusReasonInvalid),
+				},
+				{
+					Expression: "!(has(object.spec.count) && (object.spec.count != 0))",
+					Message:    "spec.count: count is not supported",
+					Reason:     ptr.To(metav1.StatusReasonInvalid),
+				},
+			}))
+		})
+
+		It("should generate the validations of the InfraMachineTemplate spec", func() {
+			Expect(policies[1].Spec.Validations).To(ContainElement(admissionregistrationv1beta1.Validation{
+				Expression: "!(has(object.spec.template.spec.nested) && has(object.spec.template.spec.nested.proxy))",
+				Message:    "spec.template.spec.nested.proxy: nested proxy is not supported",
+				Reason:     ptr.To(metav1.StatusReasonInvalid),
+			}))
+		})
+
+		It("should fail for a field of an unsupported kind", func() {
+			_, _, err := unsupported.Rules{Spec: testSpec{}, Fields: []unsupported.Field{{Path: []string{"handler"}}}}.ValidatingAdmissionPolicies("openshift-cluster-api")
+			Expect(err).To(MatchError(ContainSubstring("unsupported field kind")))
+		})
+	})
+
+	It("should generate the admission policies of every AWS unsupported field", func() {
+		policies, _, err := capi2mapi.AWSUnsupportedFields.ValidatingAdmissionPolicies("openshift-cluster-api")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(policies).To(HaveEach(HaveField("Spec.Validations", HaveLen(len(capi2mapi.AWSUnsupportedFields.Fields)-1))))
+	})
+})
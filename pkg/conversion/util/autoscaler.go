@@ -0,0 +1,88 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package util
+
+import (
+	capiv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const (
+	// MAPIAutoscalerMinSizeAnnotation is the annotation set by a MachineAutoscaler on a MAPI MachineSet
+	// to define the minimum size of the node group.
+	MAPIAutoscalerMinSizeAnnotation = "machine.openshift.io/cluster-api-autoscaler-node-group-min-size"
+
+	// MAPIAutoscalerMaxSizeAnnotation is the annotation set by a MachineAutoscaler on a MAPI MachineSet
+	// to define the maximum size of the node group.
+	MAPIAutoscalerMaxSizeAnnotation = "machine.openshift.io/cluster-api-autoscaler-node-group-max-size"
+
+	// MAPICPUAnnotation is the annotation set by the MAPI MachineSet controllers with the number of vCPUs
+	// provided by the MachineSet's instance type. It is used by the autoscaler to scale from zero.
+	MAPICPUAnnotation = "machine.openshift.io/vCPU"
+
+	// MAPIMemoryAnnotation is the annotation set by the MAPI MachineSet controllers with the memory, in MiB,
+	// provided by the MachineSet's instance type. It is used by the autoscaler to scale from zero.
+	MAPIMemoryAnnotation = "machine.openshift.io/memoryMb"
+
+	// MAPIGPUAnnotation is the annotation set by the MAPI MachineSet controllers with the number of GPUs
+	// provided by the MachineSet's instance type. It is used by the autoscaler to scale from zero.
+	MAPIGPUAnnotation = "machine.openshift.io/GPU"
+
+	// MAPIMaxPodsAnnotation is the annotation set by the MAPI MachineSet controllers with the maximum number of pods
+	// the Nodes of the MachineSet can run. It is used by the autoscaler to scale from zero.
+	MAPIMaxPodsAnnotation = "machine.openshift.io/maxPods"
+
+	// CAPIAutoscalerMinSizeAnnotation is the CAPI equivalent of MAPIAutoscalerMinSizeAnnotation.
+	CAPIAutoscalerMinSizeAnnotation = capiv1.AutoscalerMinSizeAnnotation
+
+	// CAPIAutoscalerMaxSizeAnnotation is the CAPI equivalent of MAPIAutoscalerMaxSizeAnnotation.
+	CAPIAutoscalerMaxSizeAnnotation = capiv1.AutoscalerMaxSizeAnnotation
+
+	// CAPICPUCapacityAnnotation is the annotation read by the autoscaler to determine the CPU capacity
+	// of a CAPI MachineSet when scaling from zero.
+	// See https://github.com/kubernetes/autoscaler/blob/master/cluster-autoscaler/cloudprovider/clusterapi/README.md#scale-from-zero-support
+	CAPICPUCapacityAnnotation = "capacity.cluster-autoscaler.kubernetes.io/cpu"
+
+	// CAPIMemoryCapacityAnnotation is the annotation read by the autoscaler to determine the memory capacity
+	// of a CAPI MachineSet when scaling from zero. The value is a resource quantity.
+	CAPIMemoryCapacityAnnotation = "capacity.cluster-autoscaler.kubernetes.io/memory"
+
+	// CAPIGPUCountCapacityAnnotation is the annotation read by the autoscaler to determine the number of GPUs
+	// of a CAPI MachineSet when scaling from zero.
+	CAPIGPUCountCapacityAnnotation = "capacity.cluster-autoscaler.kubernetes.io/gpu-count"
+
+	// CAPIGPUTypeCapacityAnnotation is the annotation read by the autoscaler to determine the type of GPUs
+	// of a CAPI MachineSet when scaling from zero.
+	CAPIGPUTypeCapacityAnnotation = "capacity.cluster-autoscaler.kubernetes.io/gpu-type"
+
+	// CAPIMaxPodsCapacityAnnotation is the annotation read by the autoscaler to determine the maximum number of pods
+	// of a CAPI MachineSet when scaling from zero.
+	CAPIMaxPodsCapacityAnnotation = "capacity.cluster-autoscaler.kubernetes.io/maxPods"
+
+	// DefaultGPUType is the GPU resource name assumed by the OpenShift autoscaler for MAPI MachineSets.
+	// MAPI has no way to express the GPU type, so this is the only type that can be converted losslessly.
+	DefaultGPUType = "nvidia.com/gpu"
+)
+
+// MAPIToCAPIAutoscalerAnnotations maps the MAPI autoscaler annotations whose values can be copied verbatim
+// to their CAPI equivalents.
+// CPU, memory and GPU annotations require a value translation and so are handled separately by the converters.
+func MAPIToCAPIAutoscalerAnnotations() map[string]string {
+	return map[string]string{
+		MAPIAutoscalerMinSizeAnnotation: CAPIAutoscalerMinSizeAnnotation,
+		MAPIAutoscalerMaxSizeAnnotation: CAPIAutoscalerMaxSizeAnnotation,
+		MAPIMaxPodsAnnotation:           CAPIMaxPodsCapacityAnnotation,
+	}
+}
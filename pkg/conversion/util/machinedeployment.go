@@ -0,0 +1,39 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package util
+
+const (
+	// MachineDeploymentAnnotation is the annotation set on a MAPI MachineSet to opt it into being
+	// represented as a CAPI MachineDeployment rather than a CAPI MachineSet.
+	// The only supported value is "true".
+	MachineDeploymentAnnotation = "machine.openshift.io/cluster-api-machine-deployment"
+
+	// MachineDeploymentMaxSurgeAnnotation is the annotation set on a MAPI MachineSet to configure
+	// the maxSurge of the rolling update strategy of the CAPI MachineDeployment.
+	// The value is either an absolute number or a percentage.
+	MachineDeploymentMaxSurgeAnnotation = "machine.openshift.io/cluster-api-machine-deployment-max-surge"
+
+	// MachineDeploymentMaxUnavailableAnnotation is the annotation set on a MAPI MachineSet to configure
+	// the maxUnavailable of the rolling update strategy of the CAPI MachineDeployment.
+	// The value is either an absolute number or a percentage.
+	MachineDeploymentMaxUnavailableAnnotation = "machine.openshift.io/cluster-api-machine-deployment-max-unavailable"
+)
+
+// IsMachineDeploymentEnabled determines whether the annotations of a MAPI MachineSet opt it into
+// being represented as a CAPI MachineDeployment.
+func IsMachineDeploymentEnabled(annotations map[string]string) bool {
+	return annotations[MachineDeploymentAnnotation] == "true"
+}
@@ -46,6 +46,10 @@ const (
 
 	// ReasonSyncFailed is the reason for the condition when the operator failed to sync resources.
 	ReasonSyncFailed = "SyncingFailed"
+
+	// controllerUpgradeableConditionSuffix is the suffix of the Upgradeable conditions of the single controllers,
+	// e.g. CapiInstallerControllerUpgradeable.
+	controllerUpgradeableConditionSuffix = "ControllerUpgradeable"
 )
 
 // ClusterOperatorStatusClient is a client for managing the status of the ClusterOperator object.
@@ -75,7 +79,7 @@ func (r *ClusterOperatorStatusClient) SetStatusAvailable(ctx context.Context, av
 		NewClusterOperatorStatusCondition(configv1.OperatorAvailable, configv1.ConditionTrue, ReasonAsExpected, availableConditionMsg),
 		NewClusterOperatorStatusCondition(configv1.OperatorProgressing, configv1.ConditionFalse, ReasonAsExpected, ""),
 		NewClusterOperatorStatusCondition(configv1.OperatorDegraded, configv1.ConditionFalse, ReasonAsExpected, ""),
-		NewClusterOperatorStatusCondition(configv1.OperatorUpgradeable, configv1.ConditionTrue, ReasonAsExpected, ""),
+		UpgradeableCondition(co.Status.Conditions),
 	}
 
 	if co, shouldUpdate := clusterObjectNeedsUpdating(co, conds, r.operandVersions(), r.relatedObjects()); shouldUpdate {
@@ -127,6 +131,18 @@ func (r *ClusterOperatorStatusClient) SetStatusDegraded(ctx context.Context, rec
 	return nil
 }
 
+// UpgradeableCondition returns the Upgradeable condition of the ClusterOperator given its current conditions.
+// It is False while the Upgradeable condition of any single controller is False, e.g. while a debugging override is active.
+func UpgradeableCondition(conditions []configv1.ClusterOperatorStatusCondition) configv1.ClusterOperatorStatusCondition {
+	for _, c := range conditions {
+		if strings.HasSuffix(string(c.Type), controllerUpgradeableConditionSuffix) && c.Status == configv1.ConditionFalse {
+			return NewClusterOperatorStatusCondition(configv1.OperatorUpgradeable, configv1.ConditionFalse, c.Reason, c.Message)
+		}
+	}
+
+	return NewClusterOperatorStatusCondition(configv1.OperatorUpgradeable, configv1.ConditionTrue, ReasonAsExpected, "")
+}
+
 // GetOrCreateClusterOperator is responsible for fetching the cluster operator should it exist,
 // or creating a new cluster operator if it does not already exist.
 func (r *ClusterOperatorStatusClient) GetOrCreateClusterOperator(ctx context.Context) (*configv1.ClusterOperator, error) {
@@ -61,6 +61,12 @@ var (
 	// fakeMachineSetCRD is a fake MachineSet CRD.
 	fakeMachineSetCRD = generateCRD(clusterGroupVersion.WithKind(fakeMachineSetKind))
 
+	// fakeMachineDeploymentKind is the kind for the MachineDeployment.
+	fakeMachineDeploymentKind = "MachineDeployment"
+
+	// fakeMachineDeploymentCRD is a fake MachineDeployment CRD.
+	fakeMachineDeploymentCRD = generateCRD(clusterGroupVersion.WithKind(fakeMachineDeploymentKind))
+
 	// v1beta2InfrastructureGroupVersion is a v1beta2 group version used for infrastructure objects.
 	v1beta2InfrastructureGroupVersion = schema.GroupVersion{Group: "infrastructure.cluster.x-k8s.io", Version: "v1beta2"}
 
@@ -70,6 +76,12 @@ var (
 	// fakeAWSClusterCRD is a fake AWSCluster CRD.
 	fakeAWSClusterCRD = generateCRD(v1beta2InfrastructureGroupVersion.WithKind(fakeAWSClusterKind))
 
+	// fakeAWSMachineKind is the kind for the AWSMachine.
+	fakeAWSMachineKind = "AWSMachine"
+
+	// fakeAWSMachineCRD is a fake AWSMachine CRD.
+	fakeAWSMachineCRD = generateCRD(v1beta2InfrastructureGroupVersion.WithKind(fakeAWSMachineKind))
+
 	// fakeAWSMachineTemplateKind is the kind for the AWSMachineTemplate.
 	fakeAWSMachineTemplateKind = "AWSMachineTemplate"
 
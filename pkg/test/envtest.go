@@ -22,6 +22,7 @@ import (
 
 	machinev1 "github.com/openshift/api/machine/v1"
 	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	migrationv1alpha1 "github.com/openshift/cluster-capi-operator/pkg/apis/migration/v1alpha1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -42,6 +43,7 @@ func init() {
 	utilruntime.Must(machinev1.Install(scheme.Scheme))
 	utilruntime.Must(machinev1beta1.Install(scheme.Scheme))
 	utilruntime.Must(clusteroperatorv1.Install(scheme.Scheme))
+	utilruntime.Must(migrationv1alpha1.AddToScheme(scheme.Scheme))
 	utilruntime.Must(apiextensionsv1.AddToScheme(scheme.Scheme))
 	utilruntime.Must(awsv1.AddToScheme(scheme.Scheme))
 	utilruntime.Must(azurev1.AddToScheme(scheme.Scheme))
@@ -61,7 +63,9 @@ func StartEnvTest(testEnv *envtest.Environment) (*rest.Config, client.Client, er
 		fakeClusterCRD,
 		fakeMachineCRD,
 		fakeMachineSetCRD,
+		fakeMachineDeploymentCRD,
 		fakeAWSClusterCRD,
+		fakeAWSMachineCRD,
 		fakeAWSMachineTemplateCRD,
 		fakeAzureClusterCRD,
 		fakeGCPClusterCRD,
@@ -78,6 +82,7 @@ func StartEnvTest(testEnv *envtest.Environment) (*rest.Config, client.Client, er
 			path.Join(root, "vendor", "github.com", "openshift", "api", "machine", "v1beta1", "zz_generated.crd-manifests", "0000_10_machine-api_01_machinesets-CustomNoUpgrade.crd.yaml"),
 			path.Join(root, "vendor", "github.com", "openshift", "api", "machine", "v1beta1", "zz_generated.crd-manifests", "0000_10_machine-api_01_machines-CustomNoUpgrade.crd.yaml"),
 			path.Join(root, "vendor", "github.com", "openshift", "api", "config", "v1", "zz_generated.crd-manifests", "0000_00_cluster-version-operator_01_clusteroperators.crd.yaml"),
+			path.Join(root, "manifests", "0000_30_cluster-api_02_machineapimigrations.crd.yaml"),
 		},
 		ErrorIfPathMissing: true,
 	}
@@ -0,0 +1,118 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package util
+
+import (
+	"fmt"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	machinev1applyconfigs "github.com/openshift/client-go/machine/applyconfigurations/machine/v1beta1"
+	consts "github.com/openshift/cluster-capi-operator/pkg/controllers"
+	"github.com/openshift/cluster-capi-operator/pkg/conversion/report"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxConversionMessageLength bounds the length of the LosslessConversion condition message,
+// the full conversion report is published in the migration readiness ConfigMap.
+const maxConversionMessageLength = 2048
+
+// SetLastTransitionTime determines if the last transition time should be set or updated for a given condition type.
+func SetLastTransitionTime(condType machinev1beta1.ConditionType, conditions []machinev1beta1.Condition, conditionAc *machinev1applyconfigs.ConditionApplyConfiguration) {
+	for _, condition := range conditions {
+		if condition.Type == condType {
+			if !hasSameState(&condition, conditionAc) {
+				conditionAc.WithLastTransitionTime(metav1.Now())
+
+				return
+			}
+
+			conditionAc.WithLastTransitionTime(condition.LastTransitionTime)
+
+			return
+		}
+	}
+	// Condition does not exist; set the transition time
+	conditionAc.WithLastTransitionTime(metav1.Now())
+}
+
+// LosslessConversionCondition returns the LosslessConversion condition for the outcome of a conversion.
+// The message holds the JSON encoded report entries, or the conversion error when the report is empty,
+// bounded to maxConversionMessageLength. The last transition time is carried over from the existing conditions.
+func LosslessConversionCondition(conditions []machinev1beta1.Condition, conversionReport report.Report, conversionErr error) (*machinev1applyconfigs.ConditionApplyConfiguration, error) {
+	conditionAc := machinev1applyconfigs.Condition().
+		WithType(consts.LosslessConversionCondition)
+
+	switch {
+	case conversionErr != nil || conversionReport.HasSeverity(report.SeverityError):
+		conditionAc.WithStatus(corev1.ConditionFalse).
+			WithReason(consts.ReasonConversionFailed).
+			WithSeverity(machinev1beta1.ConditionSeverityError)
+	case len(conversionReport) > 0:
+		conditionAc.WithStatus(corev1.ConditionFalse).
+			WithReason(consts.ReasonConversionLossy).
+			WithSeverity(machinev1beta1.ConditionSeverityWarning)
+	default:
+		conditionAc.WithStatus(corev1.ConditionTrue).
+			WithReason(consts.ReasonConversionLossless).
+			WithSeverity(machinev1beta1.ConditionSeverityNone)
+	}
+
+	switch {
+	case len(conversionReport) > 0:
+		message, err := conversionReportMessage(conversionReport)
+		if err != nil {
+			return nil, err
+		}
+
+		conditionAc.WithMessage(message)
+	case conversionErr != nil:
+		message := conversionErr.Error()
+		if len(message) > maxConversionMessageLength {
+			message = message[:maxConversionMessageLength] + "..."
+		}
+
+		conditionAc.WithMessage(message)
+	}
+
+	SetLastTransitionTime(consts.LosslessConversionCondition, conditions, conditionAc)
+
+	return conditionAc, nil
+}
+
+// conversionReportMessage returns the JSON encoded entries of the report that fit within maxConversionMessageLength,
+// followed by the number of entries left out and where to find them.
+func conversionReportMessage(conversionReport report.Report) (string, error) {
+	data, omitted, err := conversionReport.MarshalTruncated(maxConversionMessageLength)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal conversion report: %w", err)
+	}
+
+	if omitted == 0 {
+		return string(data), nil
+	}
+
+	return fmt.Sprintf("%s and %d more entries, see the %s ConfigMap in the %s namespace for the full report",
+		data, omitted, consts.MigrationReadinessConfigMapName, consts.DefaultManagedNamespace), nil
+}
+
+// hasSameState returns true if a condition has the same state as a condition
+// apply config; state is defined by the union of following fields: Type,
+// Status.
+func hasSameState(i *machinev1beta1.Condition, j *machinev1applyconfigs.ConditionApplyConfiguration) bool {
+	return i.Type == *j.Type &&
+		i.Status == *j.Status
+}
@@ -32,3 +32,16 @@ var (
 	// RetryPeriod is the default duration for the leader election retrial.
 	RetryPeriod = metav1.Duration{Duration: 26 * time.Second}
 )
+
+// The durations for the leader election operations on single replica topologies,
+// which tolerate the API server being unavailable during its rollouts.
+//
+//nolint:gochecknoglobals
+var (
+	// SingleReplicaLeaseDuration is the duration for the leader election lease on single replica topologies.
+	SingleReplicaLeaseDuration = metav1.Duration{Duration: 270 * time.Second}
+	// SingleReplicaRenewDeadline is the duration for the leader renewal on single replica topologies.
+	SingleReplicaRenewDeadline = metav1.Duration{Duration: 240 * time.Second}
+	// SingleReplicaRetryPeriod is the duration for the leader election retrial on single replica topologies.
+	SingleReplicaRetryPeriod = metav1.Duration{Duration: 60 * time.Second}
+)
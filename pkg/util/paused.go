@@ -0,0 +1,125 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"context"
+	"fmt"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	consts "github.com/openshift/cluster-capi-operator/pkg/controllers"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// IsCAPIPaused returns true when the CAPI resources mirroring a MAPI resource with the given authoritative API must be paused.
+func IsCAPIPaused(authoritativeAPI machinev1beta1.MachineAuthority) bool {
+	return authoritativeAPI != machinev1beta1.MachineAuthorityClusterAPI
+}
+
+// IsMAPIPaused returns true when a MAPI resource with the given authoritative API must be paused.
+func IsMAPIPaused(authoritativeAPI machinev1beta1.MachineAuthority) bool {
+	return authoritativeAPI == machinev1beta1.MachineAuthorityClusterAPI
+}
+
+// PausedConditionReason returns the reason and message of the Paused condition
+// set on the MAPI and CAPI resources for the given authoritative API.
+func PausedConditionReason(authoritativeAPI machinev1beta1.MachineAuthority) (string, string) {
+	if authoritativeAPI == machinev1beta1.MachineAuthorityClusterAPI {
+		return consts.ReasonAuthoritativeAPIClusterAPI, "Cluster API is authoritative, the Machine API resources are paused"
+	}
+
+	return consts.ReasonAuthoritativeAPIMachineAPI, "Machine API is authoritative, the Cluster API resources are paused"
+}
+
+// SetPausedAnnotation adds the given pause annotation to the object when paused is true, and removes it otherwise.
+// It returns true when the annotations of the object were changed.
+func SetPausedAnnotation(obj metav1.Object, annotation string, paused bool) bool {
+	annotations := obj.GetAnnotations()
+	_, isPaused := annotations[annotation]
+
+	switch {
+	case paused && !isPaused:
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+
+		annotations[annotation] = ""
+	case !paused && isPaused:
+		delete(annotations, annotation)
+	default:
+		return false
+	}
+
+	obj.SetAnnotations(annotations)
+
+	return true
+}
+
+// EnsureCAPIPaused pauses or unpauses a CAPI resource mirroring a MAPI resource with the given authoritative API.
+// The cluster.x-k8s.io/paused annotation is set on the resource and, when the resource has conditions,
+// its Paused condition is updated to show which API is paused.
+func EnsureCAPIPaused(ctx context.Context, c client.Client, obj client.Object, authoritativeAPI machinev1beta1.MachineAuthority) error {
+	paused := IsCAPIPaused(authoritativeAPI)
+
+	patchBase := client.MergeFrom(obj.DeepCopyObject().(client.Object)) //nolint:forcetypeassert
+
+	if SetPausedAnnotation(obj, capiv1beta1.PausedAnnotation, paused) {
+		if err := c.Patch(ctx, obj, patchBase); err != nil {
+			return fmt.Errorf("failed to patch paused annotation: %w", err)
+		}
+	}
+
+	setter, ok := obj.(conditions.Setter)
+	if !ok {
+		return nil
+	}
+
+	objCopy := obj.DeepCopyObject().(client.Object) //nolint:forcetypeassert
+
+	reason, message := PausedConditionReason(authoritativeAPI)
+	if paused {
+		conditions.Set(setter, &capiv1beta1.Condition{
+			Type:    capiv1beta1.ConditionType(consts.PausedCondition),
+			Status:  corev1.ConditionTrue,
+			Reason:  reason,
+			Message: message,
+		})
+	} else {
+		conditions.MarkFalse(setter, capiv1beta1.ConditionType(consts.PausedCondition), reason, capiv1beta1.ConditionSeverityInfo, "%s", message)
+	}
+
+	statusPatch := client.MergeFrom(objCopy)
+
+	isRequired, err := IsPatchRequired(obj, statusPatch)
+	if err != nil {
+		return fmt.Errorf("failed to check if patch required: %w", err)
+	}
+
+	if !isRequired {
+		return nil
+	}
+
+	if err := c.Status().Patch(ctx, obj, statusPatch); err != nil {
+		return fmt.Errorf("failed to patch paused condition: %w", err)
+	}
+
+	return nil
+}
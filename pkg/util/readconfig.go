@@ -17,6 +17,7 @@ package util
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -31,40 +32,91 @@ func ReadImagesFile(imagesFile string) (map[string]string, error) {
 		return nil, fmt.Errorf("unable to read file %s: %w", imagesFile, err)
 	}
 
+	containerImages, err := ParseImages(jsonData)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse images file %s: %w", imagesFile, err)
+	}
+
+	return containerImages, nil
+}
+
+// ParseImages parses the JSON map of container images, keyed by component, e.g. from the images ConfigMap.
+func ParseImages(jsonData []byte) (map[string]string, error) {
 	containerImages := map[string]string{}
 	if err := json.Unmarshal(jsonData, &containerImages); err != nil {
-		return nil, fmt.Errorf("unable to unmarshal image names from file %s: %w", imagesFile, err)
+		return nil, fmt.Errorf("unable to unmarshal image names: %w", err)
 	}
 
 	return containerImages, nil
 }
 
-type provider struct {
-	Name string `json:"name"`
+var (
+	errProviderNameRequired    = errors.New("provider name is required")
+	errUnsupportedProviderType = errors.New("unsupported provider type, must be one of bootstrap, controlplane, ipam, addon or runtimeextension")
+	errDuplicateProvider       = errors.New("provider is listed more than once")
+)
+
+// Provider selects the transport ConfigMaps of a CAPI provider to install.
+type Provider struct {
+	// Type is the type of the provider, matching the provider.cluster.x-k8s.io/type label of its ConfigMaps.
+	Type string `yaml:"type"`
+
+	// Name is the name of the provider, matching the provider.cluster.x-k8s.io/name label of its ConfigMaps.
+	Name string `yaml:"name"`
+
+	// Version, when set, only selects the ConfigMaps whose provider.cluster.x-k8s.io/version label matches it.
+	Version string `yaml:"version,omitempty"`
 }
 
-// ReadProvidersFile reads the providers file and returns the map of supported providers.
-func ReadProvidersFile(providersFile string) (map[string]bool, error) {
+// ReadAdditionalProvidersFile reads the providers file and returns the CAPI providers
+// to install alongside the core and infrastructure providers.
+func ReadAdditionalProvidersFile(providersFile string) ([]Provider, error) {
 	yamlData, err := os.ReadFile(filepath.Clean(providersFile))
 	if err != nil {
 		return nil, fmt.Errorf("unable to read file %s: %w", providersFile, err)
 	}
 
-	providers := []provider{}
-	if err := yaml.Unmarshal(yamlData, &providers); err != nil {
-		return nil, fmt.Errorf("unable to unmarshal providers names from file %s: %w", providersFile, err)
+	providers, err := ParseAdditionalProviders(yamlData)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse providers file %s: %w", providersFile, err)
 	}
 
-	supportedProviders := map[string]bool{}
+	return providers, nil
+}
+
+// ParseAdditionalProviders parses the YAML list of CAPI providers to install alongside
+// the core and infrastructure providers, e.g. from the providers ConfigMap.
+func ParseAdditionalProviders(yamlData []byte) ([]Provider, error) {
+	providers := []Provider{}
+	if err := yaml.UnmarshalStrict(yamlData, &providers); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal providers: %w", err)
+	}
+
+	seen := map[string]bool{}
 
 	for _, p := range providers {
-		// Skip core cluster-api because it is not an infrastructure provider
-		if p.Name == "cluster-api" {
-			continue
+		switch {
+		case p.Name == "":
+			return nil, fmt.Errorf("invalid provider of type %q: %w", p.Type, errProviderNameRequired)
+		case !isAdditionalProviderType(p.Type):
+			return nil, fmt.Errorf("invalid provider %q of type %q: %w", p.Name, p.Type, errUnsupportedProviderType)
+		case seen[p.Type+"/"+p.Name]:
+			return nil, fmt.Errorf("invalid provider %q of type %q: %w", p.Name, p.Type, errDuplicateProvider)
 		}
 
-		supportedProviders[p.Name] = true
+		seen[p.Type+"/"+p.Name] = true
 	}
 
-	return supportedProviders, nil
+	return providers, nil
+}
+
+// isAdditionalProviderType returns true for the provider types which can be installed in addition to
+// the core provider and the infrastructure provider of the platform, which are always installed.
+func isAdditionalProviderType(providerType string) bool {
+	switch providerType {
+	case "bootstrap", "controlplane", "ipam", "addon", "runtimeextension":
+		return true
+	default:
+		return false
+	}
 }
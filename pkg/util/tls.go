@@ -0,0 +1,169 @@
+/*
+Copyright 2024 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package util
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	cliflag "k8s.io/component-base/cli/flag"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/library-go/pkg/crypto"
+)
+
+const (
+	apiServerResourceName = "cluster"
+)
+
+// TLSProfile is the TLS configuration components serving on the cluster should apply,
+// expressed in the format of the --tls-min-version and --tls-cipher-suites flags.
+type TLSProfile struct {
+	// MinVersion is the minimum TLS version, e.g. VersionTLS12.
+	MinVersion string
+	// CipherSuites are the IANA names of the cipher suites of the profile. They include the TLS 1.3 cipher suites
+	// of the Modern profile, which Go accepts but ignores, as TLS 1.3 cipher suites are not configurable.
+	CipherSuites []string
+}
+
+// GetTLSProfile returns the TLS profile configured on the cluster APIServer resource.
+// The Intermediate profile is returned if the resource does not exist or does not set a profile.
+func GetTLSProfile(ctx context.Context, cl client.Reader) (TLSProfile, error) {
+	apiServer := &configv1.APIServer{}
+
+	if err := cl.Get(ctx, client.ObjectKey{Name: apiServerResourceName}, apiServer); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return TLSProfile{}, fmt.Errorf("failed to get apiserver %q: %w", apiServerResourceName, err)
+		}
+	}
+
+	return NewTLSProfile(apiServer.Spec.TLSSecurityProfile), nil
+}
+
+// NewTLSProfile translates the given TLS security profile into a TLSProfile.
+// A nil or incomplete profile resolves to the Intermediate profile.
+func NewTLSProfile(profile *configv1.TLSSecurityProfile) TLSProfile {
+	spec := configv1.TLSProfiles[configv1.TLSProfileIntermediateType]
+
+	if profile != nil {
+		if profile.Type == configv1.TLSProfileCustomType {
+			if profile.Custom != nil {
+				spec = &profile.Custom.TLSProfileSpec
+			}
+		} else if predefined, ok := configv1.TLSProfiles[profile.Type]; ok {
+			spec = predefined
+		}
+	}
+
+	return TLSProfile{
+		MinVersion:   string(spec.MinTLSVersion),
+		CipherSuites: crypto.OpenSSLToIANACipherSuites(spec.Ciphers),
+	}
+}
+
+// TLSProfileReloader keeps the TLS options of the operator servers up to date with the TLS profile
+// of the cluster APIServer, so that a profile change applies to new connections without restarting the operator.
+type TLSProfileReloader struct {
+	// Client reads the APIServer.
+	Client client.Reader
+
+	// KeepMinVersion keeps the minimum TLS version of the server, e.g. when it was set on the command line.
+	KeepMinVersion bool
+
+	// KeepCipherSuites keeps the cipher suites of the server, e.g. when they were set on the command line.
+	KeepCipherSuites bool
+
+	mu           sync.RWMutex
+	loaded       bool
+	minVersion   uint16
+	cipherSuites []uint16
+}
+
+// TLSOpt is a TLS option of a server, applying the last TLS profile loaded to each new connection.
+// The server keeps its own TLS options until the profile is loaded.
+func (t *TLSProfileReloader) TLSOpt(cfg *tls.Config) {
+	cfg.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		t.mu.RLock()
+		defer t.mu.RUnlock()
+
+		connCfg := cfg.Clone()
+		connCfg.GetConfigForClient = nil
+
+		if t.loaded && !t.KeepMinVersion {
+			connCfg.MinVersion = t.minVersion
+		}
+
+		if t.loaded && !t.KeepCipherSuites {
+			connCfg.CipherSuites = t.cipherSuites
+		}
+
+		return connCfg, nil
+	}
+}
+
+// Reconcile loads the TLS profile of the cluster APIServer.
+func (t *TLSProfileReloader) Reconcile(ctx context.Context, _ ctrl.Request) (ctrl.Result, error) {
+	profile, err := GetTLSProfile(ctx, t.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	minVersion, err := cliflag.TLSVersion(profile.MinVersion)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("invalid TLS profile minimum version: %w", err)
+	}
+
+	cipherSuites, err := cliflag.TLSCipherSuites(profile.CipherSuites)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("invalid TLS profile cipher suites: %w", err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.loaded = true
+	t.minVersion = minVersion
+	t.cipherSuites = cipherSuites
+
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the reloader with the Manager.
+// It does not need leader election, as every replica of the operator serves with the TLS profile.
+func (t *TLSProfileReloader) SetupWithManager(mgr ctrl.Manager) error {
+	isClusterAPIServer := predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return obj.GetName() == apiServerResourceName
+	})
+
+	if err := ctrl.NewControllerManagedBy(mgr).
+		Named("tls-profile-reloader").
+		For(&configv1.APIServer{}, builder.WithPredicates(isClusterAPIServer)).
+		WithOptions(controller.Options{NeedLeaderElection: ptr.To(false)}).
+		Complete(t); err != nil {
+		return fmt.Errorf("failed to create controller: %w", err)
+	}
+
+	return nil
+}
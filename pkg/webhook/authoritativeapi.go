@@ -0,0 +1,199 @@
+// Copyright 2024 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+
+	"github.com/openshift/cluster-capi-operator/pkg/conversion"
+	conversionutil "github.com/openshift/cluster-capi-operator/pkg/conversion/util"
+)
+
+// AuthoritativeAPIWebhook validates changes of spec.authoritativeAPI on MAPI MachineSets and Machines.
+// A change is denied when the migration is not enabled or not supported on the platform, when the
+// resource is being migrated, or when the resource can not be converted to Cluster API.
+type AuthoritativeAPIWebhook struct {
+	// Infra is the cluster Infrastructure, used to dry-run the conversion.
+	Infra *configv1.Infrastructure
+
+	// Platform is the platform of the cluster.
+	Platform configv1.PlatformType
+
+	// MigrationEnabled is true when the MachineAPIMigration feature gate is enabled.
+	MigrationEnabled bool
+
+	// conversion holds the converters registered for the Platform,
+	// it is only set when the migration is enabled and supported on the Platform.
+	conversion *conversion.Platform
+}
+
+// SetupWebhookWithManager sets up the webhook with the manager.
+func (r *AuthoritativeAPIWebhook) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	if r.MigrationEnabled && conversion.IsPlatformSupported(r.Platform) {
+		platformConversion, err := conversion.PlatformFor(r.Platform)
+		if err != nil {
+			return fmt.Errorf("failed to get conversion for platform: %w", err)
+		}
+
+		r.conversion = &platformConversion
+	}
+
+	if err := ctrl.NewWebhookManagedBy(mgr).
+		WithValidator(r).
+		For(&machinev1beta1.MachineSet{}).
+		Complete(); err != nil {
+		return fmt.Errorf("failed to create machine set webhook: %w", err)
+	}
+
+	if err := ctrl.NewWebhookManagedBy(mgr).
+		WithValidator(r).
+		For(&machinev1beta1.Machine{}).
+		Complete(); err != nil {
+		return fmt.Errorf("failed to create machine webhook: %w", err)
+	}
+
+	return nil
+}
+
+var _ webhook.CustomValidator = &AuthoritativeAPIWebhook{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type.
+func (r *AuthoritativeAPIWebhook) ValidateCreate(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type.
+func (r *AuthoritativeAPIWebhook) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	switch newResource := newObj.(type) {
+	case *machinev1beta1.MachineSet:
+		oldResource, ok := oldObj.(*machinev1beta1.MachineSet)
+		if !ok {
+			panic("expected to get an of object of type v1beta1.MachineSet")
+		}
+
+		errs := r.validateAuthoritativeAPIChange(oldResource.Spec.AuthoritativeAPI, newResource.Spec.AuthoritativeAPI, oldResource.Status.AuthoritativeAPI,
+			func() error { return r.convertMachineSet(newResource) })
+		if len(errs) > 0 {
+			return nil, apierrors.NewInvalid(machinev1beta1.GroupVersion.WithKind("MachineSet").GroupKind(), newResource.Name, errs)
+		}
+	case *machinev1beta1.Machine:
+		oldResource, ok := oldObj.(*machinev1beta1.Machine)
+		if !ok {
+			panic("expected to get an of object of type v1beta1.Machine")
+		}
+
+		errs := r.validateAuthoritativeAPIChange(oldResource.Spec.AuthoritativeAPI, newResource.Spec.AuthoritativeAPI, oldResource.Status.AuthoritativeAPI,
+			func() error { return r.convertMachine(newResource) })
+		if len(errs) > 0 {
+			return nil, apierrors.NewInvalid(machinev1beta1.GroupVersion.WithKind("Machine").GroupKind(), newResource.Name, errs)
+		}
+	default:
+		panic("expected to get an of object of type v1beta1.MachineSet or v1beta1.Machine")
+	}
+
+	return nil, nil
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type.
+func (r *AuthoritativeAPIWebhook) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateAuthoritativeAPIChange validates a change of spec.authoritativeAPI from oldAPI to newAPI,
+// for a resource currently reporting the given status.authoritativeAPI.
+// The convert function dry-runs the conversion of the resource to Cluster API.
+func (r *AuthoritativeAPIWebhook) validateAuthoritativeAPIChange(oldAPI, newAPI, statusAPI machinev1beta1.MachineAuthority, convert func() error) field.ErrorList {
+	if oldAPI == newAPI {
+		return nil
+	}
+
+	fldPath := field.NewPath("spec", "authoritativeAPI")
+
+	switch {
+	case !r.MigrationEnabled:
+		return field.ErrorList{field.Forbidden(fldPath, "the MachineAPIMigration feature gate is not enabled")}
+	case r.conversion == nil:
+		return field.ErrorList{field.Forbidden(fldPath, fmt.Sprintf("migration is not supported on platform %q", r.Platform))}
+	case statusAPI == machinev1beta1.MachineAuthorityMigrating:
+		return field.ErrorList{field.Forbidden(fldPath, "the authoritative API can not be changed while the resource is migrating")}
+	case newAPI != machinev1beta1.MachineAuthorityClusterAPI:
+		return nil
+	}
+
+	if err := convert(); err != nil {
+		return toFieldErrors(fldPath, err)
+	}
+
+	return nil
+}
+
+// convertMachineSet dry-runs the conversion of a MAPI MachineSet to Cluster API.
+func (r *AuthoritativeAPIWebhook) convertMachineSet(mapiMachineSet *machinev1beta1.MachineSet) error {
+	converter := r.conversion.FromMAPIMachineSet(mapiMachineSet, r.Infra)
+
+	if conversionutil.IsMachineDeploymentEnabled(mapiMachineSet.Annotations) {
+		_, _, _, err := converter.ToMachineDeploymentAndMachineTemplate()
+
+		return err //nolint:wrapcheck
+	}
+
+	_, _, _, err := converter.ToMachineSetAndMachineTemplate() //nolint:dogsled
+
+	return err //nolint:wrapcheck
+}
+
+// convertMachine dry-runs the conversion of a MAPI Machine to Cluster API.
+func (r *AuthoritativeAPIWebhook) convertMachine(mapiMachine *machinev1beta1.Machine) error {
+	_, _, _, err := r.conversion.FromMAPIMachine(mapiMachine, r.Infra).ToMachineAndInfrastructureMachine() //nolint:dogsled
+
+	return err //nolint:wrapcheck
+}
+
+// toFieldErrors returns the field errors of a conversion error.
+// Errors that are not field errors are reported against the given path.
+func toFieldErrors(fldPath *field.Path, err error) field.ErrorList {
+	errs := []error{err}
+
+	var aggregate utilerrors.Aggregate
+	if errors.As(err, &aggregate) {
+		errs = aggregate.Errors()
+	}
+
+	fieldErrs := field.ErrorList{}
+
+	for _, err := range errs {
+		var fieldErr *field.Error
+		if errors.As(err, &fieldErr) {
+			fieldErrs = append(fieldErrs, fieldErr)
+			continue
+		}
+
+		fieldErrs = append(fieldErrs, field.Forbidden(fldPath, fmt.Sprintf("the resource can not be converted to Cluster API: %v", err)))
+	}
+
+	return fieldErrs
+}
@@ -0,0 +1,163 @@
+// Copyright 2024 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package webhook
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	configv1resourcebuilder "github.com/openshift/cluster-api-actuator-pkg/testutils/resourcebuilder/config/v1"
+	machinev1resourcebuilder "github.com/openshift/cluster-api-actuator-pkg/testutils/resourcebuilder/machine/v1beta1"
+
+	"github.com/openshift/cluster-capi-operator/pkg/conversion"
+)
+
+var _ = Describe("AuthoritativeAPI webhook", func() {
+	type authoritativeAPITableInput struct {
+		migrationEnabled       bool
+		platform               configv1.PlatformType
+		providerSpecBuilder    machinev1resourcebuilder.AWSProviderSpecBuilder
+		oldAuthoritativeAPI    machinev1beta1.MachineAuthority
+		newAuthoritativeAPI    machinev1beta1.MachineAuthority
+		statusAuthoritativeAPI machinev1beta1.MachineAuthority
+		expectedErrors         []string
+	}
+
+	infra := configv1resourcebuilder.Infrastructure().AsAWS("cluster", "us-east-1").WithInfrastructureName("cluster-foo").Build()
+	validProviderSpec := machinev1resourcebuilder.AWSProviderSpec().WithLoadBalancers(nil)
+
+	newWebhook := func(in authoritativeAPITableInput) *AuthoritativeAPIWebhook {
+		r := &AuthoritativeAPIWebhook{
+			Infra:            infra,
+			Platform:         in.platform,
+			MigrationEnabled: in.migrationEnabled,
+		}
+
+		if in.migrationEnabled && conversion.IsPlatformSupported(in.platform) {
+			platformConversion, err := conversion.PlatformFor(in.platform)
+			Expect(err).ToNot(HaveOccurred())
+
+			r.conversion = &platformConversion
+		}
+
+		return r
+	}
+
+	expectErrors := func(err error, expectedErrors []string) {
+		if len(expectedErrors) == 0 {
+			Expect(err).ToNot(HaveOccurred())
+			return
+		}
+
+		Expect(err).To(HaveOccurred())
+
+		for _, expected := range expectedErrors {
+			Expect(err.Error()).To(ContainSubstring(expected))
+		}
+	}
+
+	authoritativeAPIEntries := []TableEntry{
+		Entry("allows updates that do not change the authoritative API", authoritativeAPITableInput{
+			platform:            configv1.GCPPlatformType,
+			providerSpecBuilder: validProviderSpec,
+			oldAuthoritativeAPI: machinev1beta1.MachineAuthorityMachineAPI,
+			newAuthoritativeAPI: machinev1beta1.MachineAuthorityMachineAPI,
+		}),
+		Entry("denies changes when the migration is not enabled", authoritativeAPITableInput{
+			platform:            configv1.AWSPlatformType,
+			providerSpecBuilder: validProviderSpec,
+			oldAuthoritativeAPI: machinev1beta1.MachineAuthorityMachineAPI,
+			newAuthoritativeAPI: machinev1beta1.MachineAuthorityClusterAPI,
+			expectedErrors:      []string{"spec.authoritativeAPI: Forbidden: the MachineAPIMigration feature gate is not enabled"},
+		}),
+		Entry("denies changes on platforms where the migration is not supported", authoritativeAPITableInput{
+			migrationEnabled:    true,
+			platform:            configv1.GCPPlatformType,
+			providerSpecBuilder: validProviderSpec,
+			oldAuthoritativeAPI: machinev1beta1.MachineAuthorityMachineAPI,
+			newAuthoritativeAPI: machinev1beta1.MachineAuthorityClusterAPI,
+			expectedErrors:      []string{`spec.authoritativeAPI: Forbidden: migration is not supported on platform "GCP"`},
+		}),
+		Entry("denies changes while the resource is migrating", authoritativeAPITableInput{
+			migrationEnabled:       true,
+			platform:               configv1.AWSPlatformType,
+			providerSpecBuilder:    validProviderSpec,
+			oldAuthoritativeAPI:    machinev1beta1.MachineAuthorityClusterAPI,
+			newAuthoritativeAPI:    machinev1beta1.MachineAuthorityMachineAPI,
+			statusAuthoritativeAPI: machinev1beta1.MachineAuthorityMigrating,
+			expectedErrors:         []string{"spec.authoritativeAPI: Forbidden: the authoritative API can not be changed while the resource is migrating"},
+		}),
+		Entry("allows changes to Cluster API when the resource can be converted", authoritativeAPITableInput{
+			migrationEnabled:       true,
+			platform:               configv1.AWSPlatformType,
+			providerSpecBuilder:    validProviderSpec,
+			oldAuthoritativeAPI:    machinev1beta1.MachineAuthorityMachineAPI,
+			newAuthoritativeAPI:    machinev1beta1.MachineAuthorityClusterAPI,
+			statusAuthoritativeAPI: machinev1beta1.MachineAuthorityMachineAPI,
+		}),
+		Entry("denies changes to Cluster API with the field errors when the resource can not be converted", authoritativeAPITableInput{
+			migrationEnabled:       true,
+			platform:               configv1.AWSPlatformType,
+			providerSpecBuilder:    machinev1resourcebuilder.AWSProviderSpec(),
+			oldAuthoritativeAPI:    machinev1beta1.MachineAuthorityMachineAPI,
+			newAuthoritativeAPI:    machinev1beta1.MachineAuthorityClusterAPI,
+			statusAuthoritativeAPI: machinev1beta1.MachineAuthorityMachineAPI,
+			expectedErrors:         []string{"spec.providerSpec.value.loadBalancers: Invalid value", "loadBalancers are not supported"},
+		}),
+		Entry("allows changes back to Machine API without converting the resource", authoritativeAPITableInput{
+			migrationEnabled:       true,
+			platform:               configv1.AWSPlatformType,
+			providerSpecBuilder:    machinev1resourcebuilder.AWSProviderSpec(),
+			oldAuthoritativeAPI:    machinev1beta1.MachineAuthorityClusterAPI,
+			newAuthoritativeAPI:    machinev1beta1.MachineAuthorityMachineAPI,
+			statusAuthoritativeAPI: machinev1beta1.MachineAuthorityClusterAPI,
+		}),
+	}
+
+	DescribeTable("when updating a MachineSet", func(in authoritativeAPITableInput) {
+		oldMachineSet := machinev1resourcebuilder.MachineSet().
+			WithNamespace("openshift-machine-api").
+			WithName("foo").
+			WithProviderSpecBuilder(in.providerSpecBuilder).
+			Build()
+		oldMachineSet.Spec.AuthoritativeAPI = in.oldAuthoritativeAPI
+		oldMachineSet.Status.AuthoritativeAPI = in.statusAuthoritativeAPI
+
+		newMachineSet := oldMachineSet.DeepCopy()
+		newMachineSet.Spec.AuthoritativeAPI = in.newAuthoritativeAPI
+
+		_, err := newWebhook(in).ValidateUpdate(context.Background(), oldMachineSet, newMachineSet)
+		expectErrors(err, in.expectedErrors)
+	}, authoritativeAPIEntries)
+
+	DescribeTable("when updating a Machine", func(in authoritativeAPITableInput) {
+		oldMachine := machinev1resourcebuilder.Machine().
+			WithNamespace("openshift-machine-api").
+			WithName("foo").
+			WithProviderSpecBuilder(in.providerSpecBuilder).
+			Build()
+		oldMachine.Spec.AuthoritativeAPI = in.oldAuthoritativeAPI
+		oldMachine.Status.AuthoritativeAPI = in.statusAuthoritativeAPI
+
+		newMachine := oldMachine.DeepCopy()
+		newMachine.Spec.AuthoritativeAPI = in.newAuthoritativeAPI
+
+		_, err := newWebhook(in).ValidateUpdate(context.Background(), oldMachine, newMachine)
+		expectErrors(err, in.expectedErrors)
+	}, authoritativeAPIEntries)
+})